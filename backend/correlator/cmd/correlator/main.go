@@ -3,22 +3,32 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/ozlabs/mcpeeker/backend/correlator/internal/httpserver"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/buildinfo"
 	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/clickhouse"
 	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/config"
 	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/consumer"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/enforcement"
 	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/evidencepool"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/httpx"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/interceptor"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
 	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/metrics"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/notifier"
 	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/registry"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const (
@@ -28,25 +38,47 @@ const (
 )
 
 func main() {
-	log.Println("Starting MCPeeker Correlator Service...")
-
 	// Load configuration
 	cfg, err := loadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		// No logger yet (config failed to load), fall back to a bootstrap one.
+		logging.New(logging.Config{Name: "correlator"}).Error("failed to load configuration", "error", err)
+		os.Exit(1)
 	}
 
+	logger := logging.New(logging.Config{
+		Name:            "correlator",
+		Level:           cfg.LogLevel,
+		Format:          cfg.LogFormat,
+		DebugSampleRate: 10,
+	})
+	logger.Info("Starting MCPeeker Correlator Service...", "build", buildinfo.String())
+	metrics.RecordBuildInfo(buildinfo.Version, buildinfo.GitCommit, buildinfo.BuildDate, buildinfo.GoVersion)
+
+	watcher := config.NewWatcherWithSnapshot(configPath(), cfg, logger.Named("config"), applyDefaults)
+
 	// Create registry client
-	registryClient, err := createRegistryClient(cfg)
+	registryClient, err := createRegistryClient(cfg, logger)
+	if err != nil {
+		logger.Error("failed to create registry client", "error", err)
+		os.Exit(1)
+	}
+
+	// Create the persistent evidence pool so correlation state survives a
+	// restart (see pkg/evidencepool)
+	evidencePool, err := createEvidencePool(cfg, logger)
 	if err != nil {
-		log.Fatalf("Failed to create registry client: %v", err)
+		logger.Error("failed to open evidence pool", "error", err)
+		os.Exit(1)
 	}
+	defer evidencePool.Close()
 
 	// Create correlator engine
 	correlator := engine.NewCorrelator(
 		cfg.DedupWindow,
 		registryClient,
 		cfg.ClickHouseURL,
+		evidencePool,
 		engine.ScoringWeights{
 			Endpoint: cfg.WeightEndpoint,
 			Judge:    cfg.WeightJudge,
@@ -58,52 +90,128 @@ func main() {
 			Suspect:      cfg.ThresholdSuspect,
 			Unauthorized: cfg.ThresholdUnauthorized,
 		},
+		// Prometheus is already exposed on HealthPort's merged
+		// observability server (see startObservabilityServer), so the internal
+		// engine metrics (window size, evictions, per-source histograms)
+		// ride the same /metrics endpoint by default.
+		engine.NewPrometheusEmitter(),
+		logger.Named("engine"),
 	)
 
+	// Register cross-cutting interceptors. Recovery runs outermost so it
+	// catches a panic anywhere behind it, including in other interceptors.
+	correlator.Use(registerInterceptors(logger)...)
+
+	// Classification model defaults to ThresholdClassifier; opt into the
+	// probabilistic model (see pkg/engine/classifier.go) for A/B testing
+	// without redeploying.
+	correlator.SetClassifier(createClassifier(logger))
+
+	// Crash recovery: reload still-pending evidence from the pool into the
+	// correlation window before the consumer starts taking new events.
+	if rehydrated, err := correlator.Rehydrate(context.Background()); err != nil {
+		logger.Error("failed to rehydrate correlation window from evidence pool", "error", err)
+		os.Exit(1)
+	} else if rehydrated > 0 {
+		logger.Info("rehydrated pending detections from evidence pool", "count", rehydrated)
+	}
+
+	// Create enforcement layer and attach it to the correlator
+	enforcementEngine, err := createEnforcementEngine(logger)
+	if err != nil {
+		logger.Error("failed to create enforcement engine", "error", err)
+		os.Exit(1)
+	}
+	correlator.SetEnforcer(enforcementEngine)
+
 	// Create ClickHouse writer
 	chWriter, err := clickhouse.NewWriter(&clickhouse.Config{
 		DSN:             cfg.ClickHouseDSN,
 		MaxOpenConns:    20,
 		MaxIdleConns:    10,
 		ConnMaxLifetime: 30 * time.Minute,
-	})
+	}, logger.Named("clickhouse"))
 	if err != nil {
-		log.Fatalf("Failed to create ClickHouse writer: %v", err)
+		logger.Error("failed to create ClickHouse writer", "error", err)
+		os.Exit(1)
 	}
 	defer chWriter.Close()
 
 	// Test ClickHouse connection
 	ctx := context.Background()
 	if err := chWriter.HealthCheck(ctx); err != nil {
-		log.Fatalf("ClickHouse health check failed: %v", err)
+		logger.Error("ClickHouse health check failed", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("ClickHouse connection established")
+
+	// Create notifier for high-score detection alerts
+	detectionNotifier, err := createNotifier(cfg, logger)
+	if err != nil {
+		logger.Error("failed to create notifier", "error", err)
+		os.Exit(1)
+	}
+
+	// Detections are written through chWriter by default; set
+	// CLICKHOUSE_ASYNC_WRITER=true to batch them through an AsyncWriter
+	// instead (see pkg/clickhouse/async_writer.go) when write volume
+	// outgrows per-row INSERTs.
+	detectionWriter, err := createDetectionWriter(ctx, cfg, chWriter, logger)
+	if err != nil {
+		logger.Error("failed to create detection writer", "error", err)
+		os.Exit(1)
+	}
+	if asyncWriter, ok := detectionWriter.(*clickhouse.AsyncWriter); ok {
+		defer asyncWriter.Close()
 	}
-	log.Println("✓ ClickHouse connection established")
 
 	// Create NATS consumer
 	natsConsumer, err := consumer.NewConsumer(
 		&consumer.Config{
-			NATSUrl:    cfg.NATSUrl,
-			Subjects:   []string{"endpoint.events", "network.events", "gateway.events"},
-			StreamName: "detections",
-			DurableName: "correlator",
-			BatchSize:  10,
+			NATSUrl:          cfg.NATSUrl,
+			Subjects:         []string{"endpoint.events", "network.events", "gateway.events"},
+			StreamName:       "detections",
+			DurableName:      "correlator",
+			BatchSize:        cfg.BatchSize,
+			MaxDeliveries:    cfg.MaxDeliveries,
+			DeadLetterSuffix: cfg.DeadLetterSuffix,
+			Logger:           logger.Named("consumer"),
+			Notifier:         detectionNotifier,
+			TLSEnabled:       cfg.NATSTLSEnabled,
+			TLSCertFile:      cfg.NATSTLSCertFile,
+			TLSKeyFile:       cfg.NATSTLSKeyFile,
+			TLSCAFile:        cfg.NATSTLSCAFile,
+			NKeySeedFile:     cfg.NATSNKeySeedFile,
+			CredsFile:        cfg.NATSCredsFile,
+			Token:            cfg.NATSToken,
+			BrokerType:       cfg.BrokerType,
+			AMQPUrl:          cfg.AMQPUrl,
+			AMQPExchange:     cfg.AMQPExchange,
+			AMQPRoutingKey:   cfg.AMQPRoutingKey,
+			AMQPPersistent:   cfg.AMQPPersistent,
 		},
 		correlator,
-		chWriter,
+		detectionWriter,
 	)
 	if err != nil {
-		log.Fatalf("Failed to create NATS consumer: %v", err)
+		logger.Error("failed to create NATS consumer", "error", err)
+		os.Exit(1)
 	}
 	defer natsConsumer.Close()
 
-	// Start metrics server
-	go startMetricsServer(cfg.MetricsPort)
-
-	// Start health check server
-	go startHealthServer(cfg.HealthPort, natsConsumer, chWriter)
+	// Start the merged metrics/health server (see internal/httpserver):
+	// /metrics, /healthz, /readyz alongside the existing /health, /ready,
+	// /version, on one lifecycle-managed listener instead of the former
+	// separate metrics and health servers and the ad-hoc /stats endpoint.
+	trustedProxies, err := httpx.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		logger.Error("invalid TRUSTED_PROXIES", "error", err)
+		os.Exit(1)
+	}
+	obsServer := startObservabilityServer(cfg.HealthPort, natsConsumer, chWriter, trustedProxies, logger)
 
 	// Start cleanup routine for expired detections
-	go startCleanupRoutine(ctx, correlator)
+	go startCleanupRoutine(ctx, correlator, logger)
 
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -113,38 +221,62 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
+	// Watch for SIGHUP/fsnotify config reloads
+	go watcher.Watch(ctx)
+	go syncTunables(ctx, watcher, correlator, natsConsumer, logger)
+
+	// Watch for enforcement rule file changes, if configured
+	if rulesFile := os.Getenv("ENFORCEMENT_RULES_FILE"); rulesFile != "" {
+		go enforcementEngine.WatchRulesFile(ctx, rulesFile)
+	}
+
 	// Start consumer
 	go func() {
 		if err := natsConsumer.Start(ctx); err != nil {
-			log.Printf("Consumer error: %v", err)
+			logger.Error("consumer error", "error", err)
 		}
 	}()
 
-	log.Println("✓ Correlator service started successfully")
+	logger.Info("correlator service started successfully")
 
 	// Wait for shutdown signal
 	<-sigChan
-	log.Println("Shutdown signal received, stopping correlator...")
+	logger.Info("shutdown signal received, stopping correlator")
 	cancel()
 
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := obsServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error("metrics/health server shutdown error", "error", err)
+	}
+
 	// Give ongoing operations time to complete
 	time.Sleep(5 * time.Second)
-	log.Println("Correlator stopped")
+	logger.Info("correlator stopped")
 }
 
-// loadConfig loads correlator configuration
-func loadConfig() (*config.Config, error) {
-	configPath := os.Getenv("CONFIG_PATH")
-	if configPath == "" {
-		configPath = defaultConfigPath
+// configPath returns the directory correlator config is loaded from.
+func configPath() string {
+	if p := os.Getenv("CONFIG_PATH"); p != "" {
+		return p
 	}
+	return defaultConfigPath
+}
 
-	cfg, err := config.Load(configPath)
+// loadConfig loads correlator configuration
+func loadConfig() (*config.FlatConfig, error) {
+	cfg, err := config.Load(configPath())
 	if err != nil {
 		return nil, err
 	}
 
-	// Apply defaults
+	applyDefaults(cfg)
+	return cfg, nil
+}
+
+// applyDefaults fills in zero-valued tunables so both the initial load and
+// every later hot reload land on the same defaults.
+func applyDefaults(cfg *config.FlatConfig) {
 	if cfg.MetricsPort == "" {
 		cfg.MetricsPort = defaultMetricsPort
 	}
@@ -154,37 +286,255 @@ func loadConfig() (*config.Config, error) {
 	if cfg.DedupWindow == 0 {
 		cfg.DedupWindow = 5 * time.Minute // FR-002a default
 	}
-
-	return cfg, nil
 }
 
 // createRegistryClient creates a registry API client
-func createRegistryClient(cfg *config.Config) (*registry.Client, error) {
+func createRegistryClient(cfg *config.FlatConfig, logger logging.Logger) (*registry.Client, error) {
 	return registry.NewClient(&registry.Config{
-		BaseURL:   cfg.RegistryAPIURL,
-		AuthToken: cfg.RegistryAuthToken,
-		Timeout:   10 * time.Second,
-		TLSConfig: nil, // TODO: Add mTLS config if needed
+		BaseURL:    cfg.RegistryAPIURL,
+		AuthToken:  cfg.RegistryAuthToken,
+		AuthScheme: cfg.RegistryAuthScheme,
+		Timeout:    10 * time.Second,
+		TLSConfig:  nil, // TODO: Add mTLS config if needed
+		Logger:     logger.Named("registry"),
+		Retry: registry.RetryConfig{
+			MaxRetries: cfg.RegistryMaxRetries,
+			BaseDelay:  registry.DefaultRetryConfig.BaseDelay,
+			MaxDelay:   registry.DefaultRetryConfig.MaxDelay,
+		},
+		CircuitBreaker: registry.CircuitBreakerConfig{
+			FailureThreshold: cfg.RegistryCircuitBreakerThreshold,
+			OpenDuration:     registry.DefaultCircuitBreakerConfig.OpenDuration,
+		},
+		Cache: registry.CacheConfig{
+			MaxEntries: registry.DefaultCacheConfig.MaxEntries,
+			TTL:        cfg.RegistryCacheTTL,
+		},
 	})
 }
 
-// startMetricsServer starts Prometheus metrics HTTP server
-func startMetricsServer(port string) {
-	http.Handle("/metrics", promhttp.Handler())
-	log.Printf("Metrics server listening on %s", port)
-	if err := http.ListenAndServe(port, nil); err != nil {
-		log.Fatalf("Metrics server failed: %v", err)
+// registerInterceptors builds the interceptor chain for Correlator.Use:
+// panic recovery and logging always run; per-tenant rate limiting is
+// opt-in via RATE_LIMIT_EVENTS_PER_SECOND (tenant key is event.HostID).
+func registerInterceptors(logger logging.Logger) []engine.Interceptor {
+	chain := []engine.Interceptor{
+		interceptor.Recovery(logger.Named("recovery")),
+		interceptor.Tracing(),
+		interceptor.Metrics(),
+	}
+
+	if raw := os.Getenv("RATE_LIMIT_EVENTS_PER_SECOND"); raw != "" {
+		eventsPerSecond, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			logger.Warn("invalid RATE_LIMIT_EVENTS_PER_SECOND, rate limiting disabled", "value", raw, "error", err)
+		} else {
+			burst := int(eventsPerSecond)
+			if raw := os.Getenv("RATE_LIMIT_BURST"); raw != "" {
+				if parsed, err := strconv.Atoi(raw); err == nil {
+					burst = parsed
+				}
+			}
+			chain = append(chain, interceptor.RateLimit(eventsPerSecond, burst, nil))
+		}
 	}
+
+	// Logging runs innermost so its duration measurement brackets only the
+	// correlator's own work, not rate limiting or tracing overhead.
+	chain = append(chain, interceptor.Logging(logger.Named("interceptor")))
+	return chain
 }
 
-// startHealthServer starts health check HTTP server
-func startHealthServer(port string, natsConsumer *consumer.Consumer, chWriter *clickhouse.Writer) {
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+// createClassifier returns the classification model Correlator uses to
+// turn a scored detection into a label. Setting CLASSIFIER_MODE=probabilistic
+// opts into engine.ProbabilisticClassifier, tuned via
+// CLASSIFIER_PRIOR_UNAUTHORIZED_RATE, CLASSIFIER_AUTHORIZED_CUTOFF, and
+// CLASSIFIER_SUSPECT_CUTOFF; any other value (including unset) keeps the
+// default ThresholdClassifier, which SetClassifier(nil) would also restore.
+func createClassifier(logger logging.Logger) engine.Classifier {
+	if os.Getenv("CLASSIFIER_MODE") != "probabilistic" {
+		return nil
+	}
+
+	prior := 0.1
+	if raw := os.Getenv("CLASSIFIER_PRIOR_UNAUTHORIZED_RATE"); raw != "" {
+		if parsed, err := strconv.ParseFloat(raw, 64); err == nil {
+			prior = parsed
+		} else {
+			logger.Warn("invalid CLASSIFIER_PRIOR_UNAUTHORIZED_RATE, using default", "value", raw, "default", prior)
+		}
+	}
+
+	authorizedCutoff := 0.3
+	if raw := os.Getenv("CLASSIFIER_AUTHORIZED_CUTOFF"); raw != "" {
+		if parsed, err := strconv.ParseFloat(raw, 64); err == nil {
+			authorizedCutoff = parsed
+		} else {
+			logger.Warn("invalid CLASSIFIER_AUTHORIZED_CUTOFF, using default", "value", raw, "default", authorizedCutoff)
+		}
+	}
+
+	suspectCutoff := 0.7
+	if raw := os.Getenv("CLASSIFIER_SUSPECT_CUTOFF"); raw != "" {
+		if parsed, err := strconv.ParseFloat(raw, 64); err == nil {
+			suspectCutoff = parsed
+		} else {
+			logger.Warn("invalid CLASSIFIER_SUSPECT_CUTOFF, using default", "value", raw, "default", suspectCutoff)
+		}
+	}
+
+	logger.Info("using probabilistic classifier", "prior_unauthorized_rate", prior, "authorized_cutoff", authorizedCutoff, "suspect_cutoff", suspectCutoff)
+	return engine.NewProbabilisticClassifier(prior, authorizedCutoff, suspectCutoff)
+}
+
+// createEvidencePool opens the persistent evidence pool at
+// cfg.EvidencePoolPath. Setting EVIDENCE_POOL_PATH to ":memory:" opts into
+// the non-persistent in-memory pool instead (e.g. for local dev without a
+// writable data directory); production deployments should leave it at the
+// default on-disk path so a restart doesn't lose in-flight correlation
+// state.
+func createEvidencePool(cfg *config.FlatConfig, logger logging.Logger) (evidencepool.Pool, error) {
+	if cfg.EvidencePoolPath == ":memory:" {
+		logger.Warn("evidence pool running in-memory, state will not survive a restart")
+		return evidencepool.NewMemoryPool(), nil
+	}
+	return evidencepool.NewBoltPool(cfg.EvidencePoolPath)
+}
+
+// createEnforcementEngine builds the scoped enforcement layer from
+// ENFORCEMENT_RULES_FILE (a YAML rule set; see pkg/enforcement), defaulting
+// to no rules so enforcement is opt-in. When ENFORCEMENT_WEBHOOK_URL is
+// set, a "webhook" action POSTing detections there is registered;
+// "quarantine" and "block" are left unregistered since they depend on
+// infrastructure (a firewall/EDR API) this repo doesn't own.
+func createEnforcementEngine(logger logging.Logger) (*enforcement.Engine, error) {
+	var rules []enforcement.Rule
+	if rulesFile := os.Getenv("ENFORCEMENT_RULES_FILE"); rulesFile != "" {
+		loaded, err := enforcement.LoadRulesFile(rulesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load enforcement rules: %w", err)
+		}
+		rules = loaded
+	}
+
+	e := enforcement.NewEngine(&enforcement.Config{
+		Rules:  rules,
+		Logger: logger.Named("enforcement"),
+	})
+
+	if webhookURL := os.Getenv("ENFORCEMENT_WEBHOOK_URL"); webhookURL != "" {
+		e.RegisterAction("webhook", newWebhookAction(webhookURL))
+	}
+
+	return e, nil
+}
+
+// newWebhookAction returns an enforcement action that POSTs detection as
+// JSON to url.
+func newWebhookAction(url string) enforcement.ActionFunc {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return func(ctx context.Context, detection *engine.AggregatedDetection) error {
+		body, err := json.Marshal(detection)
+		if err != nil {
+			return fmt.Errorf("failed to marshal detection: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to build webhook request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to send webhook: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// createNotifier builds the detection-alert fan-out from the operator's
+// notifier config. A config with no providers yields a Notifier that never
+// matches anything, so alerting is opt-in by default.
+func createNotifier(cfg *config.FlatConfig, logger logging.Logger) (*notifier.Notifier, error) {
+	providers := make([]notifier.ProviderConfig, 0, len(cfg.Notifier.Providers))
+	for _, pc := range cfg.Notifier.Providers {
+		providers = append(providers, notifier.ProviderConfig{
+			Type:           pc.Type,
+			Name:           pc.Name,
+			URL:            pc.URL,
+			Secret:         pc.Secret,
+			WebhookURL:     pc.WebhookURL,
+			RoutingKey:     pc.RoutingKey,
+			Threshold:      pc.Threshold,
+			DetectionTypes: pc.DetectionTypes,
+		})
+	}
+
+	return notifier.New(&notifier.Config{
+		Threshold:      cfg.Notifier.Threshold,
+		DetectionTypes: cfg.Notifier.DetectionTypes,
+		Providers:      providers,
+		Logger:         logger.Named("notifier"),
+	})
+}
+
+// detectionWriter is the subset of clickhouse.Writer's API consumer.NewConsumer
+// needs, shared structurally with consumer.detectionWriter so either
+// chWriter or an AsyncWriter can be passed to it.
+type detectionWriter interface {
+	WriteDetection(ctx context.Context, detection *clickhouse.Detection) error
+}
+
+// createDetectionWriter returns chWriter itself unless
+// CLICKHOUSE_ASYNC_WRITER=true, in which case it instead builds an
+// AsyncWriter (see pkg/clickhouse/async_writer.go) against the same DSN,
+// for deployments whose detection volume outgrows chWriter's per-row
+// INSERTs. CLICKHOUSE_ASYNC_WAL_DIR relocates the AsyncWriter's on-disk
+// spill buffer; it defaults to a path under /var/lib/mcpeeker, alongside
+// the evidence pool.
+func createDetectionWriter(ctx context.Context, cfg *config.FlatConfig, chWriter *clickhouse.Writer, logger logging.Logger) (detectionWriter, error) {
+	if os.Getenv("CLICKHOUSE_ASYNC_WRITER") != "true" {
+		return chWriter, nil
+	}
+
+	walDir := os.Getenv("CLICKHOUSE_ASYNC_WAL_DIR")
+	if walDir == "" {
+		walDir = "/var/lib/mcpeeker/clickhouse-async-wal"
+	}
+
+	asyncWriter, err := clickhouse.NewAsyncWriter(ctx, clickhouse.AsyncWriterConfig{
+		DSN:    cfg.ClickHouseDSN,
+		WALDir: walDir,
+	}, logger.Named("clickhouse-async"))
+	if err != nil {
+		return nil, fmt.Errorf("create async clickhouse writer: %w", err)
+	}
+	logger.Info("using async batched ClickHouse writer", "wal_dir", walDir)
+	return asyncWriter, nil
+}
+
+// startObservabilityServer builds and starts the merged metrics/health
+// server on port (see internal/httpserver), replacing the former separate
+// metrics and health listeners with a single lifecycle-managed one. It
+// carries the pre-existing /health, /ready, and /version routes alongside
+// the new /metrics, /healthz, and /readyz ones internal/httpserver.New
+// adds; /stats and Consumer.GetStats are gone in favor of /readyz.
+// trustedProxies gates which peers' X-Real-IP/X-Forwarded-For headers are
+// honored when resolving the caller's real IP (see pkg/httpx.RealIP).
+func startObservabilityServer(port string, natsConsumer *consumer.Consumer, chWriter *clickhouse.Writer, trustedProxies []*net.IPNet, logger logging.Logger) *httpserver.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, "OK")
+		w.Write([]byte("OK"))
 	})
 
-	http.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
 		// Check ClickHouse
 		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 		defer cancel()
@@ -195,23 +545,79 @@ func startHealthServer(port string, natsConsumer *consumer.Consumer, chWriter *c
 		}
 
 		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, "Ready")
+		w.Write([]byte("Ready"))
 	})
 
-	http.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
-		stats := natsConsumer.GetStats()
+	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		fmt.Fprintf(w, `{"stats": %v}`, stats)
+		json.NewEncoder(w).Encode(map[string]string{
+			"version":    buildinfo.Version,
+			"git_commit": buildinfo.GitCommit,
+			"build_date": buildinfo.BuildDate,
+			"go_version": buildinfo.GoVersion,
+			"branch":     buildinfo.Branch,
+		})
 	})
 
-	log.Printf("Health server listening on %s", port)
-	if err := http.ListenAndServe(port, nil); err != nil {
-		log.Fatalf("Health server failed: %v", err)
+	srv := httpserver.New(port, mux, correlatorReadiness(natsConsumer, chWriter), httpx.RealIP(trustedProxies), logger.Named("obs-server"))
+	srv.Start()
+	return srv
+}
+
+// correlatorReadiness reports ready only while natsConsumer's broker
+// connection is up and chWriter's ClickHouse connection answers a ping,
+// for the /readyz endpoint (see internal/httpserver).
+func correlatorReadiness(natsConsumer *consumer.Consumer, chWriter *clickhouse.Writer) httpserver.ReadyFunc {
+	return func(ctx context.Context) error {
+		if !natsConsumer.Connected() {
+			return fmt.Errorf("broker connection down")
+		}
+		if err := chWriter.HealthCheck(ctx); err != nil {
+			return fmt.Errorf("clickhouse unhealthy: %w", err)
+		}
+		return nil
+	}
+}
+
+// syncTunables watches for hot-reloaded scoring weights, classification
+// thresholds, and consumer batch size, and pushes them into the already
+// running engine.Correlator and consumer.Consumer without a restart.
+func syncTunables(ctx context.Context, watcher *config.Watcher, correlator *engine.Correlator, natsConsumer *consumer.Consumer, logger logging.Logger) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	last := watcher.Current()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cfg := watcher.Current()
+			if cfg == last {
+				continue
+			}
+			correlator.UpdateTunables(
+				engine.ScoringWeights{
+					Endpoint: cfg.WeightEndpoint,
+					Judge:    cfg.WeightJudge,
+					Network:  cfg.WeightNetwork,
+					Registry: cfg.RegistryPenalty,
+				},
+				engine.ClassificationThresholds{
+					Authorized:   cfg.ThresholdAuthorized,
+					Suspect:      cfg.ThresholdSuspect,
+					Unauthorized: cfg.ThresholdUnauthorized,
+				},
+			)
+			natsConsumer.SetBatchSize(cfg.BatchSize)
+			logger.Info("applied reloaded scoring weights, thresholds, and batch size")
+			last = cfg
+		}
 	}
 }
 
 // startCleanupRoutine periodically cleans up expired detections from the correlation window
-func startCleanupRoutine(ctx context.Context, correlator *engine.Correlator) {
+func startCleanupRoutine(ctx context.Context, correlator *engine.Correlator, logger logging.Logger) {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
@@ -222,7 +628,7 @@ func startCleanupRoutine(ctx context.Context, correlator *engine.Correlator) {
 		case <-ticker.C:
 			removed := correlator.CleanupExpired()
 			if removed > 0 {
-				log.Printf("Cleaned up %d expired detections from correlation window", removed)
+				logger.Info("cleaned up expired detections from correlation window", "removed", removed)
 				metrics.DeduplicationMatchesTotal.Add(float64(removed))
 			}
 		}
@@ -0,0 +1,93 @@
+// mcpeekerctl is an operator CLI for MCPeeker credential management.
+// Reference: FR-010 (authentication)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/authtoken"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "token":
+		err = runToken(os.Args[2:])
+	default:
+		usage()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "mcpeekerctl:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: mcpeekerctl token issue [flags]")
+}
+
+// runToken dispatches the "token" subcommand group.
+func runToken(args []string) error {
+	if len(args) < 1 || args[0] != "issue" {
+		return fmt.Errorf("usage: mcpeekerctl token issue [flags]")
+	}
+	return runTokenIssue(args[1:])
+}
+
+// runTokenIssue implements "mcpeekerctl token issue", signing a registry
+// API JWT so operators can rotate credentials without editing config.
+func runTokenIssue(args []string) error {
+	fs := flag.NewFlagSet("token issue", flag.ContinueOnError)
+	sub := fs.String("sub", "", "subject identifier for the issued token (required)")
+	scopes := fs.String("scopes", "", "comma-separated scopes, e.g. mcps:write,detections:read")
+	ttl := fs.Duration("ttl", 24*time.Hour, "token lifetime")
+	secretFile := fs.String("secret-file", "", "path to the HS256 signing secret (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *sub == "" {
+		return fmt.Errorf("-sub is required")
+	}
+	if *secretFile == "" {
+		return fmt.Errorf("-secret-file is required")
+	}
+
+	secret, err := os.ReadFile(*secretFile)
+	if err != nil {
+		return fmt.Errorf("failed to read secret file: %w", err)
+	}
+
+	token, err := authtoken.IssueHS256(secret, *sub, splitScopes(*scopes), *ttl)
+	if err != nil {
+		return fmt.Errorf("failed to issue token: %w", err)
+	}
+
+	fmt.Println(token)
+	return nil
+}
+
+// splitScopes parses a comma-separated scope list, dropping empty entries.
+func splitScopes(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var scopes []string
+	for _, s := range strings.Split(raw, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
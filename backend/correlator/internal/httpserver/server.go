@@ -0,0 +1,84 @@
+// Package httpserver provides a single lifecycle-managed HTTP server that
+// exposes Prometheus metrics and Kubernetes-style liveness/readiness
+// endpoints, so main doesn't have to hand-roll a goroutine and a shutdown
+// path for it.
+package httpserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+)
+
+// ReadyFunc reports whether the service is ready to take traffic. A
+// returned error fails /readyz with its message as the response body.
+type ReadyFunc func(ctx context.Context) error
+
+// Server binds addr and serves /metrics, /healthz, and /readyz on it,
+// alongside whatever other routes the caller registered on mux.
+type Server struct {
+	httpServer *http.Server
+	addr       string
+	logger     logging.Logger
+}
+
+// New builds a Server. mux is the caller's handler tree (e.g. already
+// carrying /health, /ready, and /version); /metrics, /healthz, and
+// /readyz are added to it here. ready is polled on every /readyz request;
+// a nil ready always reports healthy. middleware, if non-nil, wraps the
+// final handler (e.g. pkg/httpx.RealIP), matching the
+// func(http.Handler) http.Handler shape that package already returns.
+func New(addr string, mux *http.ServeMux, ready ReadyFunc, middleware func(http.Handler) http.Handler, logger logging.Logger) *Server {
+	if logger == nil {
+		logger = logging.Noop()
+	}
+	if ready == nil {
+		ready = func(context.Context) error { return nil }
+	}
+
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := ready(r.Context()); err != nil {
+			http.Error(w, "not ready: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+
+	var handler http.Handler = mux
+	if middleware != nil {
+		handler = middleware(handler)
+	}
+
+	return &Server{
+		httpServer: &http.Server{Addr: addr, Handler: handler},
+		addr:       addr,
+		logger:     logger,
+	}
+}
+
+// Start launches the listener in its own goroutine. A ListenAndServe error
+// other than the one Shutdown causes is logged, since Start doesn't block
+// for callers to check it themselves.
+func (s *Server) Start() {
+	go func() {
+		s.logger.Info("metrics/health server listening", "addr", s.addr)
+		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("metrics/health server failed", "error", err)
+		}
+	}()
+}
+
+// Shutdown gracefully stops the server, waiting for in-flight scrapes and
+// health checks to finish until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
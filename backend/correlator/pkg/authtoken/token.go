@@ -0,0 +1,87 @@
+// Package authtoken issues and validates the JWTs used to authenticate
+// against the registry API's scope-enforced endpoints (see
+// pkg/registry.Client and cmd/mcpeekerctl).
+// Reference: FR-010 (authentication)
+package authtoken
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Scope identifiers recognized by the registry API's endpoint enforcement.
+const (
+	ScopeMCPsWrite      = "mcps:write"
+	ScopeDetectionsRead = "detections:read"
+)
+
+// Claims are the registry API's JWT claims: Subject identifies the caller,
+// Scopes grants endpoint access (e.g. ScopeMCPsWrite), and ExpiresAt (via
+// RegisteredClaims) bounds the token's lifetime.
+type Claims struct {
+	Scopes []string `json:"scopes"`
+	jwt.RegisteredClaims
+}
+
+// HasScope reports whether c grants scope.
+func (c Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueHS256 signs a JWT for sub with scopes using a shared secret,
+// expiring after ttl.
+func IssueHS256(secret []byte, sub string, scopes []string, ttl time.Duration) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims(sub, scopes, ttl))
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, nil
+}
+
+// IssueRS256 signs a JWT for sub with scopes using an RSA private key,
+// expiring after ttl.
+func IssueRS256(key *rsa.PrivateKey, sub string, scopes []string, ttl time.Duration) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, newClaims(sub, scopes, ttl))
+	signed, err := token.SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, nil
+}
+
+func newClaims(sub string, scopes []string, ttl time.Duration) Claims {
+	now := time.Now()
+	return Claims{
+		Scopes: scopes,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   sub,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+}
+
+// Parse validates tokenString against keyFunc (which resolves the signing
+// key from the token's header, per jwt.Keyfunc's contract) and returns its
+// claims.
+func Parse(tokenString string, keyFunc jwt.Keyfunc) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keyFunc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+	return claims, nil
+}
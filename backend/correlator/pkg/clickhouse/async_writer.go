@@ -0,0 +1,419 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	chlib "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/metrics"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/redact"
+)
+
+const (
+	defaultAsyncBatchSize     = 10000
+	defaultAsyncFlushInterval = 5 * time.Second
+	defaultAsyncQueueSize     = 50000
+)
+
+var (
+	asyncRowsEnqueuedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "correlator_clickhouse_async_rows_enqueued_total",
+		Help: "Total detections handed to AsyncWriter.Enqueue",
+	})
+	asyncRowsFlushedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "correlator_clickhouse_async_rows_flushed_total",
+		Help: "Total detections successfully flushed to ClickHouse by AsyncWriter",
+	})
+	asyncRowsDroppedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "correlator_clickhouse_async_rows_dropped_total",
+		Help: "Total detections AsyncWriter did not hand off to ClickHouse on this attempt, by reason",
+	}, []string{"reason"}) // wal_write_error, enqueue_timeout, flush_error
+	asyncQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "correlator_clickhouse_async_queue_depth",
+		Help: "Current number of detections buffered in AsyncWriter's in-memory queue",
+	})
+	asyncFlushDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "correlator_clickhouse_async_flush_duration_seconds",
+		Help:    "Time taken to flush a batch of detections to ClickHouse via PrepareBatch/AppendStruct",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 10),
+	})
+)
+
+// AsyncWriterConfig configures AsyncWriter's batching, backpressure, and
+// spill-to-disk behavior.
+type AsyncWriterConfig struct {
+	// DSN is the same native-protocol connection string Config.DSN uses;
+	// AsyncWriter opens its own driver.Conn from it for PrepareBatch.
+	DSN string
+
+	// BatchSize is the number of buffered rows that triggers an immediate
+	// flush, rather than waiting for FlushInterval. Defaults to 10000.
+	BatchSize int
+	// FlushInterval is the longest a row waits in the buffer before being
+	// flushed, even if BatchSize hasn't been reached. Defaults to 5s.
+	FlushInterval time.Duration
+	// QueueSize bounds the in-memory channel Enqueue sends to; once full,
+	// Enqueue blocks (backpressure) until the flush loop drains it or the
+	// caller's context is done. Defaults to 50000.
+	QueueSize int
+	// WALDir is the directory AsyncWriter spills enqueued-but-not-yet-
+	// flushed rows to, so a ClickHouse outage or process crash doesn't
+	// lose them. Required.
+	WALDir string
+}
+
+func (c *AsyncWriterConfig) applyDefaults() {
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultAsyncBatchSize
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = defaultAsyncFlushInterval
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = defaultAsyncQueueSize
+	}
+}
+
+// AsyncWriter batches detections and flushes them to ClickHouse using the
+// native driver's columnar batch API (PrepareBatch/AppendStruct), which
+// performs far better than Writer's per-row/per-small-batch INSERTs under
+// high detection volume. It wraps a Writer only in spirit — WriteDetection
+// here durably spills to an on-disk WAL and hands the row to the flush
+// loop, rather than blocking on ClickHouse itself — so existing callers of
+// Writer.WriteDetection can switch to an AsyncWriter without a different
+// call shape. See NewAsyncWriter for WAL replay on startup.
+type AsyncWriter struct {
+	cfg    AsyncWriterConfig
+	conn   driver.Conn
+	wal    *wal
+	logger logging.Logger
+
+	queue    chan walEntry
+	flushReq chan chan error
+	closeCh  chan struct{}
+	doneCh   chan struct{}
+	closeOne sync.Once
+}
+
+// NewAsyncWriter opens cfg.WALDir's WAL and cfg.DSN's native connection,
+// replays any rows left over from an unclean shutdown, and starts the
+// background flush loop. New writes are only accepted once replay has
+// finished.
+func NewAsyncWriter(ctx context.Context, cfg AsyncWriterConfig, logger logging.Logger) (*AsyncWriter, error) {
+	if logger == nil {
+		logger = logging.Noop()
+	}
+	cfg.applyDefaults()
+
+	w, err := openWAL(cfg.WALDir)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := chlib.ParseDSN(cfg.DSN)
+	if err != nil {
+		w.close()
+		return nil, fmt.Errorf("parse clickhouse dsn: %w", err)
+	}
+	conn, err := chlib.Open(opts)
+	if err != nil {
+		w.close()
+		return nil, fmt.Errorf("open clickhouse native connection: %w", err)
+	}
+	if err := conn.Ping(ctx); err != nil {
+		w.close()
+		return nil, fmt.Errorf("ping clickhouse: %w", err)
+	}
+
+	aw := &AsyncWriter{
+		cfg:      cfg,
+		conn:     conn,
+		wal:      w,
+		logger:   logger,
+		queue:    make(chan walEntry, cfg.QueueSize),
+		flushReq: make(chan chan error),
+		closeCh:  make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+
+	replayed, err := aw.replayWAL(ctx)
+	if err != nil {
+		conn.Close()
+		w.close()
+		return nil, fmt.Errorf("replay wal: %w", err)
+	}
+	if replayed > 0 {
+		logger.Info("replayed pending rows from clickhouse async writer WAL", "count", replayed)
+	}
+
+	go aw.run()
+	return aw, nil
+}
+
+// replayWAL flushes every row still in the WAL from a prior run, in
+// batches of cfg.BatchSize, before NewAsyncWriter starts the flush loop.
+func (aw *AsyncWriter) replayWAL(ctx context.Context) (int, error) {
+	entries, err := aw.wal.pending()
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for len(entries) > 0 {
+		n := aw.cfg.BatchSize
+		if n > len(entries) {
+			n = len(entries)
+		}
+		batch := entries[:n]
+		entries = entries[n:]
+
+		if err := aw.flushBatch(ctx, batch); err != nil {
+			return total, fmt.Errorf("flush replayed batch: %w", err)
+		}
+		total += len(batch)
+	}
+	return total, nil
+}
+
+// Enqueue durably spills detection to the WAL, then hands it to the flush
+// loop's queue, blocking (backpressure) until there's room or ctx is done.
+// A nil error means detection has been persisted to disk and will be
+// flushed to ClickHouse eventually, even across a restart — it is safe for
+// the caller to treat this the same as Writer.WriteDetection succeeding
+// for at-least-once delivery purposes.
+func (aw *AsyncWriter) Enqueue(ctx context.Context, detection *Detection) error {
+	if detection.DetectionID == "" {
+		detection.DetectionID = uuid.New().String()
+	}
+
+	seq, err := aw.wal.append(detection)
+	if err != nil {
+		asyncRowsDroppedTotal.WithLabelValues("wal_write_error").Inc()
+		return fmt.Errorf("spill detection to wal: %w", err)
+	}
+
+	select {
+	case aw.queue <- walEntry{seq: seq, detection: detection}:
+		asyncRowsEnqueuedTotal.Inc()
+		asyncQueueDepth.Set(float64(len(aw.queue)))
+		return nil
+	case <-ctx.Done():
+		// The row is still safely in the WAL and will be picked up by a
+		// future replay (e.g. after this process restarts); it is not
+		// lost, just not yet handed to this queue.
+		asyncRowsDroppedTotal.WithLabelValues("enqueue_timeout").Inc()
+		return ctx.Err()
+	case <-aw.closeCh:
+		return fmt.Errorf("async writer closed")
+	}
+}
+
+// WriteDetection is Enqueue under the name Writer.WriteDetection uses, so
+// callers that take either type through a common interface don't need to
+// special-case AsyncWriter.
+func (aw *AsyncWriter) WriteDetection(ctx context.Context, detection *Detection) error {
+	return aw.Enqueue(ctx, detection)
+}
+
+// Flush blocks until every row currently buffered has been flushed to
+// ClickHouse (or the attempt has failed), regardless of BatchSize or
+// FlushInterval.
+func (aw *AsyncWriter) Flush(ctx context.Context) error {
+	ack := make(chan error, 1)
+	select {
+	case aw.flushReq <- ack:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-aw.closeCh:
+		return fmt.Errorf("async writer closed")
+	}
+
+	select {
+	case err := <-ack:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops the flush loop after a final flush, then closes the WAL and
+// the ClickHouse connection. It is safe to call once; a second call is a
+// no-op.
+func (aw *AsyncWriter) Close() error {
+	first := false
+	aw.closeOne.Do(func() {
+		first = true
+		close(aw.closeCh)
+	})
+	if !first {
+		return nil
+	}
+	<-aw.doneCh
+	walErr := aw.wal.close()
+	connErr := aw.conn.Close()
+	if walErr != nil {
+		return walErr
+	}
+	return connErr
+}
+
+// run is the flush loop: it accumulates queued rows until BatchSize is hit
+// or FlushInterval elapses, flushing either way, and also answers Flush
+// requests out of band.
+func (aw *AsyncWriter) run() {
+	defer close(aw.doneCh)
+
+	ticker := time.NewTicker(aw.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []walEntry
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := aw.flushBatch(context.Background(), batch)
+		if err != nil {
+			aw.logger.Error("failed to flush batch to clickhouse, rows remain in WAL for retry", "error", err, "count", len(batch))
+			asyncRowsDroppedTotal.WithLabelValues("flush_error").Add(float64(len(batch)))
+		} else {
+			asyncRowsFlushedTotal.Add(float64(len(batch)))
+		}
+		batch = batch[:0]
+		asyncQueueDepth.Set(float64(len(aw.queue)))
+		return err
+	}
+
+	for {
+		select {
+		case entry := <-aw.queue:
+			batch = append(batch, entry)
+			asyncQueueDepth.Set(float64(len(aw.queue)))
+			if len(batch) >= aw.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case ack := <-aw.flushReq:
+			// Drain whatever's already queued so Flush observes a
+			// consistent view, then flush it all as one batch.
+		drain:
+			for {
+				select {
+				case entry := <-aw.queue:
+					batch = append(batch, entry)
+				default:
+					break drain
+				}
+			}
+			ack <- flush()
+		case <-aw.closeCh:
+			flush()
+			return
+		}
+	}
+}
+
+// flushBatch sends rows to ClickHouse via PrepareBatch/AppendStruct and,
+// on success, removes them from the WAL. The caller is responsible for
+// counting flushed/dropped rows and logging; flushBatch only reports the
+// error.
+func (aw *AsyncWriter) flushBatch(ctx context.Context, rows []walEntry) error {
+	start := time.Now()
+	defer func() { asyncFlushDuration.Observe(time.Since(start).Seconds()) }()
+
+	insertBatch, err := aw.conn.PrepareBatch(ctx, `INSERT INTO detections (
+		detection_id,
+		timestamp,
+		host_id_hash,
+		composite_id,
+		score,
+		classification,
+		evidence.type,
+		evidence.source,
+		evidence.score_contribution,
+		evidence.snippet,
+		evidence.redacted,
+		judge_available
+	)`)
+	if err != nil {
+		return fmt.Errorf("prepare batch: %w", err)
+	}
+
+	seqs := make([]uint64, 0, len(rows))
+	for _, row := range rows {
+		if err := insertBatch.AppendStruct(toDetectionRow(row.detection)); err != nil {
+			insertBatch.Abort()
+			return fmt.Errorf("append row %s: %w", row.detection.DetectionID, err)
+		}
+		seqs = append(seqs, row.seq)
+	}
+
+	if err := insertBatch.Send(); err != nil {
+		return fmt.Errorf("send batch: %w", err)
+	}
+
+	if err := aw.wal.remove(seqs); err != nil {
+		return fmt.Errorf("remove flushed rows from wal: %w", err)
+	}
+	return nil
+}
+
+// detectionRow is AppendStruct's view of a Detection, with `ch` tags
+// mapping each field to its detections table column (including the
+// `evidence.*` nested-array columns Writer's hand-written INSERT also
+// targets).
+type detectionRow struct {
+	DetectionID      string    `ch:"detection_id"`
+	Timestamp        time.Time `ch:"timestamp"`
+	HostIDHash       string    `ch:"host_id_hash"`
+	CompositeID      string    `ch:"composite_id"`
+	Score            int32     `ch:"score"`
+	Classification   string    `ch:"classification"`
+	EvidenceType     []string  `ch:"evidence.type"`
+	EvidenceSource   []string  `ch:"evidence.source"`
+	EvidenceScore    []uint8   `ch:"evidence.score_contribution"`
+	EvidenceSnippet  []string  `ch:"evidence.snippet"`
+	EvidenceRedacted []uint8   `ch:"evidence.redacted"`
+	JudgeAvailable   bool      `ch:"judge_available"`
+}
+
+// toDetectionRow converts detection to AppendStruct's row shape, truncating
+// evidence snippets to 1KB the same way Writer.WriteDetection does (FR-009),
+// after running Writer's same belt-and-braces pkg/redact pass over each
+// snippet first.
+func toDetectionRow(detection *Detection) detectionRow {
+	row := detectionRow{
+		DetectionID:    detection.DetectionID,
+		Timestamp:      detection.Timestamp,
+		HostIDHash:     detection.HostIDHash,
+		CompositeID:    detection.CompositeID,
+		Score:          int32(detection.Score),
+		Classification: detection.Classification,
+		JudgeAvailable: detection.JudgeAvailable,
+	}
+
+	for _, ev := range detection.Evidence {
+		redacted := redact.String(ev.Snippet)
+		metrics.RedactionsPerDetection.Observe(float64(redacted.Count))
+		snippet := redacted.Text
+		if len(snippet) > 1024 {
+			snippet = snippet[:1024]
+		}
+		row.EvidenceType = append(row.EvidenceType, ev.Type)
+		row.EvidenceSource = append(row.EvidenceSource, ev.Source)
+		row.EvidenceScore = append(row.EvidenceScore, uint8(ev.ScoreContribution))
+		row.EvidenceSnippet = append(row.EvidenceSnippet, snippet)
+		row.EvidenceRedacted = append(row.EvidenceRedacted, boolToUint8(ev.Redacted || redacted.Count > 0))
+	}
+
+	return row
+}
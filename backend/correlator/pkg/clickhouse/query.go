@@ -0,0 +1,310 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the OpenTelemetry instrumentation name Writer's query
+// spans are reported under.
+const TracerName = "github.com/ozlabs/mcpeeker/backend/correlator/pkg/clickhouse"
+
+var tracer = otel.Tracer(TracerName)
+
+// DetectionFilter narrows StreamDetections/PageDetections to a subset of
+// the detections table. Zero-valued fields are not applied: an empty
+// HostIDHash matches every host, a nil Classifications matches every
+// classification, and so on.
+type DetectionFilter struct {
+	StartTime   time.Time
+	EndTime     time.Time
+	HostIDHash  string
+	CompositeID string
+
+	// MinScore and MaxScore bound score inclusively; a zero value leaves
+	// that side unbounded.
+	MinScore int
+	MaxScore int
+
+	// Classifications, if non-empty, restricts results to rows whose
+	// classification is one of these values.
+	Classifications []string
+
+	// EvidenceType, if set, restricts results to rows with at least one
+	// evidence entry of this type.
+	EvidenceType string
+}
+
+// whereClause renders f as a SQL WHERE clause (or "" if f has no
+// constraints set) plus its positional args, in the same order the ?
+// placeholders appear.
+func (f DetectionFilter) whereClause() (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	if !f.StartTime.IsZero() {
+		clauses = append(clauses, "timestamp >= ?")
+		args = append(args, f.StartTime)
+	}
+	if !f.EndTime.IsZero() {
+		clauses = append(clauses, "timestamp < ?")
+		args = append(args, f.EndTime)
+	}
+	if f.HostIDHash != "" {
+		clauses = append(clauses, "host_id_hash = ?")
+		args = append(args, f.HostIDHash)
+	}
+	if f.CompositeID != "" {
+		clauses = append(clauses, "composite_id = ?")
+		args = append(args, f.CompositeID)
+	}
+	if f.MinScore != 0 {
+		clauses = append(clauses, "score >= ?")
+		args = append(args, f.MinScore)
+	}
+	if f.MaxScore != 0 {
+		clauses = append(clauses, "score <= ?")
+		args = append(args, f.MaxScore)
+	}
+	if len(f.Classifications) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(f.Classifications)), ",")
+		clauses = append(clauses, fmt.Sprintf("classification IN (%s)", placeholders))
+		for _, c := range f.Classifications {
+			args = append(args, c)
+		}
+	}
+	if f.EvidenceType != "" {
+		clauses = append(clauses, "has(evidence.type, ?)")
+		args = append(args, f.EvidenceType)
+	}
+
+	if len(clauses) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(clauses, " AND "), args
+}
+
+const selectDetectionColumns = `
+	detection_id,
+	timestamp,
+	host_id_hash,
+	composite_id,
+	score,
+	classification,
+	evidence.type,
+	evidence.source,
+	evidence.score_contribution,
+	evidence.snippet,
+	evidence.redacted,
+	judge_available
+`
+
+// scanDetectionRow scans a single row in selectDetectionColumns order into
+// a Detection, reconstructing its nested Evidence slice from the table's
+// parallel evidence.* arrays.
+func scanDetectionRow(rows *sql.Rows) (*Detection, error) {
+	var detection Detection
+	var evidenceTypes []string
+	var evidenceSources []string
+	var evidenceScores []uint8
+	var evidenceSnippets []string
+	var evidenceRedacted []uint8
+
+	if err := rows.Scan(
+		&detection.DetectionID,
+		&detection.Timestamp,
+		&detection.HostIDHash,
+		&detection.CompositeID,
+		&detection.Score,
+		&detection.Classification,
+		&evidenceTypes,
+		&evidenceSources,
+		&evidenceScores,
+		&evidenceSnippets,
+		&evidenceRedacted,
+		&detection.JudgeAvailable,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	for i := range evidenceTypes {
+		detection.Evidence = append(detection.Evidence, Evidence{
+			Type:              evidenceTypes[i],
+			Source:            evidenceSources[i],
+			ScoreContribution: int(evidenceScores[i]),
+			Snippet:           evidenceSnippets[i],
+			Redacted:          evidenceRedacted[i] != 0,
+		})
+	}
+
+	return &detection, nil
+}
+
+// startQuerySpan starts an OpenTelemetry span around a ClickHouse query,
+// tagged per the db.* semantic conventions, so a slow query can be
+// correlated with correlator request latency from trace data alone.
+func startQuerySpan(ctx context.Context, operation, statement string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "clickhouse."+operation, trace.WithAttributes(
+		attribute.String("db.system", "clickhouse"),
+		attribute.String("db.operation", operation),
+		attribute.String("db.statement", statement),
+	))
+}
+
+// spanError records err on span and returns it unchanged, so callers can
+// write `return spanError(span, err)`.
+func spanError(span trace.Span, err error) error {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
+
+// StreamDetections runs filter against the detections table and invokes fn
+// once per matching row, in (timestamp DESC, detection_id) order, without
+// ever holding the full result set in memory. Prefer this (or
+// PageDetections) over GetDetectionsByTimeRange when the time range might
+// be wide — GetDetectionsByTimeRange loads every matching row into a
+// slice before returning. fn returning an error stops iteration early and
+// is returned as-is.
+func (w *Writer) StreamDetections(ctx context.Context, filter DetectionFilter, fn func(*Detection) error) error {
+	where, args := filter.whereClause()
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM detections
+		%s
+		ORDER BY timestamp DESC, detection_id
+	`, selectDetectionColumns, where)
+
+	ctx, span := startQuerySpan(ctx, "StreamDetections", query)
+	defer span.End()
+
+	rows, err := w.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to stream detections: %w", err))
+	}
+	defer rows.Close()
+
+	rowCount := 0
+	for rows.Next() {
+		detection, err := scanDetectionRow(rows)
+		if err != nil {
+			return spanError(span, err)
+		}
+		rowCount++
+		if err := fn(detection); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return spanError(span, fmt.Errorf("row iteration error: %w", err))
+	}
+
+	span.SetAttributes(attribute.Int("db.rows_affected", rowCount))
+	return nil
+}
+
+// pageCursor is the decoded form of a PageDetections page token: the
+// (timestamp, detection_id) of the last row returned on the prior page.
+// Keyset pagination on this pair, rather than OFFSET, keeps every page
+// O(limit) instead of O(offset) — an operator paging through 90 days of
+// retention shouldn't pay an ever-larger scan-and-discard cost per page.
+type pageCursor struct {
+	Timestamp   time.Time `json:"t"`
+	DetectionID string    `json:"d"`
+}
+
+func encodePageToken(c pageCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("encode page token: %w", err)
+	}
+	return base64.URLEncoding.EncodeToString(b), nil
+}
+
+func decodePageToken(token string) (pageCursor, error) {
+	var c pageCursor
+	b, err := base64.URLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("invalid page token: %w", err)
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("invalid page token: %w", err)
+	}
+	return c, nil
+}
+
+// PageDetections returns up to limit detections matching filter, ordered
+// (timestamp DESC, detection_id), and a token to pass as pageToken on the
+// next call. An empty nextPageToken means there are no more rows.
+// pageToken is the empty string for the first page.
+func (w *Writer) PageDetections(ctx context.Context, filter DetectionFilter, pageToken string, limit int) (detections []*Detection, nextPageToken string, err error) {
+	where, args := filter.whereClause()
+
+	if pageToken != "" {
+		cursor, err := decodePageToken(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		keyset := "(timestamp < ? OR (timestamp = ? AND detection_id > ?))"
+		if where == "" {
+			where = "WHERE " + keyset
+		} else {
+			where += " AND " + keyset
+		}
+		args = append(args, cursor.Timestamp, cursor.Timestamp, cursor.DetectionID)
+	}
+
+	// Fetch one row past limit so we can tell whether a next page exists
+	// without a separate COUNT query.
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM detections
+		%s
+		ORDER BY timestamp DESC, detection_id
+		LIMIT ?
+	`, selectDetectionColumns, where)
+	args = append(args, limit+1)
+
+	ctx, span := startQuerySpan(ctx, "PageDetections", query)
+	defer span.End()
+
+	rows, err := w.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", spanError(span, fmt.Errorf("failed to page detections: %w", err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		detection, err := scanDetectionRow(rows)
+		if err != nil {
+			return nil, "", spanError(span, err)
+		}
+		detections = append(detections, detection)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", spanError(span, fmt.Errorf("row iteration error: %w", err))
+	}
+
+	span.SetAttributes(attribute.Int("db.rows_affected", len(detections)))
+
+	if len(detections) > limit {
+		last := detections[limit-1]
+		nextPageToken, err = encodePageToken(pageCursor{Timestamp: last.Timestamp, DetectionID: last.DetectionID})
+		if err != nil {
+			return nil, "", err
+		}
+		detections = detections[:limit]
+	}
+
+	return detections, nextPageToken, nil
+}
@@ -0,0 +1,120 @@
+package clickhouse
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// walBucket is the single bbolt bucket pending AsyncWriter rows live in,
+// keyed by an auto-incrementing sequence number so replay order matches
+// enqueue order.
+var walBucket = []byte("pending")
+
+// wal is the on-disk write-ahead log AsyncWriter spills every enqueued
+// detection to before acknowledging it, so a ClickHouse outage or a
+// process crash between Enqueue and a successful flush doesn't lose rows.
+// It mirrors pkg/evidencepool's BoltPool: one bbolt file, one bucket,
+// entries removed once they're durably flushed.
+type wal struct {
+	db *bolt.DB
+}
+
+// openWAL opens (creating if necessary) the BoltDB-backed WAL file under
+// dir.
+func openWAL(dir string) (*wal, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("create wal dir: %w", err)
+	}
+
+	path := filepath.Join(dir, "async_writer.db")
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open wal: %w", err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(walBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create wal bucket: %w", err)
+	}
+
+	return &wal{db: db}, nil
+}
+
+// append durably writes detection and returns the sequence number it was
+// stored under, for later removal once it's been flushed.
+func (w *wal) append(detection *Detection) (uint64, error) {
+	var seq uint64
+	err := w.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(walBucket)
+
+		var err error
+		seq, err = bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(detection)
+		if err != nil {
+			return fmt.Errorf("encode wal entry: %w", err)
+		}
+		return bucket.Put(seqKey(seq), data)
+	})
+	return seq, err
+}
+
+// walEntry pairs a WAL sequence number with the detection stored under it.
+type walEntry struct {
+	seq       uint64
+	detection *Detection
+}
+
+// pending returns every entry still in the WAL, in sequence order, e.g. for
+// NewAsyncWriter to replay after an unclean shutdown.
+func (w *wal) pending() ([]walEntry, error) {
+	var entries []walEntry
+	err := w.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(walBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var detection Detection
+			if err := json.Unmarshal(v, &detection); err != nil {
+				return fmt.Errorf("decode wal entry: %w", err)
+			}
+			entries = append(entries, walEntry{seq: binary.BigEndian.Uint64(k), detection: &detection})
+			return nil
+		})
+	})
+	return entries, err
+}
+
+// remove deletes the WAL entries for seqs, e.g. once they've been
+// successfully flushed to ClickHouse.
+func (w *wal) remove(seqs []uint64) error {
+	return w.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(walBucket)
+		for _, seq := range seqs {
+			if err := bucket.Delete(seqKey(seq)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (w *wal) close() error {
+	return w.db.Close()
+}
+
+func seqKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
@@ -10,6 +10,11 @@ import (
 
 	_ "github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/google/uuid"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/identifier"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/metrics"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/redact"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Detection represents a detection to be written to ClickHouse
@@ -31,11 +36,16 @@ type Evidence struct {
 	Source            string
 	ScoreContribution int
 	Snippet           string
+	// Redacted is true if Snippet had one or more secrets/PII masked,
+	// either by the originating probe or by Writer's own belt-and-braces
+	// pkg/redact pass (see WriteDetection/WriteBatch).
+	Redacted bool
 }
 
 // Writer writes detections to ClickHouse
 type Writer struct {
-	db *sql.DB
+	db     *sql.DB
+	logger logging.Logger
 }
 
 // Config holds ClickHouse configuration
@@ -46,8 +56,13 @@ type Config struct {
 	ConnMaxLifetime time.Duration
 }
 
-// NewWriter creates a new ClickHouse writer
-func NewWriter(config *Config) (*Writer, error) {
+// NewWriter creates a new ClickHouse writer. A nil logger falls back to a
+// discarding logger so existing callers are not forced to supply one.
+func NewWriter(config *Config, logger logging.Logger) (*Writer, error) {
+	if logger == nil {
+		logger = logging.Noop()
+	}
+
 	db, err := sql.Open("clickhouse", config.DSN)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open ClickHouse connection: %w", err)
@@ -63,7 +78,17 @@ func NewWriter(config *Config) (*Writer, error) {
 		return nil, fmt.Errorf("failed to ping ClickHouse: %w", err)
 	}
 
-	return &Writer{db: db}, nil
+	logger.Info("clickhouse connection established", "max_open_conns", config.MaxOpenConns)
+
+	return &Writer{db: db, logger: logger}, nil
+}
+
+// boolToUint8 converts a Go bool to ClickHouse's UInt8 Bool representation.
+func boolToUint8(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
 }
 
 // WriteDetection writes a detection to ClickHouse
@@ -78,18 +103,26 @@ func (w *Writer) WriteDetection(ctx context.Context, detection *Detection) error
 	var evidenceSources []string
 	var evidenceScores []uint8
 	var evidenceSnippets []string
+	var evidenceRedacted []uint8
 
 	for _, ev := range detection.Evidence {
 		evidenceTypes = append(evidenceTypes, ev.Type)
 		evidenceSources = append(evidenceSources, ev.Source)
 		evidenceScores = append(evidenceScores, uint8(ev.ScoreContribution))
 
+		// Belt-and-braces redaction pass: a probe that forgot to redact,
+		// or a future probe that doesn't redact at all, still can't land
+		// a raw secret in a table with 90-day retention (FR-029).
+		redacted := redact.String(ev.Snippet)
+		metrics.RedactionsPerDetection.Observe(float64(redacted.Count))
+		snippet := redacted.Text
+
 		// Truncate snippet to 1KB for privacy (FR-009)
-		snippet := ev.Snippet
 		if len(snippet) > 1024 {
 			snippet = snippet[:1024]
 		}
 		evidenceSnippets = append(evidenceSnippets, snippet)
+		evidenceRedacted = append(evidenceRedacted, boolToUint8(ev.Redacted || redacted.Count > 0))
 	}
 
 	// Insert query
@@ -105,8 +138,9 @@ func (w *Writer) WriteDetection(ctx context.Context, detection *Detection) error
 			evidence.source,
 			evidence.score_contribution,
 			evidence.snippet,
+			evidence.redacted,
 			judge_available
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := w.db.ExecContext(
@@ -122,6 +156,7 @@ func (w *Writer) WriteDetection(ctx context.Context, detection *Detection) error
 		evidenceSources,
 		evidenceScores,
 		evidenceSnippets,
+		evidenceRedacted,
 		detection.JudgeAvailable,
 	)
 
@@ -156,8 +191,9 @@ func (w *Writer) WriteBatch(ctx context.Context, detections []*Detection) error
 			evidence.source,
 			evidence.score_contribution,
 			evidence.snippet,
+			evidence.redacted,
 			judge_available
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
@@ -175,17 +211,21 @@ func (w *Writer) WriteBatch(ctx context.Context, detections []*Detection) error
 		var evidenceSources []string
 		var evidenceScores []uint8
 		var evidenceSnippets []string
+		var evidenceRedacted []uint8
 
 		for _, ev := range detection.Evidence {
 			evidenceTypes = append(evidenceTypes, ev.Type)
 			evidenceSources = append(evidenceSources, ev.Source)
 			evidenceScores = append(evidenceScores, uint8(ev.ScoreContribution))
 
-			snippet := ev.Snippet
+			redacted := redact.String(ev.Snippet)
+			metrics.RedactionsPerDetection.Observe(float64(redacted.Count))
+			snippet := redacted.Text
 			if len(snippet) > 1024 {
 				snippet = snippet[:1024]
 			}
 			evidenceSnippets = append(evidenceSnippets, snippet)
+			evidenceRedacted = append(evidenceRedacted, boolToUint8(ev.Redacted || redacted.Count > 0))
 		}
 
 		_, err := stmt.ExecContext(
@@ -200,6 +240,7 @@ func (w *Writer) WriteBatch(ctx context.Context, detections []*Detection) error
 			evidenceSources,
 			evidenceScores,
 			evidenceSnippets,
+			evidenceRedacted,
 			detection.JudgeAvailable,
 		)
 		if err != nil {
@@ -211,102 +252,120 @@ func (w *Writer) WriteBatch(ctx context.Context, detections []*Detection) error
 		return fmt.Errorf("failed to commit batch: %w", err)
 	}
 
+	w.logger.Debug("wrote detection batch", "count", len(detections))
+
 	return nil
 }
 
+// RehashHostIDs migrates detections.host_id_hash values from the legacy
+// unkeyed SHA256 format (identifier.LegacyHash) to the current keyed
+// format (identifier.Hasher), for deployments moving onto chunk3-5's
+// HOST_ID_HMAC_KEY.
+//
+// ClickHouse only ever stores the hash, never the host_id it came from
+// (FR-008), so a legacy row can't be re-derived from what's already in
+// the table — there's nothing to "migrate" without the plaintext. This
+// helper instead takes hostIDs supplied by the caller (e.g. the current
+// agent fleet's known host IDs, or a host inventory) and rewrites any row
+// whose hash matches that host's legacy hash to its new keyed hash. Hosts
+// that have gone offline since their last legacy scan, and whose host_id
+// was therefore never observed again, keep their legacy hash forever —
+// ValidateHashFormat still accepts it.
+//
+// Gated by enabled so a migration run is an explicit, reviewed opt-in
+// rather than something that runs on every startup.
+func (w *Writer) RehashHostIDs(ctx context.Context, knownHostIDs []string, hasher *identifier.Hasher, enabled bool) (int, error) {
+	if !enabled {
+		return 0, nil
+	}
+	updated := 0
+	for _, hostID := range knownHostIDs {
+		legacy := identifier.LegacyHash(hostID)
+		current := hasher.Hash(hostID)
+		if legacy == current {
+			continue
+		}
+		res, err := w.db.ExecContext(ctx,
+			`ALTER TABLE detections UPDATE host_id_hash = ? WHERE host_id_hash = ?`,
+			current, legacy)
+		if err != nil {
+			return updated, fmt.Errorf("rehash host_id_hash: %w", err)
+		}
+		if n, err := res.RowsAffected(); err == nil && n > 0 {
+			updated++
+		}
+	}
+	return updated, nil
+}
+
 // QueryDetections queries detections from ClickHouse
 func (w *Writer) QueryDetections(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, "QueryDetections", query)
+	defer span.End()
+
 	rows, err := w.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query detections: %w", err)
+		return nil, spanError(span, fmt.Errorf("failed to query detections: %w", err))
 	}
 	return rows, nil
 }
 
 // GetDetectionCount returns the total number of detections
 func (w *Writer) GetDetectionCount(ctx context.Context) (int64, error) {
+	query := "SELECT count() FROM detections"
+	ctx, span := startQuerySpan(ctx, "GetDetectionCount", query)
+	defer span.End()
+
 	var count int64
-	err := w.db.QueryRowContext(ctx, "SELECT count() FROM detections").Scan(&count)
+	err := w.db.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get detection count: %w", err)
+		return 0, spanError(span, fmt.Errorf("failed to get detection count: %w", err))
 	}
+	span.SetAttributes(attribute.Int64("db.rows_affected", count))
 	return count, nil
 }
 
-// GetDetectionsByTimeRange queries detections within a time range
+// GetDetectionsByTimeRange queries detections within a time range. It
+// loads the entire result set into memory before returning; callers
+// walking a wide or unbounded time range should use StreamDetections or
+// PageDetections instead.
 func (w *Writer) GetDetectionsByTimeRange(
 	ctx context.Context,
 	startTime time.Time,
 	endTime time.Time,
 	limit int,
 ) ([]*Detection, error) {
-	query := `
-		SELECT
-			detection_id,
-			timestamp,
-			host_id_hash,
-			composite_id,
-			score,
-			classification,
-			evidence.type,
-			evidence.source,
-			evidence.score_contribution,
-			evidence.snippet,
-			judge_available
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM detections
 		WHERE timestamp >= ? AND timestamp < ?
 		ORDER BY timestamp DESC
 		LIMIT ?
-	`
+	`, selectDetectionColumns)
+
+	ctx, span := startQuerySpan(ctx, "GetDetectionsByTimeRange", query)
+	defer span.End()
 
 	rows, err := w.db.QueryContext(ctx, query, startTime, endTime, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query detections: %w", err)
+		return nil, spanError(span, fmt.Errorf("failed to query detections: %w", err))
 	}
 	defer rows.Close()
 
 	var detections []*Detection
 	for rows.Next() {
-		var detection Detection
-		var evidenceTypes []string
-		var evidenceSources []string
-		var evidenceScores []uint8
-		var evidenceSnippets []string
-
-		err := rows.Scan(
-			&detection.DetectionID,
-			&detection.Timestamp,
-			&detection.HostIDHash,
-			&detection.CompositeID,
-			&detection.Score,
-			&detection.Classification,
-			&evidenceTypes,
-			&evidenceSources,
-			&evidenceScores,
-			&evidenceSnippets,
-			&detection.JudgeAvailable,
-		)
+		detection, err := scanDetectionRow(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			return nil, spanError(span, err)
 		}
-
-		// Reconstruct evidence
-		for i := range evidenceTypes {
-			detection.Evidence = append(detection.Evidence, Evidence{
-				Type:              evidenceTypes[i],
-				Source:            evidenceSources[i],
-				ScoreContribution: int(evidenceScores[i]),
-				Snippet:           evidenceSnippets[i],
-			})
-		}
-
-		detections = append(detections, &detection)
+		detections = append(detections, detection)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
+		return nil, spanError(span, fmt.Errorf("row iteration error: %w", err))
 	}
 
+	span.SetAttributes(attribute.Int("db.rows_affected", len(detections)))
 	return detections, nil
 }
 
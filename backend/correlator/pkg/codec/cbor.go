@@ -0,0 +1,21 @@
+package codec
+
+import "github.com/fxamacker/cbor/v2"
+
+// CBORCodec encodes detection events as CBOR, a compact binary encoding that
+// preserves JSON's flexible shape (maps, arrays, arbitrary nesting) without
+// needing a fixed schema like the protobuf codec does.
+type CBORCodec struct{}
+
+// Name implements Codec.
+func (CBORCodec) Name() string { return "cbor" }
+
+// Encode implements Codec.
+func (CBORCodec) Encode(v any) ([]byte, error) {
+	return cbor.Marshal(v)
+}
+
+// Decode implements Codec.
+func (CBORCodec) Decode(data []byte, v any) error {
+	return cbor.Unmarshal(data, v)
+}
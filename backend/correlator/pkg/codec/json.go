@@ -0,0 +1,19 @@
+package codec
+
+import "encoding/json"
+
+// JSONCodec is the default Codec, preserving today's encoding/json behavior.
+type JSONCodec struct{}
+
+// Name implements Codec.
+func (JSONCodec) Name() string { return "json" }
+
+// Encode implements Codec.
+func (JSONCodec) Encode(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Decode implements Codec.
+func (JSONCodec) Decode(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
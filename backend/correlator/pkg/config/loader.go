@@ -1,29 +1,37 @@
 // Package config provides YAML configuration loading for the correlator service.
+// Every global.yaml/correlator.yaml is validated against an embedded JSON
+// Schema (see validate.go) before it is unmarshalled, so malformed config
+// fails fast with every violation listed, not just the first.
 // Reference: FR-015 (declarative YAML configuration), FR-016 (JSON Schema validation)
 package config
 
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/registry"
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the correlator service configuration
 type Config struct {
-	Global      GlobalConfig      `yaml:"global"`
-	Correlator  CorrelatorConfig  `yaml:"correlator"`
-	NATS        NATSConfig        `yaml:"nats"`
-	ClickHouse  ClickHouseConfig  `yaml:"clickhouse"`
-	PostgreSQL  PostgreSQLConfig  `yaml:"postgresql"`
+	Global        GlobalConfig        `yaml:"global"`
+	Correlator    CorrelatorConfig    `yaml:"correlator"`
+	NATS          NATSConfig          `yaml:"nats"`
+	Broker        BrokerConfig        `yaml:"broker"`
+	ClickHouse    ClickHouseConfig    `yaml:"clickhouse"`
+	PostgreSQL    PostgreSQLConfig    `yaml:"postgresql"`
 	Observability ObservabilityConfig `yaml:"observability"`
+	Notifier      NotifierConfig      `yaml:"notifier"`
 }
 
 // GlobalConfig contains settings shared across all services
 type GlobalConfig struct {
 	Environment string `yaml:"environment"`
 	LogLevel    string `yaml:"log_level"`
+	LogFormat   string `yaml:"log_format"` // "text" or "json"
 	Version     string `yaml:"version"`
 }
 
@@ -46,6 +54,16 @@ type CorrelatorConfig struct {
 
 	// Batch timeout for ClickHouse inserts
 	BatchTimeout string `yaml:"batch_timeout"`
+
+	// MaxDeliveries caps retries of a transient consumer failure (e.g. a
+	// ClickHouse outage) before it's dead-lettered instead of redelivered
+	// again. See pkg/consumer.Config.MaxDeliveries.
+	MaxDeliveries int `yaml:"max_deliveries"`
+
+	// DeadLetterSuffix is appended to a subject to build the subject a
+	// permanently-failed or retry-exhausted message is published to. See
+	// pkg/consumer.Config.DeadLetterSuffix.
+	DeadLetterSuffix string `yaml:"dead_letter_suffix"`
 }
 
 // ScoringWeights defines weights for different signal types
@@ -57,15 +75,29 @@ type ScoringWeights struct {
 
 // NATSConfig contains NATS JetStream connection settings
 type NATSConfig struct {
-	URL             string        `yaml:"url"`
-	ConsumerGroup   string        `yaml:"consumer_group"`
-	MaxReconnects   int           `yaml:"max_reconnects"`
-	ReconnectWait   time.Duration `yaml:"reconnect_wait"`
-	Timeout         time.Duration `yaml:"timeout"`
-	TLSEnabled      bool          `yaml:"tls_enabled"`
-	TLSCertFile     string        `yaml:"tls_cert_file"`
-	TLSKeyFile      string        `yaml:"tls_key_file"`
-	TLSCAFile       string        `yaml:"tls_ca_file"`
+	URL           string        `yaml:"url"`
+	ConsumerGroup string        `yaml:"consumer_group"`
+	MaxReconnects int           `yaml:"max_reconnects"`
+	ReconnectWait time.Duration `yaml:"reconnect_wait"`
+	Timeout       time.Duration `yaml:"timeout"`
+	TLSEnabled    bool          `yaml:"tls_enabled"`
+	TLSCertFile   string        `yaml:"tls_cert_file"`
+	TLSKeyFile    string        `yaml:"tls_key_file"`
+	TLSCAFile     string        `yaml:"tls_ca_file"`
+	NKeySeedFile  string        `yaml:"nkey_seed_file"`
+	CredsFile     string        `yaml:"creds_file"`
+	Token         string        `yaml:"token"`
+}
+
+// BrokerConfig selects the message broker backend and holds backend-specific
+// settings not already covered by NATSConfig.
+type BrokerConfig struct {
+	Type string `yaml:"type"` // "nats" (default) or "amqp"
+
+	AMQPUrl        string `yaml:"amqp_url"`
+	AMQPExchange   string `yaml:"amqp_exchange"`
+	AMQPRoutingKey string `yaml:"amqp_routing_key"`
+	AMQPPersistent bool   `yaml:"amqp_persistent"`
 }
 
 // ClickHouseConfig contains ClickHouse connection settings
@@ -97,6 +129,35 @@ type PostgreSQLConfig struct {
 	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
 }
 
+// NotifierConfig configures fan-out alerting for high-score detections.
+// See pkg/notifier.
+type NotifierConfig struct {
+	// Threshold is the default minimum score a detection event must reach
+	// to be dispatched. Providers may override it per-provider.
+	Threshold int `yaml:"threshold"`
+
+	// DetectionTypes is the default allowlist of detection types to
+	// notify on (e.g. ["process", "network"]). Empty allows all types.
+	// Providers may override it per-provider.
+	DetectionTypes []string `yaml:"detection_types"`
+
+	Providers []NotifierProviderConfig `yaml:"providers"`
+}
+
+// NotifierProviderConfig configures a single notification provider.
+type NotifierProviderConfig struct {
+	Type string `yaml:"type"` // "slack", "pagerduty", "generic", or "generic-hmac"
+	Name string `yaml:"name"`
+
+	URL        string `yaml:"url"`         // generic, generic-hmac
+	Secret     string `yaml:"secret"`      // generic-hmac
+	WebhookURL string `yaml:"webhook_url"` // slack
+	RoutingKey string `yaml:"routing_key"` // pagerduty
+
+	Threshold      int      `yaml:"threshold"`
+	DetectionTypes []string `yaml:"detection_types"`
+}
+
 // ObservabilityConfig contains metrics and logging settings
 type ObservabilityConfig struct {
 	MetricsPort        int  `yaml:"metrics_port"`
@@ -110,12 +171,18 @@ func LoadConfig(configDir string) (*Config, error) {
 
 	// Load global configuration
 	globalPath := fmt.Sprintf("%s/global.yaml", configDir)
+	if err := ValidateFile(globalPath); err != nil {
+		return nil, err
+	}
 	if err := loadYAMLFile(globalPath, config); err != nil {
 		return nil, fmt.Errorf("failed to load global.yaml: %w", err)
 	}
 
 	// Load correlator-specific configuration
 	correlatorPath := fmt.Sprintf("%s/correlator.yaml", configDir)
+	if err := ValidateFile(correlatorPath); err != nil {
+		return nil, err
+	}
 	if err := loadYAMLFile(correlatorPath, config); err != nil {
 		return nil, fmt.Errorf("failed to load correlator.yaml: %w", err)
 	}
@@ -205,6 +272,76 @@ func (c *Config) GetRegistryAuthToken() string {
 	return os.Getenv("REGISTRY_AUTH_TOKEN")
 }
 
+// GetRegistryAuthScheme returns how REGISTRY_AUTH_TOKEN is presented to the
+// registry API: "bearer" (default, for JWTs issued by mcpeekerctl token
+// issue) or "token" for a static bearer token.
+func (c *Config) GetRegistryAuthScheme() string {
+	if scheme := os.Getenv("REGISTRY_AUTH_SCHEME"); scheme != "" {
+		return scheme
+	}
+	return registry.AuthSchemeBearer
+}
+
+// GetRegistryMaxRetries returns how many times CheckMatch retries a
+// 5xx/429/network failure before giving up, from REGISTRY_MAX_RETRIES.
+func (c *Config) GetRegistryMaxRetries() int {
+	if n := envInt("REGISTRY_MAX_RETRIES"); n >= 0 {
+		return n
+	}
+	return registry.DefaultRetryConfig.MaxRetries
+}
+
+// GetRegistryCircuitBreakerThreshold returns the number of consecutive
+// CheckMatch failures that trips the registry circuit breaker open, from
+// REGISTRY_CIRCUIT_BREAKER_THRESHOLD.
+func (c *Config) GetRegistryCircuitBreakerThreshold() int {
+	if n := envInt("REGISTRY_CIRCUIT_BREAKER_THRESHOLD"); n >= 0 {
+		return n
+	}
+	return registry.DefaultCircuitBreakerConfig.FailureThreshold
+}
+
+// GetRegistryCacheTTL returns how long CheckMatch serves a cached
+// response before looking up the registry again, from
+// REGISTRY_CACHE_TTL_SECONDS.
+func (c *Config) GetRegistryCacheTTL() time.Duration {
+	if n := envInt("REGISTRY_CACHE_TTL_SECONDS"); n >= 0 {
+		return time.Duration(n) * time.Second
+	}
+	return registry.DefaultCacheConfig.TTL
+}
+
+// envInt parses name as a non-negative integer, returning -1 if unset or
+// invalid so callers can tell "not configured" apart from an explicit 0.
+func envInt(name string) int {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return -1
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
+
+// GetTrustedProxies returns the comma-separated trusted-proxy CIDR list
+// (see pkg/httpx.RealIP) from TRUSTED_PROXIES, e.g. "10.0.0.0/8,127.0.0.1/32".
+func (c *Config) GetTrustedProxies() string {
+	return os.Getenv("TRUSTED_PROXIES")
+}
+
+// GetEvidencePoolPath returns the BoltDB file path for the persistent
+// evidence pool (see pkg/evidencepool), defaulting to a path under
+// /var/lib/mcpeeker. Set EVIDENCE_POOL_PATH to relocate it, or to ":memory:"
+// to fall back to the non-persistent in-memory pool (tests only).
+func (c *Config) GetEvidencePoolPath() string {
+	if path := os.Getenv("EVIDENCE_POOL_PATH"); path != "" {
+		return path
+	}
+	return "/var/lib/mcpeeker/evidence-pool.db"
+}
+
 func (c *Config) GetMetricsPort() string {
 	return fmt.Sprintf(":%d", c.Observability.MetricsPort)
 }
@@ -215,42 +352,119 @@ func (c *Config) GetHealthPort() string {
 
 // Flattened config structure for easier access
 type FlatConfig struct {
-	DedupWindow            time.Duration
-	WeightEndpoint         int
-	WeightJudge            int
-	WeightNetwork          int
-	RegistryPenalty        int
-	ThresholdAuthorized    int
-	ThresholdSuspect       int
-	ThresholdUnauthorized  int
-	ClickHouseURL          string
-	ClickHouseDSN          string
-	NATSUrl                string
-	RegistryAPIURL         string
-	RegistryAuthToken      string
-	MetricsPort            string
-	HealthPort             string
+	DedupWindow           time.Duration
+	WeightEndpoint        int
+	WeightJudge           int
+	WeightNetwork         int
+	RegistryPenalty       int
+	ThresholdAuthorized   int
+	ThresholdSuspect      int
+	ThresholdUnauthorized int
+	BatchSize             int
+	MaxDeliveries         int
+	DeadLetterSuffix      string
+	ClickHouseURL         string
+	ClickHouseDSN         string
+	NATSUrl               string
+	NATSTLSEnabled        bool
+	NATSTLSCertFile       string
+	NATSTLSKeyFile        string
+	NATSTLSCAFile         string
+	NATSNKeySeedFile      string
+	NATSCredsFile         string
+	NATSToken             string
+	BrokerType            string
+	AMQPUrl               string
+	AMQPExchange          string
+	AMQPRoutingKey        string
+	AMQPPersistent        bool
+	RegistryAPIURL        string
+	RegistryAuthToken     string
+	RegistryAuthScheme    string
+	TrustedProxies        string
+	EvidencePoolPath      string
+	MetricsPort           string
+	HealthPort            string
+	LogLevel              string
+	LogFormat             string
+
+	// RegistryMaxRetries, RegistryCircuitBreakerThreshold, and
+	// RegistryCacheTTL tune registry.Client's resilience behavior; see
+	// GetRegistryMaxRetries/GetRegistryCircuitBreakerThreshold/GetRegistryCacheTTL.
+	RegistryMaxRetries              int
+	RegistryCircuitBreakerThreshold int
+	RegistryCacheTTL                time.Duration
+
+	// Notifier is carried through unflattened since its provider list
+	// does not reduce to scalar fields the way the rest of FlatConfig does.
+	Notifier NotifierConfig
 }
 
 // Flatten converts nested config to flat structure
 func (c *Config) Flatten() *FlatConfig {
+	batchSize := c.Correlator.BatchSize
+	if batchSize <= 0 {
+		batchSize = 10
+	}
+
 	return &FlatConfig{
-		DedupWindow:            c.GetDedupWindow(),
-		WeightEndpoint:         c.Correlator.ScoringWeights.Endpoint,
-		WeightJudge:            c.Correlator.ScoringWeights.Judge,
-		WeightNetwork:          c.Correlator.ScoringWeights.Network,
-		RegistryPenalty:        c.Correlator.RegistryPenalty,
-		ThresholdAuthorized:    4,  // Default thresholds per FR-003
-		ThresholdSuspect:       8,
-		ThresholdUnauthorized:  9,
-		ClickHouseURL:          fmt.Sprintf("http://%s:%d", c.ClickHouse.Host, c.ClickHouse.Port),
-		ClickHouseDSN:          c.GetClickHouseDSN(),
-		NATSUrl:                c.GetNATSUrl(),
-		RegistryAPIURL:         c.GetRegistryAPIURL(),
-		RegistryAuthToken:      c.GetRegistryAuthToken(),
-		MetricsPort:            c.GetMetricsPort(),
-		HealthPort:             c.GetHealthPort(),
+		DedupWindow:           c.GetDedupWindow(),
+		WeightEndpoint:        c.Correlator.ScoringWeights.Endpoint,
+		WeightJudge:           c.Correlator.ScoringWeights.Judge,
+		WeightNetwork:         c.Correlator.ScoringWeights.Network,
+		RegistryPenalty:       c.Correlator.RegistryPenalty,
+		ThresholdAuthorized:   4, // Default thresholds per FR-003
+		ThresholdSuspect:      8,
+		ThresholdUnauthorized: 9,
+		BatchSize:             batchSize,
+		MaxDeliveries:         c.Correlator.MaxDeliveries,
+		DeadLetterSuffix:      c.Correlator.DeadLetterSuffix,
+		ClickHouseURL:         fmt.Sprintf("http://%s:%d", c.ClickHouse.Host, c.ClickHouse.Port),
+		ClickHouseDSN:         c.GetClickHouseDSN(),
+		NATSUrl:               c.GetNATSUrl(),
+		NATSTLSEnabled:        c.NATS.TLSEnabled,
+		NATSTLSCertFile:       c.NATS.TLSCertFile,
+		NATSTLSKeyFile:        c.NATS.TLSKeyFile,
+		NATSTLSCAFile:         c.NATS.TLSCAFile,
+		NATSNKeySeedFile:      c.NATS.NKeySeedFile,
+		NATSCredsFile:         c.NATS.CredsFile,
+		NATSToken:             c.NATS.Token,
+		BrokerType:            c.Broker.Type,
+		AMQPUrl:               c.Broker.AMQPUrl,
+		AMQPExchange:          c.Broker.AMQPExchange,
+		AMQPRoutingKey:        c.Broker.AMQPRoutingKey,
+		AMQPPersistent:        c.Broker.AMQPPersistent,
+		RegistryAPIURL:        c.GetRegistryAPIURL(),
+		RegistryAuthToken:     c.GetRegistryAuthToken(),
+		RegistryAuthScheme:    c.GetRegistryAuthScheme(),
+		TrustedProxies:        c.GetTrustedProxies(),
+		EvidencePoolPath:      c.GetEvidencePoolPath(),
+		MetricsPort:           c.GetMetricsPort(),
+		HealthPort:            c.GetHealthPort(),
+		LogLevel:              c.GetLogLevel(),
+		LogFormat:             c.GetLogFormat(),
+		Notifier:              c.Notifier,
+
+		RegistryMaxRetries:              c.GetRegistryMaxRetries(),
+		RegistryCircuitBreakerThreshold: c.GetRegistryCircuitBreakerThreshold(),
+		RegistryCacheTTL:                c.GetRegistryCacheTTL(),
+	}
+}
+
+// GetLogLevel returns the configured log level, defaulting to "info".
+func (c *Config) GetLogLevel() string {
+	if c.Global.LogLevel == "" {
+		return "info"
+	}
+	return c.Global.LogLevel
+}
+
+// GetLogFormat returns the configured log format, defaulting to "text".
+func (c *Config) GetLogFormat() string {
+	if c.Global.LogFormat == "" {
+		return "text"
 	}
+	return c.Global.LogFormat
 }
 
 // Load is an alias for LoadConfig
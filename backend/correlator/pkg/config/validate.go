@@ -0,0 +1,89 @@
+package config
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed schemas/global.schema.json schemas/correlator.schema.json
+var schemaFS embed.FS
+
+var (
+	globalSchema     *gojsonschema.Schema
+	correlatorSchema *gojsonschema.Schema
+)
+
+func init() {
+	var err error
+	if globalSchema, err = compileSchema("schemas/global.schema.json"); err != nil {
+		panic(fmt.Sprintf("config: failed to compile global.schema.json: %v", err))
+	}
+	if correlatorSchema, err = compileSchema("schemas/correlator.schema.json"); err != nil {
+		panic(fmt.Sprintf("config: failed to compile correlator.schema.json: %v", err))
+	}
+}
+
+func compileSchema(path string) (*gojsonschema.Schema, error) {
+	data, err := schemaFS.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return gojsonschema.NewSchema(gojsonschema.NewBytesLoader(data))
+}
+
+// ValidateFile validates a single YAML config file (global.yaml or
+// correlator.yaml, identified by its base name) against the matching
+// embedded JSON Schema. All violations are aggregated into one error so
+// operators see every problem in a single CI run instead of fixing them one
+// at a time.
+func ValidateFile(path string) error {
+	schema, err := schemaForFile(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to parse YAML from %s: %w", path, err)
+	}
+
+	return validateAgainstSchema(path, schema, raw)
+}
+
+func schemaForFile(path string) (*gojsonschema.Schema, error) {
+	switch {
+	case strings.HasSuffix(path, "global.yaml"):
+		return globalSchema, nil
+	case strings.HasSuffix(path, "correlator.yaml"):
+		return correlatorSchema, nil
+	default:
+		return nil, fmt.Errorf("no JSON Schema registered for %s", path)
+	}
+}
+
+func validateAgainstSchema(path string, schema *gojsonschema.Schema, raw interface{}) error {
+	result, err := schema.Validate(gojsonschema.NewGoLoader(raw))
+	if err != nil {
+		return fmt.Errorf("%s: schema validation error: %w", path, err)
+	}
+
+	if result.Valid() {
+		return nil
+	}
+
+	violations := make([]string, 0, len(result.Errors()))
+	for _, desc := range result.Errors() {
+		violations = append(violations, fmt.Sprintf("%s: %s: %s", path, desc.Field(), desc.Description()))
+	}
+	return fmt.Errorf("config validation failed:\n%s", strings.Join(violations, "\n"))
+}
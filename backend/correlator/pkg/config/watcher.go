@@ -0,0 +1,113 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/metrics"
+)
+
+// Watcher holds the current validated configuration snapshot and refreshes
+// it on SIGHUP or a change to a file in configDir, without requiring a
+// process restart. Reload is all-or-nothing: a validation failure leaves the
+// previous snapshot in place and increments mcpeeker_config_reload_failed_total.
+type Watcher struct {
+	configDir string
+	logger    logging.Logger
+	normalize func(*FlatConfig)
+	current   atomic.Pointer[FlatConfig]
+}
+
+// NewWatcherWithSnapshot wraps an already-loaded configuration snapshot (the
+// one read during startup, before logging was configured) with reload
+// machinery, avoiding a redundant initial read.
+func NewWatcherWithSnapshot(configDir string, initial *FlatConfig, logger logging.Logger, normalize func(*FlatConfig)) *Watcher {
+	w := &Watcher{configDir: configDir, logger: logger, normalize: normalize}
+	w.current.Store(initial)
+	return w
+}
+
+// Current returns the most recently accepted configuration snapshot.
+func (w *Watcher) Current() *FlatConfig {
+	return w.current.Load()
+}
+
+// Watch blocks, reloading on SIGHUP and on changes to files in configDir,
+// until ctx is cancelled. Intended to run in its own goroutine.
+func (w *Watcher) Watch(ctx context.Context) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		w.logger.Warn("config file watcher unavailable, reload via SIGHUP only", "error", err)
+	} else {
+		defer fsWatcher.Close()
+		if err := fsWatcher.Add(w.configDir); err != nil {
+			w.logger.Warn("failed to watch config directory", "dir", w.configDir, "error", err)
+		}
+	}
+
+	var fsEvents <-chan fsnotify.Event
+	var fsErrors <-chan error
+	if fsWatcher != nil {
+		fsEvents = fsWatcher.Events
+		fsErrors = fsWatcher.Errors
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigChan:
+			w.logger.Info("received SIGHUP, reloading configuration")
+			w.reload()
+		case event, ok := <-fsEvents:
+			if !ok {
+				fsEvents = nil
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				w.logger.Info("config file changed, reloading configuration", "file", event.Name)
+				w.reload()
+			}
+		case err, ok := <-fsErrors:
+			if !ok {
+				fsErrors = nil
+				continue
+			}
+			w.logger.Warn("config file watcher error", "error", err)
+		}
+	}
+}
+
+// reload re-reads and re-validates configuration, swapping in the new
+// snapshot only on success. On failure the previous snapshot stays active.
+func (w *Watcher) reload() {
+	flat, err := w.loadAndValidate()
+	if err != nil {
+		w.logger.Error("config reload rejected, keeping previous configuration", "error", err)
+		metrics.ConfigReloadFailedTotal.Inc()
+		return
+	}
+	w.current.Store(flat)
+	w.logger.Info("configuration reloaded")
+}
+
+func (w *Watcher) loadAndValidate() (*FlatConfig, error) {
+	cfg, err := LoadConfig(w.configDir)
+	if err != nil {
+		return nil, err
+	}
+	flat := cfg.Flatten()
+	if w.normalize != nil {
+		w.normalize(flat)
+	}
+	return flat, nil
+}
@@ -0,0 +1,158 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// headersFromTable converts AMQP's loosely-typed amqp.Table into the plain
+// map[string]string BrokerMessage.Headers expects, stringifying any
+// non-string values rather than dropping them.
+func headersFromTable(table amqp.Table) map[string]string {
+	headers := make(map[string]string, len(table))
+	for k, v := range table {
+		if s, ok := v.(string); ok {
+			headers[k] = s
+			continue
+		}
+		headers[k] = fmt.Sprintf("%v", v)
+	}
+	return headers
+}
+
+// AMQPBroker consumes messages from a RabbitMQ queue, implementing
+// MessageBroker over amqp091-go's push-based delivery model.
+type AMQPBroker struct {
+	conn *amqp.Connection
+	ch   *amqp.Channel
+	url  string
+}
+
+// amqpBrokerMessage adapts amqp.Delivery to the BrokerMessage interface.
+type amqpBrokerMessage struct {
+	delivery amqp.Delivery
+}
+
+func (m amqpBrokerMessage) Data() []byte { return m.delivery.Body }
+func (m amqpBrokerMessage) Ack() error   { return m.delivery.Ack(false) }
+func (m amqpBrokerMessage) Nak() error   { return m.delivery.Nack(false, true) }
+
+// NakWithDelay requeues immediately: amqp091-go has no native delayed
+// redelivery (that requires a broker-side delayed-message-exchange plugin),
+// so this degrades to the same behavior as Nak.
+func (m amqpBrokerMessage) NakWithDelay(delay time.Duration) error {
+	return m.Nak()
+}
+
+// NumDelivered approximates JetStream's delivery count: amqp091-go only
+// exposes a Redelivered bool, not an exact count, so a redelivered message
+// is reported as attempt 2 regardless of how many times it actually
+// bounced. With Config.MaxDeliveries above 2, a transient failure on AMQP
+// is therefore never treated as exhausted and is requeued without limit.
+func (m amqpBrokerMessage) NumDelivered() int {
+	if m.delivery.Redelivered {
+		return 2
+	}
+	return 1
+}
+
+func (m amqpBrokerMessage) Headers() map[string]string {
+	return headersFromTable(m.delivery.Headers)
+}
+
+// newAMQPBroker dials RabbitMQ for consumption. Unlike the publisher side,
+// the consumer does not declare exchanges — it expects the queues named by
+// each Subscribe subject to already exist (bound by deployment tooling).
+func newAMQPBroker(config *Config) (*AMQPBroker, error) {
+	var conn *amqp.Connection
+	var err error
+	if config.TLSEnabled {
+		tlsConfig, tlsErr := loadTLSConfig(config.TLSCertFile, config.TLSKeyFile, config.TLSCAFile)
+		if tlsErr != nil {
+			return nil, fmt.Errorf("mTLS enabled but failed to load certificate bundle: %w", tlsErr)
+		}
+		conn, err = amqp.DialTLS(config.AMQPUrl, tlsConfig)
+	} else {
+		conn, err = amqp.Dial(config.AMQPUrl)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to AMQP broker: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open AMQP channel: %w", err)
+	}
+
+	return &AMQPBroker{conn: conn, ch: ch, url: config.AMQPUrl}, nil
+}
+
+// Subscribe consumes queue (named by subject) and delivers each message to
+// handler until ctx is cancelled. batchSize is accepted for interface
+// parity with NATSBroker but unused: AMQP pushes deliveries as the broker's
+// own prefetch/QoS settings allow, rather than on a pull/fetch cycle.
+func (b *AMQPBroker) Subscribe(ctx context.Context, subject string, batchSize func() int, handler func(BrokerMessage)) error {
+	deliveries, err := b.ch.Consume(subject, "correlator", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to consume AMQP queue %s: %w", subject, err)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case delivery, ok := <-deliveries:
+				if !ok {
+					return
+				}
+				handler(amqpBrokerMessage{delivery: delivery})
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Connected reports whether the underlying AMQP connection is open.
+func (b *AMQPBroker) Connected() bool {
+	return b.conn != nil && !b.conn.IsClosed()
+}
+
+// ConnectedURL returns the AMQP URL currently connected to, or the empty
+// string if not connected.
+func (b *AMQPBroker) ConnectedURL() string {
+	if !b.Connected() {
+		return ""
+	}
+	return b.url
+}
+
+// Publish publishes data to the queue/routing key named by subject on the
+// default exchange, e.g. to forward a message to its dead-letter subject
+// (see Consumer.deadLetter).
+func (b *AMQPBroker) Publish(ctx context.Context, subject string, data []byte, headers map[string]string) error {
+	table := make(amqp.Table, len(headers))
+	for k, v := range headers {
+		table[k] = v
+	}
+	return b.ch.PublishWithContext(ctx, "", subject, false, false, amqp.Publishing{
+		Headers: table,
+		Body:    data,
+	})
+}
+
+// Close closes the AMQP channel and connection.
+func (b *AMQPBroker) Close() error {
+	if b.ch != nil {
+		b.ch.Close()
+	}
+	if b.conn != nil {
+		return b.conn.Close()
+	}
+	return nil
+}
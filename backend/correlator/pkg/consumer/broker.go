@@ -0,0 +1,75 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+)
+
+// BrokerMessage is a single broker-delivered message, abstracting over
+// *nats.Msg and amqp.Delivery so Consumer's processing logic does not care
+// which transport produced it.
+type BrokerMessage interface {
+	Data() []byte
+	// Headers returns the transport metadata sent alongside Data, e.g.
+	// event-codec/event-type (see pkg/codec and publisher.Publisher.headers).
+	// Never nil.
+	Headers() map[string]string
+	// NumDelivered returns how many times this message has been delivered,
+	// including the current attempt (1 on first delivery). Used to cap
+	// retries and compute backoff (see Consumer.handleMessage).
+	NumDelivered() int
+	Ack() error
+	Nak() error
+	// NakWithDelay negatively acknowledges the message, asking the broker
+	// not to redeliver it before delay has elapsed. Brokers without native
+	// delayed redelivery fall back to an immediate Nak.
+	NakWithDelay(delay time.Duration) error
+}
+
+// MessageBroker abstracts the transport Consumer pulls detection events
+// from, so a deployment can swap NATS JetStream for RabbitMQ (or anything
+// else) via Config.BrokerType without touching call sites.
+type MessageBroker interface {
+	// Subscribe starts consuming subject in the background, invoking handler
+	// for each message until ctx is cancelled. batchSize is polled on every
+	// fetch cycle so a hot config reload can resize throughput without
+	// resubscribing.
+	Subscribe(ctx context.Context, subject string, batchSize func() int, handler func(BrokerMessage)) error
+
+	// Connected reports whether the broker connection is currently up.
+	Connected() bool
+
+	// ConnectedURL returns the address the broker is currently connected to.
+	ConnectedURL() string
+
+	// Publish sends data to subject with headers on the existing
+	// connection. Used by Consumer to forward exhausted/permanently-failed
+	// messages to a dead-letter subject; it is not part of the
+	// Subscribe/handler consumption path.
+	Publish(ctx context.Context, subject string, data []byte, headers map[string]string) error
+
+	// Close releases the underlying connection.
+	Close() error
+}
+
+// Broker type identifiers accepted by Config.BrokerType.
+const (
+	BrokerTypeNATS = "nats"
+	BrokerTypeAMQP = "amqp"
+)
+
+// newBroker constructs the MessageBroker selected by config.BrokerType,
+// defaulting to NATS for backward compatibility with existing deployments.
+func newBroker(config *Config, logger logging.Logger) (MessageBroker, error) {
+	switch config.BrokerType {
+	case "", BrokerTypeNATS:
+		return newNATSBroker(config, logger)
+	case BrokerTypeAMQP:
+		return newAMQPBroker(config)
+	default:
+		return nil, fmt.Errorf("unsupported broker type: %q", config.BrokerType)
+	}
+}
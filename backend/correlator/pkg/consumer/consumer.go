@@ -0,0 +1,373 @@
+// Package consumer consumes detection events from a message broker (NATS
+// JetStream or RabbitMQ, see broker.go) and correlates them.
+// Reference: FR-001 (NATS messaging), FR-002 (Multi-layer detection)
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/clickhouse"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/codec"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/metrics"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/notifier"
+)
+
+// ErrPermanent and ErrTransient classify why processMessage failed, so
+// Start's handler knows whether to dead-letter a message immediately or
+// retry it with backoff. processMessage wraps the underlying cause with
+// whichever applies (e.g. fmt.Errorf("%w: failed to decode event: %w",
+// ErrPermanent, err)); callers check with errors.Is.
+var (
+	// ErrPermanent marks a failure that retrying will never fix (malformed
+	// input), so the message is dead-lettered on its first failure.
+	ErrPermanent = errors.New("consumer: permanent failure")
+	// ErrTransient marks a failure that may succeed later (a downstream
+	// outage), so the message is NAK'd with backoff up to MaxDeliveries.
+	ErrTransient = errors.New("consumer: transient failure")
+)
+
+const (
+	defaultMaxDeliveries    = 10
+	defaultDeadLetterSuffix = ".dlq"
+
+	baseRetryDelay = 1 * time.Second
+	maxRetryDelay  = 2 * time.Minute
+)
+
+// detectionWriter is the subset of clickhouse.Writer's API Consumer needs,
+// so an AsyncWriter (see pkg/clickhouse/async_writer.go) can be handed to
+// NewConsumer in its place when higher write throughput is required.
+type detectionWriter interface {
+	WriteDetection(ctx context.Context, detection *clickhouse.Detection) error
+}
+
+// Consumer consumes detection events from a message broker
+type Consumer struct {
+	broker           MessageBroker
+	correlator       *engine.Correlator
+	chWriter         detectionWriter
+	notifier         *notifier.Notifier
+	subjects         []string
+	batchSize        atomic.Int32
+	maxDeliveries    int
+	deadLetterSuffix string
+	logger           logging.Logger
+}
+
+// Config holds consumer configuration
+type Config struct {
+	NATSUrl     string
+	Subjects    []string // e.g., ["endpoint.events", "network.events", "gateway.events"]
+	StreamName  string
+	DurableName string
+	BatchSize   int
+	Logger      logging.Logger
+
+	// Notifier, if set, receives every decoded detection event and fans
+	// out alerts to whichever configured providers match its filters. A
+	// nil Notifier disables alerting entirely.
+	Notifier *notifier.Notifier
+
+	// BrokerType selects the message broker backend: "nats" (default) or
+	// "amqp". See broker.go.
+	BrokerType string
+
+	// TLSEnabled turns on mTLS for the NATS connection. When true,
+	// TLSCertFile/TLSKeyFile/TLSCAFile must all be set and loadable, or
+	// NewConsumer fails rather than connecting insecurely.
+	TLSEnabled  bool
+	TLSCertFile string
+	TLSKeyFile  string
+	TLSCAFile   string
+
+	// NKeySeedFile, CredsFile, and Token are mutually exclusive NATS
+	// authentication methods; at most one should be set.
+	NKeySeedFile string
+	CredsFile    string
+	Token        string
+
+	// AMQPUrl, AMQPExchange, AMQPRoutingKey, and AMQPPersistent configure
+	// the RabbitMQ backend when BrokerType is "amqp".
+	AMQPUrl        string
+	AMQPExchange   string
+	AMQPRoutingKey string
+	AMQPPersistent bool
+
+	// MaxDeliveries caps how many times a transient failure (see
+	// ErrTransient) is retried before it's treated as exhausted and
+	// dead-lettered instead of redelivered again. Zero uses
+	// defaultMaxDeliveries.
+	MaxDeliveries int
+
+	// DeadLetterSuffix is appended to a subject to build the subject a
+	// dead-lettered message for it is published to, e.g.
+	// "endpoint.events" -> "endpoint.events.dlq". Empty uses
+	// defaultDeadLetterSuffix.
+	DeadLetterSuffix string
+}
+
+// NewConsumer creates a new consumer backed by the broker selected in
+// config.BrokerType. A nil Logger falls back to a discarding logger so
+// existing callers are not forced to supply one.
+func NewConsumer(
+	config *Config,
+	correlator *engine.Correlator,
+	chWriter detectionWriter,
+) (*Consumer, error) {
+	logger := config.Logger
+	if logger == nil {
+		logger = logging.Noop()
+	}
+
+	broker, err := newBroker(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = 10
+	}
+
+	maxDeliveries := config.MaxDeliveries
+	if maxDeliveries <= 0 {
+		maxDeliveries = defaultMaxDeliveries
+	}
+
+	deadLetterSuffix := config.DeadLetterSuffix
+	if deadLetterSuffix == "" {
+		deadLetterSuffix = defaultDeadLetterSuffix
+	}
+
+	c := &Consumer{
+		broker:           broker,
+		correlator:       correlator,
+		chWriter:         chWriter,
+		notifier:         config.Notifier,
+		subjects:         config.Subjects,
+		maxDeliveries:    maxDeliveries,
+		deadLetterSuffix: deadLetterSuffix,
+		logger:           logger,
+	}
+	c.batchSize.Store(int32(batchSize))
+	return c, nil
+}
+
+// SetBatchSize updates the broker fetch batch size, e.g. after a config hot
+// reload. Takes effect on the next fetch cycle.
+func (c *Consumer) SetBatchSize(batchSize int) {
+	if batchSize <= 0 {
+		return
+	}
+	c.batchSize.Store(int32(batchSize))
+}
+
+// Start starts consuming messages
+func (c *Consumer) Start(ctx context.Context) error {
+	c.logger.Info("starting consumer")
+
+	// Subscribe to all subjects
+	for _, subject := range c.subjects {
+		subject := subject
+		err := c.broker.Subscribe(ctx, subject, func() int { return int(c.batchSize.Load()) }, func(msg BrokerMessage) {
+			c.handleMessage(ctx, msg, subject)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+		}
+		c.logger.Info("subscribed to subject", "subject", subject)
+	}
+
+	// Wait for context cancellation
+	<-ctx.Done()
+	c.logger.Info("stopping consumer")
+
+	return nil
+}
+
+// processMessage processes a single message
+func (c *Consumer) processMessage(ctx context.Context, msg BrokerMessage, subject string) error {
+	// Continue the scanner's trace (W3C traceparent/baggage, see
+	// backend/scanner's pkg/publisher.headers) instead of starting a new
+	// one, so pkg/interceptor.Tracing's ProcessEvent span nests under the
+	// scan cycle that produced this event.
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(msg.Headers()))
+
+	// Decode detection event using whichever codec the publisher used
+	// (event-codec header), defaulting to JSON for messages without one.
+	// An unknown codec or malformed payload will never decode no matter how
+	// many times it's redelivered, so both are permanent failures.
+	eventCodec, err := codec.Get(msg.Headers()["event-codec"])
+	if err != nil {
+		return fmt.Errorf("%w: failed to select codec: %w", ErrPermanent, err)
+	}
+
+	var event engine.DetectionEvent
+	if err := eventCodec.Decode(msg.Data(), &event); err != nil {
+		return fmt.Errorf("%w: failed to decode event: %w", ErrPermanent, err)
+	}
+
+	if c.notifier != nil {
+		c.notifier.Dispatch(ctx, &event)
+	}
+
+	// Process through correlator. A failure here may be a transient
+	// registry-lookup timeout, so it's worth retrying rather than
+	// dead-lettering outright.
+	detection, err := c.correlator.ProcessEvent(ctx, &event)
+	if errors.Is(err, engine.ErrEvidenceAlreadyCommitted) {
+		c.logger.Info("skipping replayed evidence already committed", "event_id", event.EventID, "host_id", event.HostID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: correlation failed: %w", ErrTransient, err)
+	}
+
+	// Write to ClickHouse. An outage here is the canonical transient
+	// failure: retry rather than dead-letter.
+	chDetection := c.convertToClickHouseDetection(detection)
+	if err := c.chWriter.WriteDetection(ctx, chDetection); err != nil {
+		return fmt.Errorf("%w: failed to write to ClickHouse: %w", ErrTransient, err)
+	}
+
+	// Only mark evidence committed once it's durably written, so a failed
+	// write above can still be retried without looking like a replay.
+	if err := c.correlator.MarkCommitted(detection); err != nil {
+		c.logger.Warn("failed to mark evidence committed in pool", "composite_id", detection.CompositeID, "error", err)
+	}
+
+	logging.WithDetection(c.logger, detection.CompositeID, detection.HostIDHash, detection.Classification, detection.Score).
+		Info("processed detection")
+
+	return nil
+}
+
+// handleMessage runs processMessage and then Acks, retries, or dead-letters
+// msg depending on how it failed, so a permanent failure (malformed input)
+// or an exhausted transient one (a long downstream outage) can no longer
+// wedge the subject with infinite redelivery. Exhaustion is judged from
+// msg.NumDelivered, which AMQP only approximates (see
+// amqpBrokerMessage.NumDelivered).
+func (c *Consumer) handleMessage(ctx context.Context, msg BrokerMessage, subject string) {
+	err := c.processMessage(ctx, msg, subject)
+	if err == nil {
+		msg.Ack()
+		return
+	}
+
+	attempt := msg.NumDelivered()
+	metrics.ErrorsTotal.WithLabelValues("process_message").Inc()
+
+	switch {
+	case errors.Is(err, ErrPermanent):
+		c.logger.Error("permanent failure processing message, dead-lettering", "subject", subject, "attempt", attempt, "error", err)
+		c.deadLetter(ctx, msg, subject, "permanent", err)
+	case attempt >= c.maxDeliveries:
+		c.logger.Error("transient failure exhausted retries, dead-lettering", "subject", subject, "attempt", attempt, "error", err)
+		c.deadLetter(ctx, msg, subject, "retries_exhausted", err)
+	default:
+		delay := retryBackoff(attempt)
+		c.logger.Warn("transient failure processing message, retrying", "subject", subject, "attempt", attempt, "delay", delay, "error", err)
+		metrics.MessagesRetriedTotal.WithLabelValues(strconv.Itoa(attempt)).Inc()
+		if nakErr := msg.NakWithDelay(delay); nakErr != nil {
+			c.logger.Error("failed to nak message", "subject", subject, "error", nakErr)
+		}
+	}
+}
+
+// retryBackoff returns the NakWithDelay duration for the given delivery
+// attempt (1-indexed), doubling from baseRetryDelay and capping at
+// maxRetryDelay so a long outage doesn't push redelivery out indefinitely.
+func retryBackoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	shift := attempt - 1
+	if shift > 20 { // guard against overflow before the cap below applies
+		return maxRetryDelay
+	}
+	delay := baseRetryDelay << uint(shift)
+	if delay <= 0 || delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// deadLetter publishes msg to subject's dead-letter subject (subject +
+// c.deadLetterSuffix) with headers recording its original subject, delivery
+// count, and terminal error, then Acks the original so it's never
+// redelivered. If the dead-letter publish itself fails, msg is Nak'd
+// instead so it isn't silently dropped.
+func (c *Consumer) deadLetter(ctx context.Context, msg BrokerMessage, subject, reason string, cause error) {
+	metrics.MessagesDLQTotal.WithLabelValues(reason).Inc()
+
+	headers := make(map[string]string, len(msg.Headers())+3)
+	for k, v := range msg.Headers() {
+		headers[k] = v
+	}
+	headers["dlq-original-subject"] = subject
+	headers["dlq-delivery-count"] = strconv.Itoa(msg.NumDelivered())
+	headers["dlq-error"] = cause.Error()
+
+	dlqSubject := subject + c.deadLetterSuffix
+	if err := c.broker.Publish(ctx, dlqSubject, msg.Data(), headers); err != nil {
+		c.logger.Error("failed to publish to dead-letter subject, message will be retried", "subject", subject, "dlq_subject", dlqSubject, "error", err)
+		msg.Nak()
+		return
+	}
+
+	if err := msg.Ack(); err != nil {
+		c.logger.Error("failed to ack dead-lettered message", "subject", subject, "error", err)
+	}
+}
+
+// convertToClickHouseDetection converts engine detection to ClickHouse format
+func (c *Consumer) convertToClickHouseDetection(detection *engine.AggregatedDetection) *clickhouse.Detection {
+	chDetection := &clickhouse.Detection{
+		DetectionID:    "", // Will be generated
+		Timestamp:      detection.Timestamp,
+		HostIDHash:     detection.HostIDHash,
+		CompositeID:    detection.CompositeID,
+		Score:          detection.Score,
+		Classification: detection.Classification,
+		JudgeAvailable: detection.JudgeAvailable,
+		Metadata:       detection.Metadata,
+	}
+
+	// Convert evidence
+	for _, ev := range detection.Evidence {
+		snippet, _ := json.Marshal(ev.Details)
+		redacted, _ := ev.Details["redacted"].(bool)
+		chDetection.Evidence = append(chDetection.Evidence, clickhouse.Evidence{
+			Type:              ev.Type,
+			Source:            ev.Source,
+			ScoreContribution: ev.ScoreContribution,
+			Snippet:           string(snippet),
+			Redacted:          redacted,
+		})
+	}
+
+	return chDetection
+}
+
+// Close closes the underlying broker connection
+func (c *Consumer) Close() error {
+	return c.broker.Close()
+}
+
+// Connected reports whether the broker connection is currently up, for the
+// correlator's /readyz readiness closure (see internal/httpserver).
+func (c *Consumer) Connected() bool {
+	return c.broker.Connected()
+}
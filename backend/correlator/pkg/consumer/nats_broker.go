@@ -0,0 +1,206 @@
+package consumer
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+)
+
+// NATSBroker consumes messages from NATS JetStream pull subscriptions.
+type NATSBroker struct {
+	nc     *nats.Conn
+	js     nats.JetStreamContext
+	logger logging.Logger
+}
+
+// natsBrokerMessage adapts *nats.Msg to the BrokerMessage interface.
+type natsBrokerMessage struct {
+	msg *nats.Msg
+}
+
+func (m natsBrokerMessage) Data() []byte { return m.msg.Data }
+func (m natsBrokerMessage) Ack() error   { return m.msg.Ack() }
+func (m natsBrokerMessage) Nak() error   { return m.msg.Nak() }
+
+func (m natsBrokerMessage) NakWithDelay(delay time.Duration) error {
+	return m.msg.NakWithDelay(delay)
+}
+
+// NumDelivered reports JetStream's delivery count for this message. A
+// metadata lookup failure (e.g. a non-JetStream message) is treated as a
+// first delivery rather than propagated, since callers only use this for
+// retry accounting.
+func (m natsBrokerMessage) NumDelivered() int {
+	meta, err := m.msg.Metadata()
+	if err != nil {
+		return 1
+	}
+	return int(meta.NumDelivered)
+}
+
+func (m natsBrokerMessage) Headers() map[string]string {
+	headers := make(map[string]string, len(m.msg.Header))
+	for k := range m.msg.Header {
+		headers[k] = m.msg.Header.Get(k)
+	}
+	return headers
+}
+
+// newNATSBroker connects to NATS JetStream using the TLS/auth settings in
+// config, applying the same mutually-exclusive NKey/creds/token dispatch as
+// the scanner's publisher side.
+func newNATSBroker(config *Config, logger logging.Logger) (*NATSBroker, error) {
+	opts := []nats.Option{
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(2 * time.Second),
+		nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
+			if err != nil {
+				logger.Warn("NATS disconnected", "error", err)
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			logger.Info("NATS reconnected", "url", nc.ConnectedUrl())
+		}),
+	}
+
+	if config.TLSEnabled {
+		tlsConfig, err := loadTLSConfig(config.TLSCertFile, config.TLSKeyFile, config.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("mTLS enabled but failed to load certificate bundle: %w", err)
+		}
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+
+	switch {
+	case config.CredsFile != "":
+		opts = append(opts, nats.UserCredentials(config.CredsFile))
+	case config.NKeySeedFile != "":
+		nkeyOpt, err := nats.NkeyOptionFromSeed(config.NKeySeedFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load NKey seed file: %w", err)
+		}
+		opts = append(opts, nkeyOpt)
+	case config.Token != "":
+		opts = append(opts, nats.Token(config.Token))
+	}
+
+	nc, err := nats.Connect(config.NATSUrl, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	return &NATSBroker{nc: nc, js: js, logger: logger}, nil
+}
+
+// Subscribe creates a pull subscription for subject and polls it on a fixed
+// tick, delivering each fetched message to handler until ctx is cancelled.
+func (b *NATSBroker) Subscribe(ctx context.Context, subject string, batchSize func() int, handler func(BrokerMessage)) error {
+	sub, err := b.js.PullSubscribe(
+		subject,
+		"correlator",
+		nats.ManualAck(),
+		nats.AckWait(30*time.Second),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pull subscription: %w", err)
+	}
+
+	go b.processMessages(ctx, sub, subject, batchSize, handler)
+
+	return nil
+}
+
+func (b *NATSBroker) processMessages(ctx context.Context, sub *nats.Subscription, subject string, batchSize func() int, handler func(BrokerMessage)) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			msgs, err := sub.Fetch(batchSize(), nats.MaxWait(100*time.Millisecond))
+			if err != nil {
+				if err == nats.ErrTimeout {
+					continue
+				}
+				b.logger.Error("error fetching messages", "subject", subject, "error", err)
+				continue
+			}
+
+			for _, msg := range msgs {
+				handler(natsBrokerMessage{msg: msg})
+			}
+		}
+	}
+}
+
+// Connected reports whether the NATS connection is currently up.
+func (b *NATSBroker) Connected() bool {
+	return b.nc.IsConnected()
+}
+
+// ConnectedURL returns the NATS server URL currently connected to, or the
+// empty string if not connected.
+func (b *NATSBroker) ConnectedURL() string {
+	if !b.nc.IsConnected() {
+		return ""
+	}
+	return b.nc.ConnectedUrl()
+}
+
+// Publish publishes data to subject through the same JetStream context
+// Subscribe pulls from, e.g. to forward a message to its dead-letter
+// subject (see Consumer.deadLetter).
+func (b *NATSBroker) Publish(ctx context.Context, subject string, data []byte, headers map[string]string) error {
+	msg := &nats.Msg{Subject: subject, Data: data, Header: nats.Header{}}
+	for k, v := range headers {
+		msg.Header.Set(k, v)
+	}
+	_, err := b.js.PublishMsg(msg, nats.Context(ctx))
+	return err
+}
+
+// Close closes the NATS connection.
+func (b *NATSBroker) Close() error {
+	b.nc.Close()
+	return nil
+}
+
+// loadTLSConfig builds a client TLS config for mTLS-secured NATS connections.
+// All three files must load successfully; a partial or missing bundle is an
+// error rather than a silent fall-back to an insecure connection.
+func loadTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	caCert, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("failed to parse CA certificate")
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      caPool,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
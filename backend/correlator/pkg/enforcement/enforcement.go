@@ -0,0 +1,273 @@
+// Package enforcement implements a pluggable, rule-driven response layer
+// for classified detections: after Correlator scores and classifies a
+// detection, Engine dispatches it through whichever actions the matching
+// rules name (log, alert, dryrun, or any action registered via
+// RegisterAction), recording outcomes back into
+// AggregatedDetection.Metadata["actions"] so downstream consumers can
+// audit what fired.
+// Reference: FR-002 (Multi-layer detection), FR-003 (Weighted scoring)
+package enforcement
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+)
+
+// ActionFunc performs one enforcement action against detection. It should
+// respect ctx's deadline; Engine applies a per-action timeout around every
+// call regardless.
+type ActionFunc func(ctx context.Context, detection *engine.AggregatedDetection) error
+
+// Built-in action names, always available without RegisterAction.
+// "quarantine", "block", and "webhook" are not built in — they depend on
+// infrastructure this repo doesn't own (a firewall/EDR API, a webhook
+// sink) and are expected to be supplied via RegisterAction by the
+// deployment.
+const (
+	ActionLog    = "log"
+	ActionDryRun = "dryrun"
+	ActionAlert  = "alert"
+)
+
+// ScopeSelector narrows a Rule to a subset of detections. Each non-empty
+// field must match for the rule to apply; within a field, any one value
+// matching is enough (OR). An entirely empty selector matches everything.
+type ScopeSelector struct {
+	HostIDHashes   []string          `yaml:"host_id_hashes" json:"host_id_hashes"`
+	Sources        []string          `yaml:"sources" json:"sources"`
+	DetectionTypes []string          `yaml:"detection_types" json:"detection_types"`
+	MetadataLabels map[string]string `yaml:"metadata_labels" json:"metadata_labels"`
+}
+
+// Rule maps a classification + scope to the actions that should fire.
+type Rule struct {
+	Name            string        `yaml:"name" json:"name"`
+	Classifications []string      `yaml:"classifications" json:"classifications"` // e.g. ["unauthorized"]
+	Scope           ScopeSelector `yaml:"scope" json:"scope"`
+	Actions         []string      `yaml:"actions" json:"actions"` // e.g. ["block", "webhook"]
+}
+
+func (r Rule) matches(detection *engine.AggregatedDetection) bool {
+	if len(r.Classifications) > 0 && !containsString(r.Classifications, detection.Classification) {
+		return false
+	}
+	return r.Scope.matches(detection)
+}
+
+func (s ScopeSelector) matches(detection *engine.AggregatedDetection) bool {
+	if len(s.HostIDHashes) > 0 && !containsString(s.HostIDHashes, detection.HostIDHash) {
+		return false
+	}
+	if len(s.DetectionTypes) > 0 && !anyEvidenceField(s.DetectionTypes, detection, func(e engine.EvidenceRecord) string { return e.Type }) {
+		return false
+	}
+	if len(s.Sources) > 0 && !anyEvidenceField(s.Sources, detection, func(e engine.EvidenceRecord) string { return e.Source }) {
+		return false
+	}
+	if len(s.MetadataLabels) > 0 && !matchesMetadataLabels(s.MetadataLabels, detection.Metadata) {
+		return false
+	}
+	return true
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+func anyEvidenceField(values []string, detection *engine.AggregatedDetection, field func(engine.EvidenceRecord) string) bool {
+	for _, evidence := range detection.Evidence {
+		if containsString(values, field(evidence)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesMetadataLabels(labels map[string]string, metadata map[string]interface{}) bool {
+	for key, want := range labels {
+		got, ok := metadata[key]
+		if !ok {
+			return false
+		}
+		if gotStr, ok := got.(string); !ok || gotStr != want {
+			return false
+		}
+	}
+	return true
+}
+
+// actionOutcome records what happened when one action ran. Written into
+// AggregatedDetection.Metadata["actions"] as a []actionOutcome for audit.
+type actionOutcome struct {
+	Action string    `json:"action"`
+	Rule   string    `json:"rule"`
+	Status string    `json:"status"` // "success", "error", "timeout", "unregistered"
+	Error  string    `json:"error,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// Config holds enforcement engine configuration.
+type Config struct {
+	Rules []Rule
+	// ActionTimeout bounds how long a single action may run. Default: 5s.
+	ActionTimeout time.Duration
+	Logger        logging.Logger
+}
+
+// Engine matches classified detections against a hot-reloadable rule set
+// and dispatches the named actions. It implements engine.Enforcer.
+type Engine struct {
+	actions       sync.Map // name string -> ActionFunc
+	rules         atomic.Pointer[[]Rule]
+	actionTimeout time.Duration
+	logger        logging.Logger
+}
+
+// NewEngine creates an enforcement Engine with log/dryrun/alert
+// pre-registered. A nil Logger falls back to a discarding logger so
+// existing callers are not forced to supply one.
+func NewEngine(config *Config) *Engine {
+	logger := config.Logger
+	if logger == nil {
+		logger = logging.Noop()
+	}
+	timeout := config.ActionTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	e := &Engine{actionTimeout: timeout, logger: logger}
+	rules := config.Rules
+	e.rules.Store(&rules)
+
+	e.RegisterAction(ActionLog, e.logAction)
+	e.RegisterAction(ActionDryRun, e.dryRunAction)
+	e.RegisterAction(ActionAlert, e.alertAction)
+
+	return e
+}
+
+// RegisterAction makes fn available to rules under name, replacing any
+// action already registered under that name (including built-ins).
+func (e *Engine) RegisterAction(name string, fn ActionFunc) {
+	e.actions.Store(name, fn)
+}
+
+// SetRules hot-swaps the rule set, e.g. after a config reload (see
+// WatchRulesFile). Detections already dispatched are unaffected.
+func (e *Engine) SetRules(rules []Rule) {
+	e.rules.Store(&rules)
+}
+
+func (e *Engine) logAction(_ context.Context, detection *engine.AggregatedDetection) error {
+	e.logger.Info("enforcement: log action", "composite_id", detection.CompositeID,
+		"classification", detection.Classification, "score", detection.Score)
+	return nil
+}
+
+func (e *Engine) dryRunAction(_ context.Context, detection *engine.AggregatedDetection) error {
+	e.logger.Info("enforcement: dryrun action (no-op)", "composite_id", detection.CompositeID,
+		"classification", detection.Classification, "score", detection.Score)
+	return nil
+}
+
+func (e *Engine) alertAction(_ context.Context, detection *engine.AggregatedDetection) error {
+	e.logger.Warn("enforcement: alert", "composite_id", detection.CompositeID,
+		"classification", detection.Classification, "score", detection.Score,
+		"host_id_hash", detection.HostIDHash)
+	return nil
+}
+
+// Dispatch runs every action named by rules matching detection, each
+// concurrently with its own timeout, and records outcomes into
+// detection.Metadata["actions"]. It blocks until all actions finish or
+// time out, so the outcomes are present before the caller persists
+// detection. Implements engine.Enforcer.
+func (e *Engine) Dispatch(ctx context.Context, detection *engine.AggregatedDetection) {
+	rules := *e.rules.Load()
+
+	type scheduled struct{ ruleName, action string }
+	var todo []scheduled
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if !rule.matches(detection) {
+			continue
+		}
+		for _, action := range rule.Actions {
+			key := rule.Name + "/" + action
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			todo = append(todo, scheduled{ruleName: rule.Name, action: action})
+		}
+	}
+	if len(todo) == 0 {
+		return
+	}
+
+	var mu sync.Mutex
+	outcomes := make([]actionOutcome, 0, len(todo))
+	var wg sync.WaitGroup
+	for _, s := range todo {
+		s := s
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outcome := e.runAction(ctx, s.ruleName, s.action, detection)
+			mu.Lock()
+			outcomes = append(outcomes, outcome)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if detection.Metadata == nil {
+		detection.Metadata = make(map[string]interface{})
+	}
+	detection.Metadata["actions"] = outcomes
+}
+
+func (e *Engine) runAction(ctx context.Context, ruleName, action string, detection *engine.AggregatedDetection) actionOutcome {
+	outcome := actionOutcome{Action: action, Rule: ruleName, At: time.Now()}
+
+	value, ok := e.actions.Load(action)
+	if !ok {
+		outcome.Status = "unregistered"
+		e.logger.Warn("enforcement action not registered", "action", action, "rule", ruleName)
+		return outcome
+	}
+	fn := value.(ActionFunc)
+
+	actionCtx, cancel := context.WithTimeout(ctx, e.actionTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- fn(actionCtx, detection) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			outcome.Status = "error"
+			outcome.Error = err.Error()
+			e.logger.Error("enforcement action failed", "action", action, "rule", ruleName, "error", err)
+		} else {
+			outcome.Status = "success"
+		}
+	case <-actionCtx.Done():
+		outcome.Status = "timeout"
+		outcome.Error = actionCtx.Err().Error()
+		e.logger.Warn("enforcement action timed out", "action", action, "rule", ruleName)
+	}
+	return outcome
+}
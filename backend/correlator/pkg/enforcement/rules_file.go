@@ -0,0 +1,83 @@
+package enforcement
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// RulesConfig is the on-disk YAML/JSON shape of a rule set file, e.g.:
+//
+//	rules:
+//	  - name: block-unauthorized-prod
+//	    classifications: ["unauthorized"]
+//	    scope:
+//	      metadata_labels:
+//	        env: prod
+//	    actions: ["block", "webhook"]
+type RulesConfig struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// LoadRulesFile reads and parses a rule set from path. YAML and JSON share
+// syntax closely enough that yaml.Unmarshal handles both.
+func LoadRulesFile(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var cfg RulesConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file: %w", err)
+	}
+	return cfg.Rules, nil
+}
+
+// WatchRulesFile reloads the rule set from path whenever it changes on
+// disk, without requiring a correlator restart. A parse failure on reload
+// is logged and the previous rule set stays active. Intended to run in
+// its own goroutine; blocks until ctx is cancelled.
+func (e *Engine) WatchRulesFile(ctx context.Context, path string) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		e.logger.Warn("enforcement rules file watcher unavailable, reload disabled", "error", err)
+		return
+	}
+	defer fsWatcher.Close()
+
+	if err := fsWatcher.Add(filepath.Dir(path)); err != nil {
+		e.logger.Warn("failed to watch enforcement rules directory", "path", path, "error", err)
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return
+			}
+			if event.Name != path || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			rules, err := LoadRulesFile(path)
+			if err != nil {
+				e.logger.Error("enforcement rules reload rejected, keeping previous rules", "path", path, "error", err)
+				continue
+			}
+			e.SetRules(rules)
+			e.logger.Info("enforcement rules reloaded", "path", path, "rule_count", len(rules))
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			e.logger.Warn("enforcement rules file watcher error", "error", err)
+		}
+	}
+}
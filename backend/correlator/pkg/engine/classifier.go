@@ -0,0 +1,171 @@
+package engine
+
+import (
+	"math"
+	"sync"
+)
+
+// Classifier turns a scored detection into a classification label
+// ("authorized", "suspect", "unauthorized"). Correlator.SetClassifier lets
+// operators swap models at runtime (see ThresholdClassifier and
+// ProbabilisticClassifier) and A/B test classifications against the same
+// evidence/scoring pipeline without redeploying.
+type Classifier interface {
+	Classify(detection *AggregatedDetection) string
+}
+
+// RegistryObserver is implemented by classifiers that recalibrate from
+// registry-confirmed outcomes, such as ProbabilisticClassifier. Correlator
+// calls Observe asynchronously after each registry check so calibration
+// never blocks the scoring path.
+type RegistryObserver interface {
+	Observe(source, evidenceType string, registryAuthorized bool)
+}
+
+// ThresholdClassifier is the original fixed-threshold classification
+// model: it compares detection.Score against ClassificationThresholds
+// obtained from thresholds, which is typically Correlator.thresholds so
+// classification stays in sync with UpdateTunables.
+type ThresholdClassifier struct {
+	thresholds func() ClassificationThresholds
+}
+
+// NewThresholdClassifier returns a ThresholdClassifier that reads
+// thresholds on every Classify call.
+func NewThresholdClassifier(thresholds func() ClassificationThresholds) *ThresholdClassifier {
+	return &ThresholdClassifier{thresholds: thresholds}
+}
+
+// Classify implements Classifier.
+func (t *ThresholdClassifier) Classify(detection *AggregatedDetection) string {
+	thresholds := t.thresholds()
+	if detection.Score <= thresholds.Authorized {
+		return "authorized"
+	}
+	if detection.Score <= thresholds.Suspect {
+		return "suspect"
+	}
+	return "unauthorized"
+}
+
+// betaCounts holds the Beta(alpha, beta) pseudo-counts ProbabilisticClassifier
+// keeps per evidence (Source, Type) pair: alpha counts times this evidence
+// was seen alongside a registry-authorized outcome, beta counts times it
+// was seen alongside a confirmed-unauthorized one. Starts at Beta(1, 1)
+// (uniform) and is nudged by Observe as registry outcomes arrive.
+type betaCounts struct {
+	alpha float64
+	beta  float64
+}
+
+// ProbabilisticClassifier classifies detections by combining per-source
+// likelihood ratios, learned from registry-labeled outcomes, with a prior
+// derived from the tenant's base rate of unauthorized MCPs — rather than
+// comparing the aggregate score against fixed thresholds. It is an
+// alternative to ThresholdClassifier, not a replacement; operators can run
+// both side by side (see Correlator.SetClassifier) to compare
+// classifications on the same evidence stream.
+//
+// For each evidence (Source, Type) pair it keeps a Beta(alpha, beta)
+// distribution: alpha/( alpha+beta) approximates P(this evidence | authorized),
+// beta/(alpha+beta) approximates P(this evidence | unauthorized). Classify
+// multiplies the resulting per-source likelihood ratios in log-space (to
+// avoid underflow with many evidence items), combines them with the
+// configured prior, and converts the result back to
+// P(unauthorized | evidence) before applying AuthorizedCutoff and
+// SuspectCutoff.
+type ProbabilisticClassifier struct {
+	mu     sync.Mutex
+	counts map[string]*betaCounts
+
+	// PriorUnauthorizedRate is the tenant's base rate of unauthorized
+	// MCPs, used as P(unauthorized) before any evidence is applied.
+	PriorUnauthorizedRate float64
+
+	// AuthorizedCutoff and SuspectCutoff are P(unauthorized | evidence)
+	// cutoffs: below AuthorizedCutoff classifies as "authorized", below
+	// SuspectCutoff as "suspect", otherwise "unauthorized".
+	AuthorizedCutoff float64
+	SuspectCutoff    float64
+}
+
+// NewProbabilisticClassifier returns a ProbabilisticClassifier with no
+// prior observations; every (source, type) pair starts at the uniform
+// Beta(1, 1) prior until Observe is called.
+func NewProbabilisticClassifier(priorUnauthorizedRate, authorizedCutoff, suspectCutoff float64) *ProbabilisticClassifier {
+	return &ProbabilisticClassifier{
+		counts:                make(map[string]*betaCounts),
+		PriorUnauthorizedRate: priorUnauthorizedRate,
+		AuthorizedCutoff:      authorizedCutoff,
+		SuspectCutoff:         suspectCutoff,
+	}
+}
+
+func betaKey(source, evidenceType string) string {
+	return source + ":" + evidenceType
+}
+
+// Observe updates the (source, evidenceType) pair's Beta(alpha, beta)
+// counts given a registry-confirmed outcome. Correlator calls this
+// asynchronously from recalculateDetection so the model self-calibrates
+// over time without the scoring path waiting on it.
+func (p *ProbabilisticClassifier) Observe(source, evidenceType string, registryAuthorized bool) {
+	key := betaKey(source, evidenceType)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	counts, ok := p.counts[key]
+	if !ok {
+		counts = &betaCounts{alpha: 1, beta: 1}
+		p.counts[key] = counts
+	}
+	if registryAuthorized {
+		counts.alpha++
+	} else {
+		counts.beta++
+	}
+}
+
+// likelihoodRatio returns P(evidence | unauthorized) / P(evidence | authorized)
+// for the given (source, evidenceType) pair, approximated from its Beta
+// mean counts. Pairs with no observations yet return 1 (neutral — they
+// don't move the posterior either way).
+func (p *ProbabilisticClassifier) likelihoodRatio(source, evidenceType string) float64 {
+	key := betaKey(source, evidenceType)
+
+	p.mu.Lock()
+	counts, ok := p.counts[key]
+	p.mu.Unlock()
+	if !ok {
+		return 1
+	}
+	return counts.beta / counts.alpha
+}
+
+// Classify implements Classifier.
+func (p *ProbabilisticClassifier) Classify(detection *AggregatedDetection) string {
+	prior := p.PriorUnauthorizedRate
+	switch {
+	case prior <= 0:
+		prior = 0.01 // floor: avoid log(0) for a tenant with no history yet
+	case prior >= 1:
+		prior = 0.99
+	}
+
+	logOdds := math.Log(prior / (1 - prior))
+	for _, evidence := range detection.Evidence {
+		logOdds += math.Log(p.likelihoodRatio(evidence.Source, evidence.Type))
+	}
+
+	posteriorUnauthorized := 1 / (1 + math.Exp(-logOdds))
+
+	switch {
+	case posteriorUnauthorized < p.AuthorizedCutoff:
+		return "authorized"
+	case posteriorUnauthorized < p.SuspectCutoff:
+		return "suspect"
+	default:
+		return "unauthorized"
+	}
+}
@@ -4,41 +4,60 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/evidencepool"
 	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/identifier"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
 	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/registry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
 )
 
+// TracerName is the OpenTelemetry instrumentation name engine spans are
+// reported under. pkg/interceptor's Tracing interceptor starts the parent
+// ProcessEvent span under the same name so child spans below nest under it.
+const TracerName = "github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+
+var tracer = otel.Tracer(TracerName)
+
+// ErrEvidenceAlreadyCommitted is returned by ProcessEvent when an agent
+// replays evidence that was already committed to a prior AggregatedDetection,
+// so the caller can skip re-processing instead of re-inflating the score.
+var ErrEvidenceAlreadyCommitted = errors.New("engine: evidence already committed")
+
 // DetectionEvent represents an incoming detection event from any source
 type DetectionEvent struct {
-	EventID      string                 `json:"event_id"`
-	Timestamp    time.Time              `json:"timestamp"`
-	HostID       string                 `json:"host_id"`
-	DetectionType string                `json:"detection_type"` // "file", "process", "network", "gateway"
-	Score        int                    `json:"score"`
-	Evidence     map[string]interface{} `json:"evidence"`
+	EventID       string                 `json:"event_id"`
+	Timestamp     time.Time              `json:"timestamp"`
+	HostID        string                 `json:"host_id"`
+	DetectionType string                 `json:"detection_type"` // "file", "process", "network", "gateway"
+	Score         int                    `json:"score"`
+	Evidence      map[string]interface{} `json:"evidence"`
 }
 
 // AggregatedDetection represents a correlated detection with evidence from multiple sources
 type AggregatedDetection struct {
-	CompositeID      string                   `json:"composite_id"`
-	HostIDHash       string                   `json:"host_id_hash"` // SHA256(host_id)
-	Timestamp        time.Time                `json:"timestamp"`    // First detection time
-	LastUpdated      time.Time                `json:"last_updated"`
-	Score            int                      `json:"score"`
-	Classification   string                   `json:"classification"` // "authorized", "suspect", "unauthorized"
-	Evidence         []EvidenceRecord         `json:"evidence"`
-	RegistryMatched  bool                     `json:"registry_matched"`
-	RegistryPenalty  int                      `json:"registry_penalty"`
-	JudgeAvailable   bool                     `json:"judge_available"`
-	Metadata         map[string]interface{}   `json:"metadata"`
+	CompositeID     string                 `json:"composite_id"`
+	HostIDHash      string                 `json:"host_id_hash"` // identifier.HashHostID(host_id)
+	Timestamp       time.Time              `json:"timestamp"`    // First detection time
+	LastUpdated     time.Time              `json:"last_updated"`
+	Score           int                    `json:"score"`
+	Classification  string                 `json:"classification"` // "authorized", "suspect", "unauthorized"
+	Evidence        []EvidenceRecord       `json:"evidence"`
+	RegistryMatched bool                   `json:"registry_matched"`
+	RegistryPenalty int                    `json:"registry_penalty"`
+	JudgeAvailable  bool                   `json:"judge_available"`
+	Metadata        map[string]interface{} `json:"metadata"`
 }
 
 // EvidenceRecord represents a single piece of evidence
 type EvidenceRecord struct {
+	Hash              string                 `json:"hash"`   // deterministic content hash, see evidencepool.HashEvidence
 	Type              string                 `json:"type"`   // "endpoint", "network", "gateway"
 	Source            string                 `json:"source"` // "scanner-v1.0.0", "zeek", "judge"
 	ScoreContribution int                    `json:"score_contribution"`
@@ -53,13 +72,26 @@ type CorrelationWindow struct {
 	windowSize time.Duration
 }
 
+// Enforcer dispatches a classified detection through scoped enforcement
+// actions (see pkg/enforcement.Engine). Optional; a nil Enforcer disables
+// enforcement entirely.
+type Enforcer interface {
+	Dispatch(ctx context.Context, detection *AggregatedDetection)
+}
+
 // Correlator is the main correlation engine
 type Correlator struct {
 	window          *CorrelationWindow
 	registryClient  *registry.Client
 	clickhouseURL   string
-	scoringWeights  ScoringWeights
-	classThresholds ClassificationThresholds
+	pool            evidencepool.Pool
+	scoringWeights  atomic.Pointer[ScoringWeights]
+	classThresholds atomic.Pointer[ClassificationThresholds]
+	classifier      atomic.Pointer[Classifier]
+	enforcer        atomic.Pointer[Enforcer]
+	interceptors    []Interceptor
+	metricsEmitter  MetricsEmitter
+	logger          logging.Logger
 }
 
 // ScoringWeights defines weight for each signal type (FR-003)
@@ -77,37 +109,141 @@ type ClassificationThresholds struct {
 	Unauthorized int // > suspect threshold = unauthorized (default: 9+)
 }
 
-// NewCorrelator creates a new correlation engine
+// NewCorrelator creates a new correlation engine. A nil logger falls back to
+// a discarding logger so existing callers are not forced to supply one. A
+// nil pool falls back to an in-memory evidencepool.Pool, which works for
+// tests and single-run use but does not survive a restart; pass a
+// *evidencepool.BoltPool (see pkg/evidencepool) to persist evidence
+// lifecycle state across restarts. A nil metricsEmitter falls back to
+// NoopMetricsEmitter.
 func NewCorrelator(
 	windowSize time.Duration,
 	registryClient *registry.Client,
 	clickhouseURL string,
+	pool evidencepool.Pool,
 	weights ScoringWeights,
 	thresholds ClassificationThresholds,
+	metricsEmitter MetricsEmitter,
+	logger logging.Logger,
 ) *Correlator {
-	return &Correlator{
+	if logger == nil {
+		logger = logging.Noop()
+	}
+	if pool == nil {
+		pool = evidencepool.NewMemoryPool()
+	}
+	if metricsEmitter == nil {
+		metricsEmitter = NoopMetricsEmitter{}
+	}
+	c := &Correlator{
 		window: &CorrelationWindow{
 			detections: make(map[string]*AggregatedDetection),
 			windowSize: windowSize,
 		},
-		registryClient:  registryClient,
-		clickhouseURL:   clickhouseURL,
-		scoringWeights:  weights,
-		classThresholds: thresholds,
+		registryClient: registryClient,
+		clickhouseURL:  clickhouseURL,
+		pool:           pool,
+		metricsEmitter: metricsEmitter,
+		logger:         logger,
+	}
+	c.scoringWeights.Store(&weights)
+	c.classThresholds.Store(&thresholds)
+	var classifier Classifier = NewThresholdClassifier(c.thresholds)
+	c.classifier.Store(&classifier)
+	return c
+}
+
+// UpdateTunables swaps in new scoring weights and classification thresholds,
+// e.g. after a config hot reload. In-flight detections in the correlation
+// window keep their already-recorded evidence; only future scoring uses the
+// new values.
+func (c *Correlator) UpdateTunables(weights ScoringWeights, thresholds ClassificationThresholds) {
+	c.scoringWeights.Store(&weights)
+	c.classThresholds.Store(&thresholds)
+}
+
+func (c *Correlator) weights() ScoringWeights {
+	return *c.scoringWeights.Load()
+}
+
+func (c *Correlator) thresholds() ClassificationThresholds {
+	return *c.classThresholds.Load()
+}
+
+// SetEnforcer installs (or replaces) the enforcement layer every
+// classified detection is dispatched through after scoring. Passing nil
+// disables enforcement.
+func (c *Correlator) SetEnforcer(enforcer Enforcer) {
+	c.enforcer.Store(&enforcer)
+}
+
+// SetClassifier installs (or replaces) the classification model
+// recalculateDetection uses to turn a scored detection into a
+// classification label. Passing nil restores the default
+// ThresholdClassifier. Safe to call while the correlator is processing
+// events; the new classifier takes effect on the next recalculation.
+func (c *Correlator) SetClassifier(classifier Classifier) {
+	if classifier == nil {
+		classifier = NewThresholdClassifier(c.thresholds)
+	}
+	c.classifier.Store(&classifier)
+}
+
+func (c *Correlator) classifierOrDefault() Classifier {
+	if ptr := c.classifier.Load(); ptr != nil && *ptr != nil {
+		return *ptr
 	}
+	return NewThresholdClassifier(c.thresholds)
 }
 
-// ProcessEvent processes an incoming detection event
+// ProcessEvent processes an incoming detection event by running it through
+// the registered interceptor chain (see Use), terminating in the
+// correlator's own scoring logic.
 func (c *Correlator) ProcessEvent(ctx context.Context, event *DetectionEvent) (*AggregatedDetection, error) {
+	return chain(c.interceptors, c.processEvent)(ctx, event)
+}
+
+// processEvent is the terminal Handler in the interceptor chain: it
+// generates the composite ID, records evidence, and (re)scores the
+// detection. It deliberately contains no cross-cutting concerns (panic
+// recovery, logging, rate limiting, tracing, metrics) — those belong in
+// interceptors, see pkg/interceptor.
+func (c *Correlator) processEvent(ctx context.Context, event *DetectionEvent) (*AggregatedDetection, error) {
+	c.metricsEmitter.IncEventsProcessed(event.DetectionType)
+
 	// Generate composite ID from event
+	_, idSpan := tracer.Start(ctx, "generateCompositeID")
 	compositeID, err := c.generateCompositeID(event)
+	idSpan.End()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate composite ID: %w", err)
 	}
 
+	// Carry composite_id as baggage on ctx so every span from here on
+	// (scoring, registry lookup, enforcement dispatch) can correlate back
+	// to this MCP instance without re-deriving it from event evidence.
+	if member, err := baggage.NewMember("composite_id", compositeID); err == nil {
+		if bag, err := baggage.New(member); err == nil {
+			ctx = baggage.ContextWithBaggage(ctx, bag)
+		}
+	}
+
 	// Hash host ID for privacy (FR-008)
 	hostIDHash := identifier.HashHostID(event.HostID)
 
+	evidenceType := c.mapDetectionTypeToEvidenceType(event.DetectionType)
+	source := c.extractSource(event)
+	evidenceHash := evidencepool.HashEvidence(compositeID, evidenceType, source, event.Timestamp, event.Evidence)
+
+	// Reject evidence an agent already delivered and had committed to a
+	// prior AggregatedDetection, so a replay can't re-inflate the score.
+	if committed, err := c.pool.IsCommitted(evidenceHash); err != nil {
+		c.logger.Warn("evidence pool commit check failed", "composite_id", compositeID, "error", err)
+	} else if committed {
+		c.logger.Info("duplicate evidence rejected, already committed", "composite_id", compositeID, "hash", evidenceHash)
+		return nil, ErrEvidenceAlreadyCommitted
+	}
+
 	// Check if detection already exists in window
 	c.window.mu.Lock()
 	detection, exists := c.window.detections[compositeID]
@@ -129,27 +265,133 @@ func (c *Correlator) ProcessEvent(ctx context.Context, event *DetectionEvent) (*
 
 	// Add evidence
 	evidence := EvidenceRecord{
-		Type:              c.mapDetectionTypeToEvidenceType(event.DetectionType),
-		Source:            c.extractSource(event),
+		Hash:              evidenceHash,
+		Type:              evidenceType,
+		Source:            source,
 		ScoreContribution: c.calculateScoreContribution(event),
 		Timestamp:         event.Timestamp,
 		Details:           event.Evidence,
 	}
 	detection.Evidence = append(detection.Evidence, evidence)
 	detection.LastUpdated = time.Now()
+	activeDetections := len(c.window.detections)
 
 	c.window.mu.Unlock()
 
+	c.metricsEmitter.ObserveScoreContribution(evidence.Source, evidence.ScoreContribution)
+	c.metricsEmitter.SetActiveDetections(activeDetections)
+
+	if _, err := c.pool.AddPending(evidencepool.Evidence{
+		Hash:              evidenceHash,
+		CompositeID:       compositeID,
+		HostIDHash:        hostIDHash,
+		Type:              evidence.Type,
+		Source:            evidence.Source,
+		ScoreContribution: evidence.ScoreContribution,
+		Timestamp:         evidence.Timestamp,
+		Details:           evidence.Details,
+	}); err != nil {
+		c.logger.Warn("failed to persist pending evidence", "composite_id", compositeID, "error", err)
+	}
+
 	// Recalculate score and classification
 	if err := c.recalculateDetection(ctx, detection); err != nil {
 		return nil, fmt.Errorf("failed to recalculate detection: %w", err)
 	}
 
+	c.metricsEmitter.IncClassification(detection.Classification)
+	c.metricsEmitter.ObserveScore(detection.Score)
+	c.metricsEmitter.SetJudgeAvailable(detection.JudgeAvailable)
+
+	if enforcerPtr := c.enforcer.Load(); enforcerPtr != nil && *enforcerPtr != nil {
+		(*enforcerPtr).Dispatch(ctx, detection)
+	}
+
 	return detection, nil
 }
 
+// MarkCommitted tells the evidence pool that every piece of evidence on
+// detection has now been durably written (e.g. to ClickHouse). Call this
+// only after that write actually succeeds, so a failed write can still be
+// retried without the evidence being mistaken for a replay.
+func (c *Correlator) MarkCommitted(detection *AggregatedDetection) error {
+	hashes := make([]string, 0, len(detection.Evidence))
+	for _, e := range detection.Evidence {
+		if e.Hash != "" {
+			hashes = append(hashes, e.Hash)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	return c.pool.MarkCommitted(detection.CompositeID, hashes, time.Now())
+}
+
+// Rehydrate reloads every still-pending piece of evidence from the
+// evidence pool into the correlation window, so a correlator restart
+// doesn't lose in-flight correlation state. Call once during startup,
+// before the consumer begins processing new events. Returns the number of
+// composite IDs rehydrated.
+func (c *Correlator) Rehydrate(ctx context.Context) (int, error) {
+	pending, err := c.pool.AllPending()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load pending evidence: %w", err)
+	}
+
+	byComposite := make(map[string][]evidencepool.Evidence)
+	for _, e := range pending {
+		byComposite[e.CompositeID] = append(byComposite[e.CompositeID], e)
+	}
+
+	c.window.mu.Lock()
+	for compositeID, evs := range byComposite {
+		detection := &AggregatedDetection{
+			CompositeID:    compositeID,
+			Evidence:       make([]EvidenceRecord, 0, len(evs)),
+			JudgeAvailable: true,
+			Metadata:       make(map[string]interface{}),
+		}
+		for i, e := range evs {
+			if i == 0 || e.Timestamp.Before(detection.Timestamp) {
+				detection.Timestamp = e.Timestamp
+			}
+			if e.Timestamp.After(detection.LastUpdated) {
+				detection.LastUpdated = e.Timestamp
+			}
+			if detection.HostIDHash == "" {
+				detection.HostIDHash = e.HostIDHash
+			}
+			detection.Evidence = append(detection.Evidence, EvidenceRecord{
+				Hash:              e.Hash,
+				Type:              e.Type,
+				Source:            e.Source,
+				ScoreContribution: e.ScoreContribution,
+				Timestamp:         e.Timestamp,
+				Details:           e.Details,
+			})
+		}
+		c.window.detections[compositeID] = detection
+	}
+	c.window.mu.Unlock()
+
+	for compositeID := range byComposite {
+		c.window.mu.RLock()
+		detection := c.window.detections[compositeID]
+		c.window.mu.RUnlock()
+
+		if err := c.recalculateDetection(ctx, detection); err != nil {
+			c.logger.Warn("failed to recalculate rehydrated detection", "composite_id", compositeID, "error", err)
+		}
+	}
+
+	return len(byComposite), nil
+}
+
 // recalculateDetection recalculates score and classification for a detection
 func (c *Correlator) recalculateDetection(ctx context.Context, detection *AggregatedDetection) error {
+	scoringCtx, scoringSpan := tracer.Start(ctx, "scoring")
+	defer scoringSpan.End()
+
 	// Check if Judge evidence is present - indicates Judge availability (FR-020a)
 	hasJudgeEvidence := false
 	for _, evidence := range detection.Evidence {
@@ -167,21 +409,28 @@ func (c *Correlator) recalculateDetection(ctx context.Context, detection *Aggreg
 	}
 
 	// Check registry for match
-	registryMatch, err := c.registryClient.CheckMatch(ctx, registry.MatchRequest{
+	registryCtx, registrySpan := tracer.Start(scoringCtx, "registry.CheckMatch")
+	registryMatch, err := c.registryClient.CheckMatch(registryCtx, registry.MatchRequest{
 		CompositeID:  detection.CompositeID,
 		HostIDHash:   detection.HostIDHash,
 		ManifestHash: c.extractManifestHash(detection),
 	})
+	registrySpan.End()
 	if err != nil {
 		// Log error but continue
-		fmt.Printf("Warning: Registry check failed: %v\n", err)
+		c.logger.Warn("registry check failed", "composite_id", detection.CompositeID, "error", err)
+		c.metricsEmitter.IncRegistryError()
+		registryMatch = &registry.MatchResponse{}
 	}
 
 	// Apply registry penalty if matched (FR-005)
 	if registryMatch.Matched {
 		detection.RegistryMatched = true
-		detection.RegistryPenalty = c.scoringWeights.Registry
-		totalScore += c.scoringWeights.Registry
+		detection.RegistryPenalty = c.weights().Registry
+		totalScore += c.weights().Registry
+		c.metricsEmitter.IncRegistryHit()
+	} else if err == nil {
+		c.metricsEmitter.IncRegistryMiss()
 	}
 
 	// Ensure score doesn't go negative
@@ -191,23 +440,27 @@ func (c *Correlator) recalculateDetection(ctx context.Context, detection *Aggreg
 
 	detection.Score = totalScore
 
-	// Classify based on thresholds
-	detection.Classification = c.classify(totalScore)
+	classifier := c.classifierOrDefault()
+	detection.Classification = classifier.Classify(detection)
+
+	// If the classifier self-calibrates from registry outcomes (e.g.
+	// ProbabilisticClassifier), feed it this detection's evidence
+	// asynchronously so scoring never waits on it. err == nil here means
+	// registryMatch.Matched is an authoritative label, not just a default.
+	if observer, ok := classifier.(RegistryObserver); ok && err == nil {
+		authorized := registryMatch.Matched
+		evidenceSnapshot := make([]EvidenceRecord, len(detection.Evidence))
+		copy(evidenceSnapshot, detection.Evidence)
+		go func() {
+			for _, evidence := range evidenceSnapshot {
+				observer.Observe(evidence.Source, evidence.Type, authorized)
+			}
+		}()
+	}
 
 	return nil
 }
 
-// classify determines classification based on score
-func (c *Correlator) classify(score int) string {
-	if score <= c.classThresholds.Authorized {
-		return "authorized"
-	}
-	if score <= c.classThresholds.Suspect {
-		return "suspect"
-	}
-	return "unauthorized"
-}
-
 // generateCompositeID generates a composite identifier for the event
 func (c *Correlator) generateCompositeID(event *DetectionEvent) (string, error) {
 	// Extract components from evidence
@@ -215,9 +468,10 @@ func (c *Correlator) generateCompositeID(event *DetectionEvent) (string, error)
 	port := c.extractPort(event)
 	manifestHash := c.extractManifestHashFromEvent(event)
 	processSignature := c.extractProcessSignature(event)
+	containerIdentity := c.extractContainerIdentity(event)
 
 	// Generate composite ID
-	return identifier.GenerateCompositeID(host, port, manifestHash, processSignature), nil
+	return identifier.GenerateCompositeID(host, port, manifestHash, processSignature, containerIdentity), nil
 }
 
 // Helper functions to extract data from evidence
@@ -251,6 +505,19 @@ func (c *Correlator) extractProcessSignature(event *DetectionEvent) string {
 	return ""
 }
 
+// extractContainerIdentity builds the optional container identity component
+// GenerateCompositeID uses to keep a composite ID stable across container
+// recreation (see backend/scanner's pkg/containerscan, which populates
+// image_digest and pod_id). Returns "" for non-containerized detections.
+func (c *Correlator) extractContainerIdentity(event *DetectionEvent) string {
+	imageDigest, _ := event.Evidence["image_digest"].(string)
+	podID, _ := event.Evidence["pod_id"].(string)
+	if imageDigest == "" && podID == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s", imageDigest, podID)
+}
+
 func (c *Correlator) extractManifestHash(detection *AggregatedDetection) string {
 	// Extract from evidence details
 	for _, evidence := range detection.Evidence {
@@ -290,11 +557,11 @@ func (c *Correlator) calculateScoreContribution(event *DetectionEvent) int {
 	// Use configured weights
 	switch evidenceType {
 	case "endpoint":
-		return c.scoringWeights.Endpoint
+		return c.weights().Endpoint
 	case "gateway":
-		return c.scoringWeights.Judge
+		return c.weights().Judge
 	case "network":
-		return c.scoringWeights.Network
+		return c.weights().Network
 	default:
 		return event.Score // Use event's own score if type unknown
 	}
@@ -309,21 +576,33 @@ func (c *Correlator) GetDetection(compositeID string) (*AggregatedDetection, boo
 	return detection, exists
 }
 
-// CleanupExpired removes detections older than the window size
+// CleanupExpired removes detections older than the window size from the
+// in-memory window and prunes (deletes) evidence older than the same
+// cutoff from the evidence pool via Pool.Prune, so a restart's Rehydrate
+// doesn't bring back evidence that had already aged out.
 func (c *Correlator) CleanupExpired() int {
-	c.window.mu.Lock()
-	defer c.window.mu.Unlock()
+	cutoff := time.Now().Add(-c.window.windowSize)
 
-	now := time.Now()
-	cutoff := now.Add(-c.window.windowSize)
+	c.window.mu.Lock()
 	removed := 0
-
 	for compositeID, detection := range c.window.detections {
 		if detection.LastUpdated.Before(cutoff) {
 			delete(c.window.detections, compositeID)
 			removed++
 		}
 	}
+	c.window.mu.Unlock()
+
+	if removed > 0 {
+		c.metricsEmitter.IncEvictions(removed)
+	}
+	c.metricsEmitter.SetActiveDetections(c.GetActiveDetections())
+
+	if prunedInPool, err := c.pool.Prune(cutoff); err != nil {
+		c.logger.Warn("evidence pool prune failed", "error", err)
+	} else if prunedInPool > 0 {
+		c.logger.Info("expired stale pending evidence in pool", "count", prunedInPool)
+	}
 
 	return removed
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/evidencepool"
 	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/registry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -34,9 +35,9 @@ var (
 	}
 
 	defaultThresholds = ClassificationThresholds{
-		Authorized:   4,  // <= 4 = authorized
-		Suspect:      8,  // 5-8 = suspect
-		Unauthorized: 9,  // >= 9 = unauthorized
+		Authorized:   4, // <= 4 = authorized
+		Suspect:      8, // 5-8 = suspect
+		Unauthorized: 9, // >= 9 = unauthorized
 	}
 )
 
@@ -54,8 +55,11 @@ func TestRegistryMatchForcesAuthorized(t *testing.T) {
 		time.Hour,
 		&registry.Client{}, // We'll replace this with mock via direct field access
 		"http://clickhouse:8123",
+		evidencepool.NewMemoryPool(),
 		defaultWeights,
 		defaultThresholds,
+		nil,
+		nil,
 	)
 
 	// Create a detection with endpoint evidence (score 11)
@@ -285,8 +289,8 @@ func TestScoreCalculationWithRegistryPenalty(t *testing.T) {
 // T018: Test negative score flooring to 0
 func TestNegativeScoreFlooringToZero(t *testing.T) {
 	testCases := []struct {
-		name           string
-		evidenceScore  int
+		name            string
+		evidenceScore   int
 		registryPenalty int
 		expectedScore   int
 	}{
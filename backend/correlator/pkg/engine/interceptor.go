@@ -0,0 +1,38 @@
+package engine
+
+import "context"
+
+// Handler performs correlation for a single event. The terminal Handler in
+// any chain is Correlator's own scoring logic; Interceptors wrap it.
+type Handler func(ctx context.Context, event *DetectionEvent) (*AggregatedDetection, error)
+
+// Interceptor wraps a Handler with cross-cutting behavior, in the style of
+// go-grpc-middleware's unary interceptor chain: call next to continue the
+// chain, or return without calling it to short-circuit (e.g. rate
+// limiting). See pkg/interceptor for the built-in panic-recovery, logging,
+// rate-limiting, tracing, and metrics interceptors.
+type Interceptor func(ctx context.Context, event *DetectionEvent, next Handler) (*AggregatedDetection, error)
+
+// Use appends interceptors to the chain ProcessEvent runs through, outermost
+// first: the first interceptor passed to the first Use call sees the event
+// before any other, and its next() call invokes the next interceptor (or,
+// once the chain is exhausted, the correlator's own scoring logic).
+// Use is not safe to call concurrently with ProcessEvent; register all
+// interceptors during startup before the consumer begins processing.
+func (c *Correlator) Use(interceptors ...Interceptor) {
+	c.interceptors = append(c.interceptors, interceptors...)
+}
+
+// chain builds a single Handler out of the registered interceptors plus the
+// terminal handler that performs the actual correlation.
+func chain(interceptors []Interceptor, terminal Handler) Handler {
+	handler := terminal
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor := interceptors[i]
+		next := handler
+		handler = func(ctx context.Context, event *DetectionEvent) (*AggregatedDetection, error) {
+			return interceptor(ctx, event, next)
+		}
+	}
+	return handler
+}
@@ -0,0 +1,59 @@
+package engine
+
+// MetricsEmitter reports Correlator's internal operating metrics to
+// whichever backend the deployment prefers (StatsD, Prometheus, OTLP; see
+// metrics_statsd.go, metrics_prometheus.go, metrics_otlp.go). It is
+// separate from pkg/interceptor's Metrics interceptor, which instruments
+// ProcessEvent from the outside for request-level counters — MetricsEmitter
+// instruments state internal to Correlator itself (window size, eviction
+// counts, per-source contribution histograms) that only the correlator can
+// observe directly.
+type MetricsEmitter interface {
+	// IncEventsProcessed counts one DetectionEvent processed, by type
+	// ("file", "process", "network", "gateway").
+	IncEventsProcessed(detectionType string)
+
+	// IncClassification counts one AggregatedDetection landing in
+	// classification ("authorized", "suspect", "unauthorized").
+	IncClassification(classification string)
+
+	// IncRegistryHit/Miss/Error record the outcome of one registry check.
+	IncRegistryHit()
+	IncRegistryMiss()
+	IncRegistryError()
+
+	// ObserveScore records one AggregatedDetection's final score, for
+	// mean/p95 tracking on the backend side.
+	ObserveScore(score int)
+
+	// ObserveScoreContribution records one EvidenceRecord's score
+	// contribution, broken down by source ("scanner-v1.0.0", "zeek", ...).
+	ObserveScoreContribution(source string, contribution int)
+
+	// SetActiveDetections reports the current size of the correlation
+	// window.
+	SetActiveDetections(count int)
+
+	// IncEvictions counts detections removed by one CleanupExpired run.
+	IncEvictions(count int)
+
+	// SetJudgeAvailable reports whether the most recently processed
+	// detection had Judge (gateway) evidence.
+	SetJudgeAvailable(available bool)
+}
+
+// NoopMetricsEmitter discards every metric. It is the default when
+// NewCorrelator is given a nil MetricsEmitter, so existing callers aren't
+// forced to supply one.
+type NoopMetricsEmitter struct{}
+
+func (NoopMetricsEmitter) IncEventsProcessed(string)            {}
+func (NoopMetricsEmitter) IncClassification(string)             {}
+func (NoopMetricsEmitter) IncRegistryHit()                      {}
+func (NoopMetricsEmitter) IncRegistryMiss()                     {}
+func (NoopMetricsEmitter) IncRegistryError()                    {}
+func (NoopMetricsEmitter) ObserveScore(int)                     {}
+func (NoopMetricsEmitter) ObserveScoreContribution(string, int) {}
+func (NoopMetricsEmitter) SetActiveDetections(int)              {}
+func (NoopMetricsEmitter) IncEvictions(int)                     {}
+func (NoopMetricsEmitter) SetJudgeAvailable(bool)               {}
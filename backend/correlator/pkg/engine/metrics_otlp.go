@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName is the OpenTelemetry instrumentation name OTLPEmitter's
+// instruments are reported under.
+const meterName = "github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+
+// OTLPEmitter reports MetricsEmitter calls through the OpenTelemetry
+// metrics API (otel.Meter), so whatever MeterProvider/exporter the
+// deployment configures (OTLP, stdout, etc.) receives them. With no
+// MeterProvider configured, otel's default no-op implementation makes this
+// free, same as the Tracing interceptor.
+type OTLPEmitter struct {
+	eventsProcessed   metric.Int64Counter
+	classification    metric.Int64Counter
+	registryHit       metric.Int64Counter
+	registryMiss      metric.Int64Counter
+	registryError     metric.Int64Counter
+	score             metric.Int64Histogram
+	scoreContribution metric.Int64Histogram
+	activeDetections  metric.Int64Gauge
+	evictionsTotal    metric.Int64Counter
+	judgeAvailable    metric.Int64Gauge
+}
+
+// NewOTLPEmitter creates the instruments OTLPEmitter reports through.
+func NewOTLPEmitter() (*OTLPEmitter, error) {
+	meter := otel.Meter(meterName)
+
+	eventsProcessed, err := meter.Int64Counter("correlator.events_processed", metric.WithDescription("Total detection events processed by Correlator"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create events_processed counter: %w", err)
+	}
+	classification, err := meter.Int64Counter("correlator.classification", metric.WithDescription("Total detections by classification"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create classification counter: %w", err)
+	}
+	registryHit, err := meter.Int64Counter("correlator.registry_hits", metric.WithDescription("Total registry checks that matched"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry_hits counter: %w", err)
+	}
+	registryMiss, err := meter.Int64Counter("correlator.registry_misses", metric.WithDescription("Total registry checks with no match"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry_misses counter: %w", err)
+	}
+	registryError, err := meter.Int64Counter("correlator.registry_errors", metric.WithDescription("Total registry checks that failed"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry_errors counter: %w", err)
+	}
+	score, err := meter.Int64Histogram("correlator.score", metric.WithDescription("Distribution of final detection scores"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create score histogram: %w", err)
+	}
+	scoreContribution, err := meter.Int64Histogram("correlator.score_contribution", metric.WithDescription("Distribution of per-evidence score contributions"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create score_contribution histogram: %w", err)
+	}
+	activeDetections, err := meter.Int64Gauge("correlator.active_detections", metric.WithDescription("Current number of detections in the correlation window"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create active_detections gauge: %w", err)
+	}
+	evictionsTotal, err := meter.Int64Counter("correlator.evictions", metric.WithDescription("Total detections evicted by CleanupExpired"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create evictions counter: %w", err)
+	}
+	judgeAvailable, err := meter.Int64Gauge("correlator.judge_available", metric.WithDescription("1 if the last processed detection had Judge evidence, else 0"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create judge_available gauge: %w", err)
+	}
+
+	return &OTLPEmitter{
+		eventsProcessed:   eventsProcessed,
+		classification:    classification,
+		registryHit:       registryHit,
+		registryMiss:      registryMiss,
+		registryError:     registryError,
+		score:             score,
+		scoreContribution: scoreContribution,
+		activeDetections:  activeDetections,
+		evictionsTotal:    evictionsTotal,
+		judgeAvailable:    judgeAvailable,
+	}, nil
+}
+
+func (o *OTLPEmitter) IncEventsProcessed(detectionType string) {
+	o.eventsProcessed.Add(context.Background(), 1, metric.WithAttributes(attribute.String("detection_type", detectionType)))
+}
+
+func (o *OTLPEmitter) IncClassification(classification string) {
+	o.classification.Add(context.Background(), 1, metric.WithAttributes(attribute.String("classification", classification)))
+}
+
+func (o *OTLPEmitter) IncRegistryHit() {
+	o.registryHit.Add(context.Background(), 1)
+}
+
+func (o *OTLPEmitter) IncRegistryMiss() {
+	o.registryMiss.Add(context.Background(), 1)
+}
+
+func (o *OTLPEmitter) IncRegistryError() {
+	o.registryError.Add(context.Background(), 1)
+}
+
+func (o *OTLPEmitter) ObserveScore(score int) {
+	o.score.Record(context.Background(), int64(score))
+}
+
+func (o *OTLPEmitter) ObserveScoreContribution(source string, contribution int) {
+	o.scoreContribution.Record(context.Background(), int64(contribution), metric.WithAttributes(attribute.String("source", source)))
+}
+
+func (o *OTLPEmitter) SetActiveDetections(count int) {
+	o.activeDetections.Record(context.Background(), int64(count))
+}
+
+func (o *OTLPEmitter) IncEvictions(count int) {
+	o.evictionsTotal.Add(context.Background(), int64(count))
+}
+
+func (o *OTLPEmitter) SetJudgeAvailable(available bool) {
+	value := int64(0)
+	if available {
+		value = 1
+	}
+	o.judgeAvailable.Record(context.Background(), value)
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// PrometheusEmitter reports MetricsEmitter calls as Prometheus series under
+// the correlator_engine_* namespace, distinct from pkg/metrics and
+// pkg/interceptor's Metrics interceptor so the two can't collide if both
+// happen to be wired up in the same binary.
+type PrometheusEmitter struct {
+	eventsProcessed   *prometheus.CounterVec
+	classification    *prometheus.CounterVec
+	registryHit       prometheus.Counter
+	registryMiss      prometheus.Counter
+	registryError     prometheus.Counter
+	score             prometheus.Histogram
+	scoreContribution *prometheus.HistogramVec
+	activeDetections  prometheus.Gauge
+	evictionsTotal    prometheus.Counter
+	judgeAvailable    prometheus.Gauge
+}
+
+// NewPrometheusEmitter registers the correlator_engine_* metrics against
+// the default Prometheus registry (via promauto, matching pkg/metrics'
+// convention) and returns an emitter backed by them.
+func NewPrometheusEmitter() *PrometheusEmitter {
+	return &PrometheusEmitter{
+		eventsProcessed: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "correlator_engine_events_processed_total",
+			Help: "Total detection events processed by Correlator, by detection type",
+		}, []string{"detection_type"}),
+		classification: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "correlator_engine_classification_total",
+			Help: "Total detections by classification, as observed directly in Correlator",
+		}, []string{"classification"}),
+		registryHit: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "correlator_engine_registry_hits_total",
+			Help: "Total registry checks that matched an authorized entry",
+		}),
+		registryMiss: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "correlator_engine_registry_misses_total",
+			Help: "Total registry checks that found no match",
+		}),
+		registryError: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "correlator_engine_registry_errors_total",
+			Help: "Total registry checks that failed",
+		}),
+		score: promauto.NewHistogram(prometheus.HistogramOpts{
+			Name:    "correlator_engine_score",
+			Help:    "Distribution of final AggregatedDetection scores",
+			Buckets: prometheus.LinearBuckets(0, 2, 12),
+		}),
+		scoreContribution: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "correlator_engine_score_contribution",
+			Help:    "Distribution of per-EvidenceRecord score contributions, by source",
+			Buckets: prometheus.LinearBuckets(0, 2, 12),
+		}, []string{"source"}),
+		activeDetections: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "correlator_engine_active_detections",
+			Help: "Current number of detections in the correlation window",
+		}),
+		evictionsTotal: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "correlator_engine_evictions_total",
+			Help: "Total detections evicted from the correlation window by CleanupExpired",
+		}),
+		judgeAvailable: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "correlator_engine_judge_available",
+			Help: "1 if the most recently processed detection had Judge (gateway) evidence, else 0",
+		}),
+	}
+}
+
+func (p *PrometheusEmitter) IncEventsProcessed(detectionType string) {
+	p.eventsProcessed.WithLabelValues(detectionType).Inc()
+}
+
+func (p *PrometheusEmitter) IncClassification(classification string) {
+	p.classification.WithLabelValues(classification).Inc()
+}
+
+func (p *PrometheusEmitter) IncRegistryHit()   { p.registryHit.Inc() }
+func (p *PrometheusEmitter) IncRegistryMiss()  { p.registryMiss.Inc() }
+func (p *PrometheusEmitter) IncRegistryError() { p.registryError.Inc() }
+
+func (p *PrometheusEmitter) ObserveScore(score int) {
+	p.score.Observe(float64(score))
+}
+
+func (p *PrometheusEmitter) ObserveScoreContribution(source string, contribution int) {
+	p.scoreContribution.WithLabelValues(source).Observe(float64(contribution))
+}
+
+func (p *PrometheusEmitter) SetActiveDetections(count int) {
+	p.activeDetections.Set(float64(count))
+}
+
+func (p *PrometheusEmitter) IncEvictions(count int) {
+	p.evictionsTotal.Add(float64(count))
+}
+
+func (p *PrometheusEmitter) SetJudgeAvailable(available bool) {
+	if available {
+		p.judgeAvailable.Set(1)
+		return
+	}
+	p.judgeAvailable.Set(0)
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+)
+
+// StatsDEmitter reports MetricsEmitter calls over UDP in plain-text StatsD
+// protocol (<metric>:<value>|<type>). It does not batch; each call is one
+// UDP packet, which is how most StatsD client libraries operate at
+// moderate event volume.
+type StatsDEmitter struct {
+	conn   net.Conn
+	prefix string
+	logger logging.Logger
+}
+
+// NewStatsDEmitter dials addr (e.g. "127.0.0.1:8125") and returns an
+// emitter that prefixes every metric name with prefix + ".". A nil logger
+// falls back to a discarding logger.
+func NewStatsDEmitter(addr, prefix string, logger logging.Logger) (*StatsDEmitter, error) {
+	if logger == nil {
+		logger = logging.Noop()
+	}
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial statsd at %s: %w", addr, err)
+	}
+	return &StatsDEmitter{conn: conn, prefix: prefix, logger: logger}, nil
+}
+
+func (s *StatsDEmitter) send(stat string) {
+	if _, err := s.conn.Write([]byte(stat)); err != nil {
+		// A dropped metric shouldn't affect correlation; just log it.
+		s.logger.Warn("failed to send statsd metric", "error", err)
+	}
+}
+
+func (s *StatsDEmitter) counter(name string, tags string) {
+	s.send(fmt.Sprintf("%s.%s%s:1|c", s.prefix, name, tags))
+}
+
+func (s *StatsDEmitter) gauge(name string, value int) {
+	s.send(fmt.Sprintf("%s.%s:%d|g", s.prefix, name, value))
+}
+
+func (s *StatsDEmitter) histogram(name string, value int, tags string) {
+	s.send(fmt.Sprintf("%s.%s%s:%d|h", s.prefix, name, tags, value))
+}
+
+func (s *StatsDEmitter) IncEventsProcessed(detectionType string) {
+	s.counter("events_processed", ",detection_type="+detectionType)
+}
+
+func (s *StatsDEmitter) IncClassification(classification string) {
+	s.counter("classification", ",classification="+classification)
+}
+
+func (s *StatsDEmitter) IncRegistryHit()   { s.counter("registry_hit", "") }
+func (s *StatsDEmitter) IncRegistryMiss()  { s.counter("registry_miss", "") }
+func (s *StatsDEmitter) IncRegistryError() { s.counter("registry_error", "") }
+
+func (s *StatsDEmitter) ObserveScore(score int) {
+	s.histogram("score", score, "")
+}
+
+func (s *StatsDEmitter) ObserveScoreContribution(source string, contribution int) {
+	s.histogram("score_contribution", contribution, ",source="+source)
+}
+
+func (s *StatsDEmitter) SetActiveDetections(count int) {
+	s.gauge("active_detections", count)
+}
+
+func (s *StatsDEmitter) IncEvictions(count int) {
+	s.send(fmt.Sprintf("%s.evictions:%d|c", s.prefix, count))
+}
+
+func (s *StatsDEmitter) SetJudgeAvailable(available bool) {
+	value := 0
+	if available {
+		value = 1
+	}
+	s.gauge("judge_available", value)
+}
+
+// Close releases the underlying UDP socket.
+func (s *StatsDEmitter) Close() error {
+	return s.conn.Close()
+}
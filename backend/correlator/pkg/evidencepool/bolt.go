@@ -0,0 +1,193 @@
+package evidencepool
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// evidenceBucket is the single bbolt bucket evidence records live in, keyed
+// by Evidence.Hash.
+var evidenceBucket = []byte("evidence")
+
+// BoltPool is the default, restart-surviving Pool backed by a local BoltDB
+// file. One BoltPool owns its file exclusively (bbolt takes a file lock),
+// so only one correlator instance should point at a given path.
+type BoltPool struct {
+	db *bolt.DB
+}
+
+// NewBoltPool opens (creating if necessary) a BoltDB-backed evidence pool
+// at path.
+func NewBoltPool(path string) (*BoltPool, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open evidence pool db: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(evidenceBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create evidence bucket: %w", err)
+	}
+
+	return &BoltPool{db: db}, nil
+}
+
+func (p *BoltPool) AddPending(evidence Evidence) (State, error) {
+	var state State
+	err := p.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(evidenceBucket)
+
+		if existing := bucket.Get([]byte(evidence.Hash)); existing != nil {
+			var stored Evidence
+			if err := json.Unmarshal(existing, &stored); err != nil {
+				return fmt.Errorf("failed to decode existing evidence: %w", err)
+			}
+			state = stored.State
+			return nil
+		}
+
+		evidence.State = StatePending
+		data, err := json.Marshal(evidence)
+		if err != nil {
+			return fmt.Errorf("failed to encode evidence: %w", err)
+		}
+		state = StatePending
+		return bucket.Put([]byte(evidence.Hash), data)
+	})
+	return state, err
+}
+
+func (p *BoltPool) MarkCommitted(compositeID string, hashes []string, committedAt time.Time) error {
+	return p.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(evidenceBucket)
+		wanted := make(map[string]bool, len(hashes))
+		for _, h := range hashes {
+			wanted[h] = true
+		}
+
+		for hash := range wanted {
+			data := bucket.Get([]byte(hash))
+			if data == nil {
+				continue
+			}
+			var evidence Evidence
+			if err := json.Unmarshal(data, &evidence); err != nil {
+				return fmt.Errorf("failed to decode evidence %s: %w", hash, err)
+			}
+			if evidence.CompositeID != compositeID || evidence.State != StatePending {
+				continue
+			}
+			evidence.State = StateCommitted
+			evidence.CommittedAt = committedAt
+			updated, err := json.Marshal(evidence)
+			if err != nil {
+				return fmt.Errorf("failed to encode evidence %s: %w", hash, err)
+			}
+			if err := bucket.Put([]byte(hash), updated); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (p *BoltPool) IsCommitted(hash string) (bool, error) {
+	committed := false
+	err := p.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(evidenceBucket).Get([]byte(hash))
+		if data == nil {
+			return nil
+		}
+		var evidence Evidence
+		if err := json.Unmarshal(data, &evidence); err != nil {
+			return fmt.Errorf("failed to decode evidence %s: %w", hash, err)
+		}
+		committed = evidence.State == StateCommitted
+		return nil
+	})
+	return committed, err
+}
+
+func (p *BoltPool) PendingForComposite(compositeID string) ([]Evidence, error) {
+	var result []Evidence
+	err := p.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(evidenceBucket).ForEach(func(_, data []byte) error {
+			var evidence Evidence
+			if err := json.Unmarshal(data, &evidence); err != nil {
+				return fmt.Errorf("failed to decode evidence: %w", err)
+			}
+			if evidence.CompositeID == compositeID && evidence.State == StatePending {
+				result = append(result, evidence)
+			}
+			return nil
+		})
+	})
+	return result, err
+}
+
+func (p *BoltPool) AllPending() ([]Evidence, error) {
+	var result []Evidence
+	err := p.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(evidenceBucket).ForEach(func(_, data []byte) error {
+			var evidence Evidence
+			if err := json.Unmarshal(data, &evidence); err != nil {
+				return fmt.Errorf("failed to decode evidence: %w", err)
+			}
+			if evidence.State == StatePending {
+				result = append(result, evidence)
+			}
+			return nil
+		})
+	})
+	return result, err
+}
+
+func (p *BoltPool) Prune(cutoff time.Time) (int, error) {
+	pruned := 0
+	err := p.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(evidenceBucket)
+
+		// Collect keys to remove first: bbolt forbids Delete during ForEach.
+		var toRemove [][]byte
+		err := bucket.ForEach(func(key, data []byte) error {
+			var evidence Evidence
+			if err := json.Unmarshal(data, &evidence); err != nil {
+				return fmt.Errorf("failed to decode evidence: %w", err)
+			}
+			switch evidence.State {
+			case StatePending:
+				if evidence.Timestamp.Before(cutoff) {
+					toRemove = append(toRemove, append([]byte(nil), key...))
+				}
+			case StateCommitted:
+				if evidence.CommittedAt.Before(cutoff) {
+					toRemove = append(toRemove, append([]byte(nil), key...))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range toRemove {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+			pruned++
+		}
+		return nil
+	})
+	return pruned, err
+}
+
+func (p *BoltPool) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,91 @@
+package evidencepool
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestBoltPool(t *testing.T) *BoltPool {
+	t.Helper()
+	pool, err := NewBoltPool(filepath.Join(t.TempDir(), "evidence.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { pool.Close() })
+	return pool
+}
+
+func TestBoltPoolAddPendingIsIdempotentOnReplay(t *testing.T) {
+	pool := openTestBoltPool(t)
+	evidence := Evidence{Hash: "h1", CompositeID: "c1", Timestamp: time.Now()}
+
+	state, err := pool.AddPending(evidence)
+	assert.NoError(t, err)
+	assert.Equal(t, StatePending, state)
+
+	state, err = pool.AddPending(evidence)
+	assert.NoError(t, err)
+	assert.Equal(t, StatePending, state)
+}
+
+func TestBoltPoolMarkCommittedThenIsCommitted(t *testing.T) {
+	pool := openTestBoltPool(t)
+	now := time.Now()
+	pool.AddPending(Evidence{Hash: "h1", CompositeID: "c1", Timestamp: now})
+
+	err := pool.MarkCommitted("c1", []string{"h1"}, now)
+	assert.NoError(t, err)
+
+	committed, err := pool.IsCommitted("h1")
+	assert.NoError(t, err)
+	assert.True(t, committed)
+}
+
+func TestBoltPoolPrunePendingByTimestamp(t *testing.T) {
+	pool := openTestBoltPool(t)
+	cutoff := time.Now()
+	pool.AddPending(Evidence{Hash: "old", CompositeID: "c1", Timestamp: cutoff.Add(-time.Hour)})
+	pool.AddPending(Evidence{Hash: "new", CompositeID: "c1", Timestamp: cutoff.Add(time.Hour)})
+
+	pruned, err := pool.Prune(cutoff)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+
+	all, _ := pool.AllPending()
+	assert.Len(t, all, 1)
+	assert.Equal(t, "new", all[0].Hash)
+}
+
+func TestBoltPoolPruneCommittedByCommittedAt(t *testing.T) {
+	pool := openTestBoltPool(t)
+	cutoff := time.Now()
+	pool.AddPending(Evidence{Hash: "h1", CompositeID: "c1", Timestamp: cutoff.Add(-time.Hour)})
+	pool.MarkCommitted("c1", []string{"h1"}, cutoff.Add(-time.Minute))
+
+	pruned, err := pool.Prune(cutoff)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+	committed, _ := pool.IsCommitted("h1")
+	assert.False(t, committed)
+}
+
+func TestBoltPoolPersistsAcrossReopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "evidence.db")
+
+	pool, err := NewBoltPool(path)
+	require.NoError(t, err)
+	pool.AddPending(Evidence{Hash: "h1", CompositeID: "c1", Timestamp: time.Now()})
+	require.NoError(t, pool.Close())
+
+	reopened, err := NewBoltPool(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	pending, err := reopened.AllPending()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+}
@@ -0,0 +1,109 @@
+package evidencepool
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryPool is an in-memory Pool. It does not survive a restart; use it in
+// tests or when EVIDENCE_POOL_PATH is set to ":memory:".
+type MemoryPool struct {
+	mu     sync.RWMutex
+	byHash map[string]Evidence
+}
+
+// NewMemoryPool creates an empty in-memory evidence pool.
+func NewMemoryPool() *MemoryPool {
+	return &MemoryPool{byHash: make(map[string]Evidence)}
+}
+
+func (p *MemoryPool) AddPending(evidence Evidence) (State, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if existing, ok := p.byHash[evidence.Hash]; ok {
+		return existing.State, nil
+	}
+	evidence.State = StatePending
+	p.byHash[evidence.Hash] = evidence
+	return StatePending, nil
+}
+
+func (p *MemoryPool) MarkCommitted(compositeID string, hashes []string, committedAt time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	wanted := make(map[string]bool, len(hashes))
+	for _, h := range hashes {
+		wanted[h] = true
+	}
+	for hash, evidence := range p.byHash {
+		if evidence.CompositeID != compositeID || evidence.State != StatePending || !wanted[hash] {
+			continue
+		}
+		evidence.State = StateCommitted
+		evidence.CommittedAt = committedAt
+		p.byHash[hash] = evidence
+	}
+	return nil
+}
+
+func (p *MemoryPool) IsCommitted(hash string) (bool, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	evidence, ok := p.byHash[hash]
+	return ok && evidence.State == StateCommitted, nil
+}
+
+func (p *MemoryPool) PendingForComposite(compositeID string) ([]Evidence, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var result []Evidence
+	for _, evidence := range p.byHash {
+		if evidence.CompositeID == compositeID && evidence.State == StatePending {
+			result = append(result, evidence)
+		}
+	}
+	return result, nil
+}
+
+func (p *MemoryPool) AllPending() ([]Evidence, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var result []Evidence
+	for _, evidence := range p.byHash {
+		if evidence.State == StatePending {
+			result = append(result, evidence)
+		}
+	}
+	return result, nil
+}
+
+func (p *MemoryPool) Prune(cutoff time.Time) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pruned := 0
+	for hash, evidence := range p.byHash {
+		switch evidence.State {
+		case StatePending:
+			if evidence.Timestamp.Before(cutoff) {
+				delete(p.byHash, hash)
+				pruned++
+			}
+		case StateCommitted:
+			if evidence.CommittedAt.Before(cutoff) {
+				delete(p.byHash, hash)
+				pruned++
+			}
+		}
+	}
+	return pruned, nil
+}
+
+func (p *MemoryPool) Close() error {
+	return nil
+}
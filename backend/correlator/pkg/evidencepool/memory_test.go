@@ -0,0 +1,97 @@
+package evidencepool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryPoolAddPendingIsIdempotentOnReplay(t *testing.T) {
+	pool := NewMemoryPool()
+	evidence := Evidence{Hash: "h1", CompositeID: "c1", Timestamp: time.Now()}
+
+	state, err := pool.AddPending(evidence)
+	assert.NoError(t, err)
+	assert.Equal(t, StatePending, state)
+
+	// Replaying the same hash must not re-add or change its state.
+	state, err = pool.AddPending(evidence)
+	assert.NoError(t, err)
+	assert.Equal(t, StatePending, state)
+}
+
+func TestMemoryPoolMarkCommittedOnlyAffectsWantedPendingHashes(t *testing.T) {
+	pool := NewMemoryPool()
+	now := time.Now()
+	pool.AddPending(Evidence{Hash: "h1", CompositeID: "c1", Timestamp: now})
+	pool.AddPending(Evidence{Hash: "h2", CompositeID: "c1", Timestamp: now})
+	pool.AddPending(Evidence{Hash: "h3", CompositeID: "c2", Timestamp: now})
+
+	err := pool.MarkCommitted("c1", []string{"h1"}, now)
+	assert.NoError(t, err)
+
+	committed, _ := pool.IsCommitted("h1")
+	assert.True(t, committed)
+
+	stillPending, _ := pool.IsCommitted("h2")
+	assert.False(t, stillPending)
+
+	otherComposite, _ := pool.IsCommitted("h3")
+	assert.False(t, otherComposite)
+}
+
+func TestMemoryPoolPendingForComposite(t *testing.T) {
+	pool := NewMemoryPool()
+	now := time.Now()
+	pool.AddPending(Evidence{Hash: "h1", CompositeID: "c1", Timestamp: now})
+	pool.AddPending(Evidence{Hash: "h2", CompositeID: "c2", Timestamp: now})
+
+	result, err := pool.PendingForComposite("c1")
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "h1", result[0].Hash)
+}
+
+func TestMemoryPoolPrunePendingByTimestamp(t *testing.T) {
+	pool := NewMemoryPool()
+	cutoff := time.Now()
+	pool.AddPending(Evidence{Hash: "old", CompositeID: "c1", Timestamp: cutoff.Add(-time.Hour)})
+	pool.AddPending(Evidence{Hash: "new", CompositeID: "c1", Timestamp: cutoff.Add(time.Hour)})
+
+	pruned, err := pool.Prune(cutoff)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+
+	all, _ := pool.AllPending()
+	assert.Len(t, all, 1)
+	assert.Equal(t, "new", all[0].Hash)
+}
+
+func TestMemoryPoolPruneCommittedByCommittedAt(t *testing.T) {
+	pool := NewMemoryPool()
+	cutoff := time.Now()
+	pool.AddPending(Evidence{Hash: "h1", CompositeID: "c1", Timestamp: cutoff.Add(-time.Hour)})
+	pool.MarkCommitted("c1", []string{"h1"}, cutoff.Add(-time.Minute))
+
+	pruned, err := pool.Prune(cutoff)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+	committed, _ := pool.IsCommitted("h1")
+	assert.False(t, committed)
+}
+
+func TestMemoryPoolPruneKeepsRecentlyCommittedEvidence(t *testing.T) {
+	pool := NewMemoryPool()
+	cutoff := time.Now()
+	pool.AddPending(Evidence{Hash: "h1", CompositeID: "c1", Timestamp: cutoff.Add(-time.Hour)})
+	pool.MarkCommitted("c1", []string{"h1"}, cutoff.Add(time.Hour))
+
+	pruned, err := pool.Prune(cutoff)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, pruned)
+	committed, _ := pool.IsCommitted("h1")
+	assert.True(t, committed)
+}
@@ -0,0 +1,88 @@
+// Package evidencepool tracks the lifecycle of evidence contributed to a
+// detection: each Evidence is identified by a deterministic content hash
+// and moves from pending to committed (or is pruned for age). Persisting
+// this state lets the correlator survive a restart and lets ProcessEvent
+// reject evidence an agent replays after it already committed.
+// Reference: FR-002 (Multi-layer detection), FR-003 (Weighted scoring)
+package evidencepool
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// State is the lifecycle stage of a piece of evidence.
+type State string
+
+const (
+	StatePending   State = "pending"
+	StateCommitted State = "committed"
+	StateExpired   State = "expired"
+)
+
+// Evidence is the persisted form of engine.EvidenceRecord, addressed by a
+// deterministic content hash so the same signal replayed by an agent is
+// recognized rather than re-scored.
+type Evidence struct {
+	Hash              string                 `json:"hash"`
+	CompositeID       string                 `json:"composite_id"`
+	HostIDHash        string                 `json:"host_id_hash"`
+	Type              string                 `json:"type"`
+	Source            string                 `json:"source"`
+	ScoreContribution int                    `json:"score_contribution"`
+	Timestamp         time.Time              `json:"timestamp"`
+	Details           map[string]interface{} `json:"details"`
+	State             State                  `json:"state"`
+	// CommittedAt is zero until MarkCommitted; Prune uses it instead of
+	// Timestamp so a long-pending detection that just committed isn't
+	// immediately pruned as expired.
+	CommittedAt time.Time `json:"committed_at,omitempty"`
+}
+
+// HashEvidence deterministically identifies a piece of evidence by its
+// content, so the same signal redelivered by an agent (e.g. after a
+// reconnect) hashes to the same ID instead of being treated as new.
+func HashEvidence(compositeID, evidenceType, source string, timestamp time.Time, details map[string]interface{}) string {
+	detailsJSON, _ := json.Marshal(details) // best-effort: nil/unmarshalable details just hash as "null"
+	h := sha256.New()
+	fmt.Fprintf(h, "%s:%s:%s:%d:%s", compositeID, evidenceType, source, timestamp.UnixNano(), detailsJSON)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Pool is the evidence lifecycle store. Implementations must be safe for
+// concurrent use.
+type Pool interface {
+	// AddPending records evidence as pending. If the hash already exists
+	// (an agent replayed the same signal), AddPending is a no-op and
+	// returns the existing state so the caller can detect the replay.
+	AddPending(evidence Evidence) (State, error)
+
+	// MarkCommitted transitions every pending hash for compositeID to
+	// committed, stamping CommittedAt. Hashes not currently pending are
+	// left untouched.
+	MarkCommitted(compositeID string, hashes []string, committedAt time.Time) error
+
+	// IsCommitted reports whether hash has already been committed.
+	IsCommitted(hash string) (bool, error)
+
+	// PendingForComposite returns every pending Evidence for compositeID,
+	// e.g. to rehydrate the in-memory correlation window after a restart.
+	PendingForComposite(compositeID string) ([]Evidence, error)
+
+	// AllPending returns every pending Evidence across all composite IDs,
+	// for startup crash recovery.
+	AllPending() ([]Evidence, error)
+
+	// Prune deletes pending evidence older than cutoff (it never committed
+	// in time) and committed evidence whose CommittedAt predates cutoff
+	// (enough time has passed that a replay of it is no longer expected),
+	// reporting how many were removed. Without this, every piece of
+	// evidence ever seen would stay resident for the life of the process.
+	Prune(cutoff time.Time) (int, error)
+
+	// Close releases any resources held by the store.
+	Close() error
+}
@@ -0,0 +1,122 @@
+// Package httpx provides small net/http middlewares shared by MCPeeker's
+// HTTP servers.
+// Reference: FR-010 (authentication/audit)
+package httpx
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// contextKey is an unexported type so httpx's context keys never collide
+// with keys set by other packages.
+type contextKey int
+
+const realIPKey contextKey = iota
+
+// ParseTrustedProxies parses a comma-separated list of CIDRs (e.g.
+// "10.0.0.0/8,127.0.0.1/32") into the form RealIP expects. An empty string
+// yields no trusted proxies, meaning X-Real-IP/X-Forwarded-For are never
+// trusted and RemoteAddr is always used.
+func ParseTrustedProxies(raw string) ([]*net.IPNet, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var nets []*net.IPNet
+	for _, cidr := range strings.Split(raw, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// RealIP returns middleware that resolves the caller's real IP address,
+// trusting proxy-supplied headers only when the immediate peer
+// (r.RemoteAddr) is within trustedProxies. It checks X-Real-IP first, then
+// the right-most non-trusted hop of X-Forwarded-For, and finally falls
+// back to RemoteAddr. The resolved IP is stored in the request context and
+// retrievable via RealIPFromContext.
+func RealIP(trustedProxies []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolveRealIP(r, trustedProxies)
+			ctx := context.WithValue(r.Context(), realIPKey, ip)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RealIPFromContext returns the IP resolved by RealIP's middleware, or ""
+// if none was set (e.g. the middleware was never applied).
+func RealIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(realIPKey).(string)
+	return ip
+}
+
+func resolveRealIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	remoteIP := stripPort(r.RemoteAddr)
+	if !isTrusted(remoteIP, trustedProxies) {
+		return remoteIP
+	}
+
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return realIP
+	}
+
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		if ip := rightmostUntrustedHop(xff, trustedProxies); ip != "" {
+			return ip
+		}
+	}
+
+	return remoteIP
+}
+
+// rightmostUntrustedHop walks X-Forwarded-For from right to left, skipping
+// hops that are themselves trusted proxies, and returns the first
+// (right-most) hop that isn't — the closest untrusted hop to our trusted
+// proxy, and therefore the most credible claim of the real client IP.
+func rightmostUntrustedHop(xff string, trustedProxies []*net.IPNet) string {
+	hops := strings.Split(xff, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !isTrusted(hop, trustedProxies) {
+			return hop
+		}
+	}
+	return ""
+}
+
+func isTrusted(ip string, trustedProxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range trustedProxies {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+func stripPort(hostport string) string {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return hostport
+	}
+	return host
+}
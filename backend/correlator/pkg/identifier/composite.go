@@ -35,9 +35,18 @@ import (
 //	    "b4d6e8f0a2c4e6f8a0c2e4f6a8c0e2f4a6c8e0f2a4c6e8f0a2c4e6f8a0c2e4f6",
 //	)
 //	// Result: "e7f9d1c3b5a7e9f1d3c5b7a9e1f3d5c7b9a1e3f5d7c9b1a3e5f7d9c1b3a5e7f9"
-func GenerateCompositeID(host string, port int, manifestHash string, processSignature string) string {
-	// Construct composite string: host:port:manifest:signature
+//
+// containerIdentity is an optional fifth component (e.g. "docker:<image
+// digest>:<pod_id>") that keeps a container's composite ID stable across
+// recreation as long as its image and manifest don't change, even though
+// its container_id and IP do. Pass at most one; extra values are ignored so
+// existing four-argument callers keep compiling unchanged.
+func GenerateCompositeID(host string, port int, manifestHash string, processSignature string, containerIdentity ...string) string {
+	// Construct composite string: host:port:manifest:signature[:container]
 	compositeString := fmt.Sprintf("%s:%d:%s:%s", host, port, manifestHash, processSignature)
+	if len(containerIdentity) > 0 && containerIdentity[0] != "" {
+		compositeString = fmt.Sprintf("%s:%s", compositeString, containerIdentity[0])
+	}
 
 	// Hash with SHA256
 	hash := sha256.Sum256([]byte(compositeString))
@@ -1,20 +1,165 @@
 // Package identifier provides host ID hashing utilities for privacy compliance.
 // Reference: FR-008 - Host identifiers must be hashed before storage
+//
+// Hashes are keyed (HMAC-SHA256, or Argon2id for extra margin if the key
+// leaks) rather than bare SHA256, since host_id_hash is stored in
+// ClickHouse where a bare hash of the small, enumerable space of corporate
+// hostnames and RFC1918 addresses is brute-forceable offline. See Hasher
+// and NewHasherFromEnv for configuration.
 package identifier
 
 import (
+	"bytes"
+	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Mode selects the algorithm a Hasher uses.
+type Mode int
+
+const (
+	ModeHMAC     Mode = iota // HMAC-SHA256, keyed with a per-deployment secret; the default
+	ModeArgon2id             // resists offline brute-forcing if the key leaks, at the cost of slower hashing
+)
+
+// currentKeyID is prefixed to every hash produced by a keyed Hasher so a
+// future key rotation can introduce v2 without making v1 hashes
+// unrecognizable (see ValidateHashFormat).
+const currentKeyID = "v1"
+
+// Argon2id parameters per the request: time=2, memory=64MiB, threads=2.
+const (
+	argon2Time      = 2
+	argon2MemoryKiB = 64 * 1024
+	argon2Threads   = 2
+	argon2KeyLen    = 32
+)
+
+var hashFormatRE = regexp.MustCompile(`^v[0-9]+:[0-9a-f]+$`)
+
+// Hasher hashes host identifiers with a keyed algorithm. Construct one with
+// NewHasher or NewHasherFromEnv; the zero value is not usable.
+type Hasher struct {
+	mode Mode
+	key  []byte
+}
+
+// NewHasher builds a Hasher from an explicit key and mode. Most callers
+// should use NewHasherFromEnv instead so the key never appears in code or
+// config files.
+func NewHasher(key []byte, mode Mode) *Hasher {
+	return &Hasher{mode: mode, key: key}
+}
+
+// NewHasherFromEnv builds a Hasher from HOST_ID_HMAC_KEY (the literal key)
+// or HOST_ID_HMAC_KEY_FILE (a path to a file containing it), and selects
+// ModeArgon2id when HOST_ID_HASH_MODE=argon2id. Returns an error if neither
+// env var is set.
+func NewHasherFromEnv() (*Hasher, error) {
+	key, err := loadKeyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	mode := ModeHMAC
+	if strings.EqualFold(os.Getenv("HOST_ID_HASH_MODE"), "argon2id") {
+		mode = ModeArgon2id
+	}
+	return NewHasher(key, mode), nil
+}
+
+func loadKeyFromEnv() ([]byte, error) {
+	if key := os.Getenv("HOST_ID_HMAC_KEY"); key != "" {
+		return []byte(key), nil
+	}
+	if path := os.Getenv("HOST_ID_HMAC_KEY_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read HOST_ID_HMAC_KEY_FILE: %w", err)
+		}
+		return bytes.TrimSpace(data), nil
+	}
+	return nil, fmt.Errorf("no host ID hashing key configured: set HOST_ID_HMAC_KEY or HOST_ID_HMAC_KEY_FILE")
+}
+
+// Hash hashes hostID under h's mode and prefixes the result with the active
+// key id, e.g. "v1:9f86d0...".
+func (h *Hasher) Hash(hostID string) string {
+	var sum []byte
+	switch h.mode {
+	case ModeArgon2id:
+		sum = argon2.IDKey([]byte(hostID), h.key, argon2Time, argon2MemoryKiB, argon2Threads, argon2KeyLen)
+	default:
+		mac := hmac.New(sha256.New, h.key)
+		mac.Write([]byte(hostID))
+		sum = mac.Sum(nil)
+	}
+	return currentKeyID + ":" + hex.EncodeToString(sum)
+}
+
+// BatchHash hashes multiple host identifiers, in the same order as
+// hostIDs. In ModeArgon2id it fans the work out across a worker pool
+// sized to GOMAXPROCS instead of hashing serially.
+func (h *Hasher) BatchHash(hostIDs []string) []string {
+	hashed := make([]string, len(hostIDs))
+	if h.mode != ModeArgon2id || len(hostIDs) < 2 {
+		for i, hostID := range hostIDs {
+			hashed[i] = h.Hash(hostID)
+		}
+		return hashed
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i, hostID := range hostIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, hostID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			hashed[i] = h.Hash(hostID)
+		}(i, hostID)
+	}
+	wg.Wait()
+	return hashed
+}
+
+var (
+	defaultHasherOnce sync.Once
+	defaultHasher     *Hasher
+	defaultHasherErr  error
+	legacyWarnOnce    sync.Once
 )
 
-// HashHostID hashes a host identifier using SHA256 for privacy compliance.
-// Per FR-008, host identifiers must be hashed before storage in ClickHouse.
+func defaultHasherInstance() (*Hasher, error) {
+	defaultHasherOnce.Do(func() {
+		defaultHasher, defaultHasherErr = NewHasherFromEnv()
+	})
+	return defaultHasher, defaultHasherErr
+}
+
+// HashHostID hashes a host identifier using the package's default Hasher,
+// configured from HOST_ID_HMAC_KEY / HOST_ID_HMAC_KEY_FILE /
+// HOST_ID_HASH_MODE (see Hasher). Per FR-008, host identifiers must be
+// hashed before storage in ClickHouse.
+//
+// If no key is configured, HashHostID falls back to the legacy unkeyed
+// SHA256 format (see LegacyHash) and logs a one-time warning to stderr.
 //
 // Args:
 //   - hostID: Original host identifier (IP, hostname, container ID, etc.)
 //
 // Returns:
-//   - 64-character hex string (SHA256 hash)
+//   - "v1:<hex>" (keyed) or 64-character hex (legacy, unkeyed) — see
+//     ValidateHashFormat
 //
 // Example:
 //
@@ -26,24 +171,40 @@ import (
 //   - Hash is one-way: cannot reverse to get original identifier
 //   - Same host_id always produces same hash (for correlation)
 func HashHostID(hostID string) string {
-	hash := sha256.Sum256([]byte(hostID))
-	return hex.EncodeToString(hash[:])
+	h, err := defaultHasherInstance()
+	if err != nil {
+		legacyWarnOnce.Do(func() {
+			fmt.Fprintf(os.Stderr, "identifier: %s; falling back to legacy unkeyed SHA256 host_id_hash, see package docs\n", err)
+		})
+		return LegacyHash(hostID)
+	}
+	return h.Hash(hostID)
 }
 
-// ValidateHashFormat checks if a hash has the correct format.
-// Must be exactly 64 hex characters (SHA256 output).
+// LegacyHash reproduces the pre-keyed (bare SHA256) host_id_hash format.
+// Kept only so HashHostID can fall back to it when no key is configured
+// and so RehashHostIDs can recognize rows still in that format; new code
+// should not call this directly.
+func LegacyHash(hostID string) string {
+	sum := sha256.Sum256([]byte(hostID))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidateHashFormat checks if a hash has the correct format: either the
+// legacy 64-character hex (bare SHA256) or a key-id-prefixed "vN:<hex>"
+// form produced by a keyed Hasher.
 func ValidateHashFormat(hash string) bool {
-	if len(hash) != 64 {
-		return false
+	if len(hash) == 64 {
+		_, err := hex.DecodeString(hash)
+		return err == nil
 	}
-
-	// Check all characters are hex
-	_, err := hex.DecodeString(hash)
-	return err == nil
+	return hashFormatRE.MatchString(hash)
 }
 
-// BatchHashHostIDs hashes multiple host identifiers in a single call.
-// Useful for bulk processing or batch operations.
+// BatchHashHostIDs hashes multiple host identifiers using the package's
+// default Hasher (see HashHostID). Useful for bulk processing or batch
+// operations; fans out across a worker pool when Argon2id is selected
+// (see Hasher.BatchHash).
 //
 // Args:
 //   - hostIDs: Slice of original host identifiers
@@ -51,9 +212,16 @@ func ValidateHashFormat(hash string) bool {
 // Returns:
 //   - Slice of hashed identifiers in same order as input
 func BatchHashHostIDs(hostIDs []string) []string {
-	hashed := make([]string, len(hostIDs))
-	for i, hostID := range hostIDs {
-		hashed[i] = HashHostID(hostID)
+	h, err := defaultHasherInstance()
+	if err != nil {
+		legacyWarnOnce.Do(func() {
+			fmt.Fprintf(os.Stderr, "identifier: %s; falling back to legacy unkeyed SHA256 host_id_hash, see package docs\n", err)
+		})
+		hashed := make([]string, len(hostIDs))
+		for i, hostID := range hostIDs {
+			hashed[i] = LegacyHash(hostID)
+		}
+		return hashed
 	}
-	return hashed
+	return h.BatchHash(hostIDs)
 }
@@ -0,0 +1,34 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+)
+
+// Logging returns an Interceptor that logs one structured line per event,
+// once the rest of the chain has returned: the event ID and duration, plus
+// either the resulting composite ID/classification/score or the error.
+// Nothing is logged on entry. This replaces ad-hoc
+// per-call logging inside the scoring code.
+func Logging(logger logging.Logger) engine.Interceptor {
+	if logger == nil {
+		logger = logging.Noop()
+	}
+	return func(ctx context.Context, event *engine.DetectionEvent, next engine.Handler) (*engine.AggregatedDetection, error) {
+		start := time.Now()
+		detection, err := next(ctx, event)
+		duration := time.Since(start)
+
+		if err != nil {
+			logger.Error("detection event processing failed", "event_id", event.EventID, "host_id", event.HostID, "duration_ms", duration.Milliseconds(), "error", err)
+			return detection, err
+		}
+
+		logging.WithDetection(logger, detection.CompositeID, detection.HostIDHash, detection.Classification, detection.Score).
+			Info("processed detection event", "event_id", event.EventID, "duration_ms", duration.Milliseconds())
+		return detection, nil
+	}
+}
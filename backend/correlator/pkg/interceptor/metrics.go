@@ -0,0 +1,31 @@
+package interceptor
+
+import (
+	"context"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/metrics"
+)
+
+// Metrics returns an Interceptor that records DetectionProcessedTotal,
+// CorrelationScoreDistribution, ClassificationDistributionTotal,
+// RegistryMatchedTotal, and ErrorsTotal for every event processed,
+// replacing the equivalent bookkeeping callers previously had to do
+// themselves around ProcessEvent.
+func Metrics() engine.Interceptor {
+	return func(ctx context.Context, event *engine.DetectionEvent, next engine.Handler) (*engine.AggregatedDetection, error) {
+		detection, err := next(ctx, event)
+		if err != nil {
+			metrics.ErrorsTotal.WithLabelValues("process_event").Inc()
+			return detection, err
+		}
+
+		metrics.DetectionProcessedTotal.WithLabelValues(event.DetectionType).Inc()
+		metrics.CorrelationScoreDistribution.Observe(float64(detection.Score))
+		metrics.ClassificationDistributionTotal.WithLabelValues(detection.Classification).Inc()
+		if detection.RegistryMatched {
+			metrics.RegistryMatchedTotal.Inc()
+		}
+		return detection, nil
+	}
+}
@@ -0,0 +1,53 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+	"golang.org/x/time/rate"
+)
+
+// ErrRateLimited is returned when a tenant has exceeded its configured
+// event rate.
+var ErrRateLimited = fmt.Errorf("rate limit exceeded")
+
+// TenantKeyFunc extracts the tenant a DetectionEvent belongs to, e.g. a
+// customer ID embedded in event.Evidence. Defaults to event.HostID when
+// nil is passed to RateLimit, so single-tenant deployments get one limiter
+// per host without configuration.
+type TenantKeyFunc func(event *engine.DetectionEvent) string
+
+// RateLimit returns an Interceptor that caps each tenant (see keyFunc) to
+// eventsPerSecond, with burst additional events absorbed before throttling
+// kicks in. Throttled events are rejected with ErrRateLimited rather than
+// blocking, since Correlator.ProcessEvent is called from the consumer's
+// per-message hot path.
+func RateLimit(eventsPerSecond float64, burst int, keyFunc TenantKeyFunc) engine.Interceptor {
+	if keyFunc == nil {
+		keyFunc = func(event *engine.DetectionEvent) string { return event.HostID }
+	}
+
+	var mu sync.Mutex
+	limiters := make(map[string]*rate.Limiter)
+
+	limiterFor := func(tenant string) *rate.Limiter {
+		mu.Lock()
+		defer mu.Unlock()
+		limiter, ok := limiters[tenant]
+		if !ok {
+			limiter = rate.NewLimiter(rate.Limit(eventsPerSecond), burst)
+			limiters[tenant] = limiter
+		}
+		return limiter
+	}
+
+	return func(ctx context.Context, event *engine.DetectionEvent, next engine.Handler) (*engine.AggregatedDetection, error) {
+		tenant := keyFunc(event)
+		if !limiterFor(tenant).Allow() {
+			return nil, fmt.Errorf("%w: tenant %q", ErrRateLimited, tenant)
+		}
+		return next(ctx, event)
+	}
+}
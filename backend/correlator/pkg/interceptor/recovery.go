@@ -0,0 +1,51 @@
+// Package interceptor provides the built-in engine.Interceptors: panic
+// recovery, structured logging, per-tenant rate limiting, tracing, and
+// metrics. Register whichever are wanted with Correlator.Use, in the order
+// they should run (outermost first) — Recovery should normally be first so
+// it can catch a panic from every interceptor behind it.
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+)
+
+// PanicError is what Recovery returns when next panics. A panic means
+// processEvent never returned an AggregatedDetection to attach the stack
+// to, so it travels on the error instead; callers that persist failures
+// (e.g. to ClickHouse's errors table) can type-assert for it and copy
+// Stack into Metadata["panic"] themselves.
+type PanicError struct {
+	EventID string
+	Cause   interface{}
+	Stack   string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic processing detection event %s: %v", e.EventID, e.Cause)
+}
+
+// Recovery returns an Interceptor that converts a panic anywhere further
+// down the chain (including the correlator's own scoring logic) into a
+// *PanicError, so one malformed evidence map can't crash the whole
+// correlator loop. Always logged at error level with the stack trace.
+func Recovery(logger logging.Logger) engine.Interceptor {
+	if logger == nil {
+		logger = logging.Noop()
+	}
+	return func(ctx context.Context, event *engine.DetectionEvent, next engine.Handler) (detection *engine.AggregatedDetection, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				stack := string(debug.Stack())
+				logger.Error("recovered from panic processing detection event", "event_id", event.EventID, "panic", r, "stack", stack)
+				detection = nil
+				err = &PanicError{EventID: event.EventID, Cause: r, Stack: stack}
+			}
+		}()
+		return next(ctx, event)
+	}
+}
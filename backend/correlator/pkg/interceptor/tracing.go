@@ -0,0 +1,42 @@
+package interceptor
+
+import (
+	"context"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing returns an Interceptor that starts a span around the whole
+// ProcessEvent call. Correlator's own processEvent and recalculateDetection
+// open child spans (composite-ID generation, registry lookup, scoring)
+// under engine.TracerName, so they nest under the span this interceptor
+// starts as long as it passes the ctx it receives down the chain. With no
+// SDK configured, otel's default no-op tracer makes all of this free.
+func Tracing() engine.Interceptor {
+	tracer := otel.Tracer(engine.TracerName)
+	return func(ctx context.Context, event *engine.DetectionEvent, next engine.Handler) (*engine.AggregatedDetection, error) {
+		ctx, span := tracer.Start(ctx, "Correlator.ProcessEvent", trace.WithAttributes(
+			attribute.String("event_id", event.EventID),
+			attribute.String("detection_type", event.DetectionType),
+		))
+		defer span.End()
+
+		detection, err := next(ctx, event)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return detection, err
+		}
+
+		span.SetAttributes(
+			attribute.String("composite_id", detection.CompositeID),
+			attribute.String("classification", detection.Classification),
+			attribute.Int("score", detection.Score),
+		)
+		return detection, nil
+	}
+}
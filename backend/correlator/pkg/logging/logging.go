@@ -0,0 +1,102 @@
+// Package logging provides structured, level-aware logging for the correlator
+// service, replacing ad-hoc log.Printf/fmt.Printf calls with a single logger
+// that can be configured from GlobalConfig and threaded through constructors.
+// Reference: FR-014 (Prometheus/observability exposure), US5 (Transparency)
+package logging
+
+import (
+	"os"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// Logger is the structured logger used throughout the correlator. It is an
+// alias for hclog.Logger so callers can use With/Named/etc. directly.
+type Logger = hclog.Logger
+
+// Config controls how a Logger is constructed from GlobalConfig.
+type Config struct {
+	// Name identifies the subsystem emitting logs, e.g. "correlator", "clickhouse".
+	Name string
+
+	// Level is GlobalConfig.LogLevel: trace, debug, info, warn, error.
+	Level string
+
+	// Format is GlobalConfig.LogFormat: "text" or "json".
+	Format string
+
+	// DebugSampleRate, if > 1, emits only 1 in N debug-level records so
+	// high-volume event processing doesn't drown stdout/SIEM ingestion.
+	// 0 or 1 disables sampling (every debug record is emitted).
+	DebugSampleRate uint64
+}
+
+// New creates a Logger honoring the supplied Config. The returned Logger
+// injects no fields by default; callers should use With(...) to attach
+// correlation fields (compositeID, hostIDHash, classification, score,
+// evidenceType) for a given detection before logging.
+func New(cfg Config) Logger {
+	base := hclog.New(&hclog.LoggerOptions{
+		Name:       cfg.Name,
+		Level:      hclog.LevelFromString(cfg.Level),
+		JSONFormat: cfg.Format == "json",
+		Output:     os.Stderr,
+	})
+
+	if cfg.DebugSampleRate <= 1 {
+		return base
+	}
+	return &sampledLogger{Logger: base, every: cfg.DebugSampleRate}
+}
+
+// sampledLogger wraps a Logger and only forwards 1-in-N Debug/Trace calls,
+// leaving Info/Warn/Error untouched so operational signal is never dropped.
+type sampledLogger struct {
+	hclog.Logger
+	every   uint64
+	counter uint64
+}
+
+func (s *sampledLogger) Debug(msg string, args ...interface{}) {
+	if atomic.AddUint64(&s.counter, 1)%s.every == 0 {
+		s.Logger.Debug(msg, args...)
+	}
+}
+
+func (s *sampledLogger) Trace(msg string, args ...interface{}) {
+	if atomic.AddUint64(&s.counter, 1)%s.every == 0 {
+		s.Logger.Trace(msg, args...)
+	}
+}
+
+// With, Named and ResetNamed keep sampling on derived loggers, which the
+// embedded hclog.Logger's versions would otherwise return unwrapped.
+func (s *sampledLogger) With(args ...interface{}) hclog.Logger {
+	return &sampledLogger{Logger: s.Logger.With(args...), every: s.every}
+}
+
+func (s *sampledLogger) Named(name string) hclog.Logger {
+	return &sampledLogger{Logger: s.Logger.Named(name), every: s.every}
+}
+
+func (s *sampledLogger) ResetNamed(name string) hclog.Logger {
+	return &sampledLogger{Logger: s.Logger.ResetNamed(name), every: s.every}
+}
+
+// Noop returns a Logger that discards everything, for callers that accept an
+// optional Logger but were not given one.
+func Noop() Logger {
+	return hclog.NewNullLogger()
+}
+
+// WithDetection returns a Logger pre-populated with the correlation fields
+// SIEM consumers expect on every detection-related log line.
+func WithDetection(l Logger, compositeID, hostIDHash, classification string, score int) Logger {
+	return l.With(
+		"composite_id", compositeID,
+		"host_id_hash", hostIDHash,
+		"classification", classification,
+		"score", score,
+	)
+}
@@ -69,6 +69,52 @@ var (
 		},
 	)
 
+	// RegistryRetriesTotal counts registry.Client CheckMatch retries, by
+	// the retry attempt being made, so a registry outage shows up as
+	// growth at a specific attempt number rather than only in aggregate.
+	RegistryRetriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "correlator_registry_retries_total",
+			Help: "Total number of registry.Client CheckMatch retries, by attempt",
+		},
+		[]string{"attempt"},
+	)
+
+	// RegistryCircuitShortCircuitsTotal counts CheckMatch calls that were
+	// short-circuited by the registry client's circuit breaker instead of
+	// reaching the network.
+	RegistryCircuitShortCircuitsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "correlator_registry_circuit_short_circuits_total",
+			Help: "Total number of CheckMatch calls short-circuited by the open registry circuit breaker",
+		},
+	)
+
+	// RegistryCircuitBreakerState is the registry client's current
+	// circuit breaker state (0=closed, 1=open, 2=half_open; see
+	// registry.CircuitState).
+	RegistryCircuitBreakerState = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "correlator_registry_circuit_breaker_state",
+			Help: "Registry client circuit breaker state: 0=closed, 1=open, 2=half_open",
+		},
+	)
+
+	// RegistryCacheHitsTotal and RegistryCacheMissesTotal count the
+	// registry client's in-memory CheckMatch response cache.
+	RegistryCacheHitsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "correlator_registry_cache_hits_total",
+			Help: "Total number of CheckMatch calls served from the registry client's response cache",
+		},
+	)
+	RegistryCacheMissesTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "correlator_registry_cache_misses_total",
+			Help: "Total number of CheckMatch calls not served from the registry client's response cache",
+		},
+	)
+
 	// ErrorsTotal counts correlator errors
 	ErrorsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -77,4 +123,64 @@ var (
 		},
 		[]string{"error_type"},
 	)
+
+	// MessagesDLQTotal counts messages the consumer has dead-lettered
+	// instead of redelivering, by reason ("permanent" or
+	// "retries_exhausted"; see pkg/consumer.Consumer.deadLetter).
+	MessagesDLQTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "correlator_messages_dlq_total",
+			Help: "Total number of messages sent to a dead-letter subject, by reason",
+		},
+		[]string{"reason"},
+	)
+
+	// MessagesRetriedTotal counts transient-failure retries, labeled with
+	// the delivery attempt being retried, so a redelivery storm shows up as
+	// growth at a specific attempt number rather than only in aggregate.
+	MessagesRetriedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "correlator_messages_retried_total",
+			Help: "Total number of transient-failure message retries, by delivery attempt",
+		},
+		[]string{"attempt"},
+	)
+
+	// ConfigReloadFailedTotal counts rejected SIGHUP/fsnotify config reloads
+	ConfigReloadFailedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "mcpeeker_config_reload_failed_total",
+			Help: "Total number of config reloads rejected due to validation failure",
+		},
+	)
+
+	// RedactionsPerDetection tracks how many secrets/PII values
+	// pkg/clickhouse's belt-and-braces pkg/redact pass masked in a single
+	// evidence snippet, on top of whatever the originating probe already
+	// redacted. A non-zero count here means a probe shipped an unredacted
+	// secret (see FR-029).
+	RedactionsPerDetection = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "correlator_redactions_per_detection",
+			Help:    "Number of secret/PII redactions applied to a single evidence snippet by the ClickHouse writer's belt-and-braces pass",
+			Buckets: []float64{0, 1, 2, 3, 5, 8, 13},
+		},
+	)
+
+	// BuildInfo is a gauge set to 1, labeled with build metadata, so
+	// Prometheus join-queries can attribute other series to the binary
+	// version that produced them.
+	BuildInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mcpeeker_build_info",
+			Help: "Build metadata for the running correlator binary, value is always 1",
+		},
+		[]string{"version", "commit", "build_date", "go_version"},
+	)
 )
+
+// RecordBuildInfo sets the mcpeeker_build_info gauge for the current binary.
+// Call once at startup.
+func RecordBuildInfo(version, commit, buildDate, goVersion string) {
+	BuildInfo.WithLabelValues(version, commit, buildDate, goVersion).Set(1)
+}
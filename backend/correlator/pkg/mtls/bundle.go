@@ -0,0 +1,96 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CertBundle is a single named certificate/key pair loaded from a
+// TLSConfig.CertDir subdirectory, together with the hostnames (SANs and,
+// failing that, the CN) it should be selected for via SNI.
+type CertBundle struct {
+	Name        string
+	Certificate tls.Certificate
+	Hostnames   []string // e.g. "gateway.internal", "*.mcp.internal"
+}
+
+// loadCertBundles scans dir for immediate subdirectories each containing
+// a cert.pem/key.pem pair, parsing every leaf certificate's SANs (and CN,
+// for certificates predating mandatory SANs) into a CertBundle keyed by
+// the subdirectory name. A subdirectory without a cert.pem is silently
+// skipped rather than treated as an error, so unrelated directories under
+// CertDir don't break loading.
+func loadCertBundles(dir string) (map[string]*CertBundle, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read cert dir: %w", err)
+	}
+
+	bundles := make(map[string]*CertBundle)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		certPath := filepath.Join(dir, name, "cert.pem")
+		keyPath := filepath.Join(dir, name, "key.pem")
+
+		if _, err := os.Stat(certPath); err != nil {
+			continue
+		}
+
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("load cert bundle %q: %w", name, err)
+		}
+
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return nil, fmt.Errorf("parse cert bundle %q leaf certificate: %w", name, err)
+		}
+
+		hostnames := append([]string{}, leaf.DNSNames...)
+		if leaf.Subject.CommonName != "" {
+			hostnames = append(hostnames, leaf.Subject.CommonName)
+		}
+
+		bundles[name] = &CertBundle{
+			Name:        name,
+			Certificate: cert,
+			Hostnames:   hostnames,
+		}
+	}
+
+	return bundles, nil
+}
+
+// matchesSNI reports whether hostname (one of a CertBundle's Hostnames,
+// possibly a single-label wildcard like "*.mcp.internal") matches
+// serverName from a ClientHello. Matching is case-insensitive; wildcards
+// only cover exactly one label, per RFC 6125's simplest form — MCPeeker's
+// internal CAs don't issue anything more exotic.
+func matchesSNI(hostname, serverName string) bool {
+	hostname = strings.ToLower(hostname)
+	serverName = strings.ToLower(serverName)
+
+	if hostname == serverName {
+		return true
+	}
+
+	if !strings.HasPrefix(hostname, "*.") {
+		return false
+	}
+
+	suffix := hostname[1:] // ".mcp.internal"
+	if !strings.HasSuffix(serverName, suffix) {
+		return false
+	}
+
+	prefix := strings.TrimSuffix(serverName, suffix)
+	return prefix != "" && !strings.Contains(prefix, ".")
+}
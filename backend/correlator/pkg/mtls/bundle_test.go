@@ -0,0 +1,65 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchesSNIExactMatch(t *testing.T) {
+	assert.True(t, matchesSNI("gateway.internal", "gateway.internal"))
+	assert.True(t, matchesSNI("Gateway.Internal", "gateway.internal"))
+	assert.False(t, matchesSNI("gateway.internal", "other.internal"))
+}
+
+func TestMatchesSNIWildcardSingleLabel(t *testing.T) {
+	assert.True(t, matchesSNI("*.mcp.internal", "agent.mcp.internal"))
+	assert.False(t, matchesSNI("*.mcp.internal", "mcp.internal"))
+}
+
+func TestMatchesSNIWildcardDoesNotCoverMultipleLabels(t *testing.T) {
+	assert.False(t, matchesSNI("*.mcp.internal", "a.b.mcp.internal"))
+}
+
+func TestMatchesSNINonWildcardPrefixRejected(t *testing.T) {
+	assert.False(t, matchesSNI("gateway.internal", "evilgateway.internal"))
+}
+
+func TestNewClientMultiCertModeSelectsBundleBySNI(t *testing.T) {
+	dir := t.TempDir()
+	certDir := filepath.Join(dir, "certs")
+	require.NoError(t, os.MkdirAll(filepath.Join(certDir, "gateway"), 0700))
+	require.NoError(t, os.MkdirAll(filepath.Join(certDir, "agent"), 0700))
+
+	gwCert, gwKey := generateLeafCert(t, filepath.Join(certDir, "gateway"), "gateway", []string{"gateway.internal"})
+	require.NoError(t, os.Rename(gwCert, filepath.Join(certDir, "gateway", "cert.pem")))
+	require.NoError(t, os.Rename(gwKey, filepath.Join(certDir, "gateway", "key.pem")))
+
+	agentCert, agentKey := generateLeafCert(t, filepath.Join(certDir, "agent"), "agent", []string{"agent.internal"})
+	require.NoError(t, os.Rename(agentCert, filepath.Join(certDir, "agent", "cert.pem")))
+	require.NoError(t, os.Rename(agentKey, filepath.Join(certDir, "agent", "key.pem")))
+
+	caPath := writeSelfSignedCA(t, dir, "ca", time.Now().Add(365*24*time.Hour))
+
+	client, err := NewClient(&TLSConfig{CertDir: certDir, DefaultCertBundle: "gateway", CAFile: caPath})
+	require.NoError(t, err)
+
+	cert, err := client.getCertificate(&tls.ClientHelloInfo{ServerName: "agent.internal"})
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "agent", leaf.Subject.CommonName)
+
+	// Unmatched SNI falls back to the configured default bundle.
+	cert, err = client.getCertificate(&tls.ClientHelloInfo{ServerName: "unknown.internal"})
+	require.NoError(t, err)
+	leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "gateway", leaf.Subject.CommonName)
+}
@@ -0,0 +1,109 @@
+package mtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSelfSignedCA(t *testing.T, dir, name string, notAfter time.Time) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, name+".pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	require.NoError(t, os.WriteFile(path, pemBytes, 0600))
+	return path
+}
+
+func TestLoadCAPoolFromFilesParsesEachCert(t *testing.T) {
+	dir := t.TempDir()
+	path1 := writeSelfSignedCA(t, dir, "ca1", time.Now().Add(365*24*time.Hour))
+	path2 := writeSelfSignedCA(t, dir, "ca2", time.Now().Add(365*24*time.Hour))
+
+	pool, certs, err := loadCAPoolFromFiles([]string{path1, path2})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, pool)
+	assert.Len(t, certs, 2)
+	assert.Equal(t, path1, certs[0].file)
+	assert.Equal(t, path2, certs[1].file)
+}
+
+func TestLoadCAPoolSingleFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSelfSignedCA(t, dir, "ca1", time.Now().Add(365*24*time.Hour))
+
+	pool, err := loadCAPool(path)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, pool)
+}
+
+func TestLoadCAPoolFromFilesMissingFile(t *testing.T) {
+	_, _, err := loadCAPoolFromFiles([]string{"/nonexistent/ca.pem"})
+	assert.Error(t, err)
+}
+
+func TestCAFilePathsDedupesAndPreservesOrder(t *testing.T) {
+	cfg := &TLSConfig{CAFile: "a.pem", CAFiles: []string{"b.pem", "a.pem", "c.pem"}}
+
+	paths := cfg.caFilePaths()
+
+	assert.Equal(t, []string{"a.pem", "b.pem", "c.pem"}, paths)
+}
+
+func TestCAFilePathsSkipsEmpty(t *testing.T) {
+	cfg := &TLSConfig{CAFiles: []string{"", "b.pem"}}
+
+	paths := cfg.caFilePaths()
+
+	assert.Equal(t, []string{"b.pem"}, paths)
+}
+
+func TestRotateCAPoolReloadsPool(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := generateLeafCert(t, dir, "leaf", []string{"leaf.internal"})
+	caPath := writeSelfSignedCA(t, dir, "ca1", time.Now().Add(365*24*time.Hour))
+
+	client, err := NewClient(&TLSConfig{CertFile: certPath, KeyFile: keyPath, CAFile: caPath})
+	require.NoError(t, err)
+
+	firstPool := client.caPool.Load()
+	require.NotNil(t, firstPool)
+
+	// Replace the CA file's contents with a different CA and rotate.
+	secondCAPath := writeSelfSignedCA(t, dir, "ca2", time.Now().Add(365*24*time.Hour))
+	data, err := os.ReadFile(secondCAPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(caPath, data, 0600))
+
+	err = client.RotateCAPool()
+	assert.NoError(t, err)
+	assert.NotSame(t, firstPool, client.caPool.Load())
+}
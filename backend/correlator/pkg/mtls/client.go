@@ -3,14 +3,28 @@
 package mtls
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"os"
-	"sync"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+)
+
+// Defaults for TLSConfig's debounce/backoff knobs, applied whenever the
+// corresponding field is left zero-valued.
+const (
+	defaultReloadInterval   = 5 * time.Minute
+	defaultDebounceInterval = 500 * time.Millisecond
+	defaultRetryBackoffMin  = 1 * time.Second
+	defaultRetryBackoffMax  = 30 * time.Second
 )
 
 // TLSConfig holds mTLS configuration
@@ -19,32 +33,260 @@ type TLSConfig struct {
 	KeyFile  string // Path to client private key
 	CAFile   string // Path to CA certificate
 
-	// Auto-reload settings
+	// CertDir, if set, switches the client into multi-certificate mode:
+	// every immediate subdirectory of CertDir containing a cert.pem/
+	// key.pem pair becomes a named CertBundle, and the persistent
+	// tls.Config picks the right one per-connection via GetCertificate,
+	// matched against the incoming ClientHello's SNI (see bundle.go).
+	// CertFile/KeyFile are ignored when CertDir is set.
+	CertDir string
+	// DefaultCertBundle names the CertDir subdirectory to serve a
+	// connection whose SNI is absent or matches no bundle's hostnames.
+	// Required when CertDir is set.
+	DefaultCertBundle string
+
+	// Auto-reload settings. ReloadInterval is the periodic fallback check
+	// in case fsnotify missed an event entirely; defaults to 5m.
 	EnableAutoReload bool
 	ReloadInterval   time.Duration
+
+	// DebounceInterval coalesces a burst of fsnotify events (an atomic
+	// write-temp-then-rename rotation fires several) into a single
+	// reload: a reload is scheduled debounceInterval after the most
+	// recent event, and each new event pushes it back out. Defaults to
+	// 500ms.
+	DebounceInterval time.Duration
+
+	// RetryBackoffMin and RetryBackoffMax bound the exponential backoff
+	// applied to reload attempts that fail (e.g. because they raced an
+	// in-progress file write). Default to 1s and 30s.
+	RetryBackoffMin time.Duration
+	RetryBackoffMax time.Duration
+
+	// OnReload, if set, is called after every reload attempt (including
+	// retries) so callers can emit metrics or alerts instead of relying
+	// on log lines.
+	OnReload func(ReloadEvent)
+
+	// Source, if set, supplies certificate/CA material from a pluggable
+	// CertificateSource (e.g. NewSpiffeSource) instead of CertFile/
+	// KeyFile/CAFile or CertDir, both of which are ignored when Source is
+	// set. A source-backed identity is single-certificate only — SNI
+	// bundle serving (CertDir) has no equivalent here.
+	Source CertificateSource
+
+	// TrustDomain and AllowedSPIFFEIDs, if either is set, additionally
+	// constrain verifyPeerCertificate to require a spiffe:// URI SAN on
+	// the peer's leaf certificate. AllowedSPIFFEIDs, if non-empty, must
+	// contain the peer's exact SPIFFE ID; otherwise any ID under
+	// TrustDomain is accepted. Deployments not using SPIFFE identities
+	// leave both empty.
+	TrustDomain      string
+	AllowedSPIFFEIDs []string
+
+	// CAFiles lists additional root CA bundle files to trust alongside
+	// CAFile. The CA pool is always rebuilt as the union of CAFile (if
+	// set) and every file in CAFiles, so a rollover window — trusting
+	// both the outgoing and incoming root — is just listing both; see
+	// RotateCAPool and Client's dedicated CA-file watcher, which reload
+	// the CA pool independently of any leaf certificate. Ignored when
+	// Source is set — a CertificateSource supplies its own trust bundle.
+	CAFiles []string
+
+	// OnCAWarning, if set, is called once per CA certificate in the pool
+	// that is within 7 days of NotAfter, on every CA pool (re)load.
+	OnCAWarning func(CAWarning)
+
+	// Logger receives Client's structured log output. A nil Logger falls
+	// back to a discarding one, matching registry.Config's Logger
+	// convention.
+	Logger logging.Logger
+
+	// ExpiryNotifier, if set, is called for any certificate — leaf,
+	// bundle, or CA — found within 7 days of NotAfter, whether that's
+	// discovered by an explicit ValidateCertificate call, the daily
+	// background expiry check Client runs on its own, or a CA pool
+	// (re)load. Prefer this over OnCAWarning for alerting that shouldn't
+	// care which kind of certificate is expiring.
+	ExpiryNotifier func(cert *x509.Certificate, daysLeft int)
+}
+
+// CAWarning reports a CA certificate in the pool nearing expiry, as
+// surfaced by TLSConfig.OnCAWarning.
+type CAWarning struct {
+	File     string
+	Subject  string
+	NotAfter time.Time
+	DaysLeft int
+}
+
+// caFilePaths returns CAFile and CAFiles combined into a single
+// deduplicated list, in that order.
+func (cfg *TLSConfig) caFilePaths() []string {
+	var paths []string
+	seen := make(map[string]bool)
+	add := func(p string) {
+		if p == "" || seen[p] {
+			return
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	add(cfg.CAFile)
+	for _, p := range cfg.CAFiles {
+		add(p)
+	}
+	return paths
+}
+
+// ReloadEvent describes the outcome of one certificate reload attempt,
+// passed to TLSConfig.OnReload.
+type ReloadEvent struct {
+	Success bool
+	Err     error
+	// Attempt is 1 for a reload triggered directly by a debounced
+	// filesystem change or the periodic ticker, and >1 for each
+	// subsequent backoff retry after a failure.
+	Attempt int
+	// Duration is how long this attempt took, also recorded as
+	// mtls_cert_reload_duration_seconds.
+	Duration time.Duration
+}
+
+// reloadInterval returns ReloadInterval, defaulting to 5m when left
+// zero-valued — time.NewTicker panics on a non-positive duration, so this
+// must never return <= 0.
+func (cfg *TLSConfig) reloadInterval() time.Duration {
+	if cfg.ReloadInterval > 0 {
+		return cfg.ReloadInterval
+	}
+	return defaultReloadInterval
+}
+
+func (cfg *TLSConfig) debounceInterval() time.Duration {
+	if cfg.DebounceInterval > 0 {
+		return cfg.DebounceInterval
+	}
+	return defaultDebounceInterval
+}
+
+// retryBackoff returns how long to wait before reload attempt number
+// attempt+1, doubling from RetryBackoffMin up to a cap of RetryBackoffMax.
+func (cfg *TLSConfig) retryBackoff(attempt int) time.Duration {
+	min := cfg.RetryBackoffMin
+	if min <= 0 {
+		min = defaultRetryBackoffMin
+	}
+	max := cfg.RetryBackoffMax
+	if max <= 0 {
+		max = defaultRetryBackoffMax
+	}
+
+	backoff := min
+	for i := 1; i < attempt && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	return backoff
 }
 
-// Client represents an mTLS client with automatic certificate reloading
+// Client represents an mTLS client with automatic certificate reloading.
+//
+// The live certificate material is held in atomic pointers rather than
+// behind a mutex-guarded *tls.Config: tlsConfig is built once in NewClient
+// and never mutated again, so its GetCertificate/GetClientCertificate/
+// VerifyPeerCertificate callbacks can dereference the atomics below on
+// every handshake without synchronization; loadTLSConfig's reloads take
+// effect via Store(), not a field write on tlsConfig.
 type Client struct {
 	config    *TLSConfig
 	tlsConfig *tls.Config
-	mu        sync.RWMutex
+	logger    logging.Logger
 	watcher   *fsnotify.Watcher
 	stopChan  chan struct{}
+
+	// watchedFiles, in single-certificate mode, restricts reload triggers
+	// to events on these exact paths, since startAutoReload watches their
+	// parent directory (to catch Create/Rename inode swaps) rather than
+	// the files themselves — unfiltered, that would also reload on every
+	// unrelated file change in the same directory. Empty in
+	// multi-certificate mode, where any event under CertDir is relevant.
+	watchedFiles map[string]bool
+
+	// caWatchedFiles mirrors watchedFiles but for CAFile/CAFiles, watched
+	// independently of watchedFiles so a root CA rollover doesn't have to
+	// wait on (or trigger) a leaf certificate reload, and vice versa.
+	caWatchedFiles map[string]bool
+
+	// cert is the active certificate in single-certificate mode (nil in
+	// multi-certificate mode).
+	cert atomic.Pointer[tls.Certificate]
+
+	// bundles and defaultBundle are the active certificate set in
+	// multi-certificate mode (nil in single-certificate mode). A reader
+	// that observes one updated a moment before the other only risks
+	// briefly treating a changed bundle set as not containing a bundle
+	// that's actually already there (falling through to defaultBundle,
+	// itself either the new or still-previous value) — never a torn read
+	// within a single CertBundle.
+	bundles       atomic.Pointer[map[string]*CertBundle]
+	defaultBundle atomic.Pointer[CertBundle]
+
+	// caPool backs verifyPeerCertificate's manual chain verification.
+	caPool atomic.Pointer[x509.CertPool]
+
+	// caCerts holds the parsed CA certificates currently in caPool,
+	// purely so RotateCAPool can run expiry checks against them.
+	caCerts atomic.Pointer[[]caCertificate]
 }
 
-// NewClient creates a new mTLS client
+// caCertificate pairs a parsed CA certificate with the file it was loaded
+// from, for CAWarning.File.
+type caCertificate struct {
+	file string
+	cert *x509.Certificate
+}
+
+// NewClient creates a new mTLS client. A nil config.Logger falls back to a
+// discarding logger so existing callers are not forced to supply one.
 func NewClient(config *TLSConfig) (*Client, error) {
+	logger := config.Logger
+	if logger == nil {
+		logger = logging.Noop()
+	}
+
 	client := &Client{
 		config:   config,
+		logger:   logger,
 		stopChan: make(chan struct{}),
 	}
 
-	// Load initial TLS config
+	// Load initial certificate material into the atomics above.
 	if err := client.loadTLSConfig(); err != nil {
 		return nil, fmt.Errorf("failed to load TLS config: %w", err)
 	}
 
+	// Built exactly once: every later reload only Store()s new atomics,
+	// never touches this struct, so it's safe to hand the same *tls.Config
+	// to every connection without cloning or locking.
+	client.tlsConfig = &tls.Config{
+		MinVersion: tls.VersionTLS13,
+		ClientAuth: tls.RequireAnyClientCert,
+		// Go's built-in RootCAs/ClientCAs verification reads those fields
+		// directly off this struct on every handshake, so hot-swapping
+		// the CA pool would mean mutating (and racing) tlsConfig itself.
+		// Disabling it here (InsecureSkipVerify for the client role,
+		// RequireAnyClientCert instead of RequireAndVerifyClientCert for
+		// the server role) and doing the real verification in
+		// verifyPeerCertificate against the atomic caPool avoids that.
+		InsecureSkipVerify:    true,
+		GetCertificate:        client.getCertificate,
+		GetClientCertificate:  client.getClientCertificate,
+		VerifyPeerCertificate: client.verifyPeerCertificate,
+	}
+
 	// Start auto-reload if enabled
 	if config.EnableAutoReload {
 		if err := client.startAutoReload(); err != nil {
@@ -52,71 +294,382 @@ func NewClient(config *TLSConfig) (*Client, error) {
 		}
 	}
 
+	// Re-checks expiry once a day regardless of reload activity, so a
+	// certificate that's simply aging towards its NotAfter — no file
+	// change, no source push — still surfaces an ExpiryNotifier/OnCAWarning
+	// call well before it lapses.
+	go client.watchExpiry()
+
 	return client, nil
 }
 
-// GetTLSConfig returns the current TLS configuration (thread-safe)
+// GetTLSConfig returns the client's persistent TLS configuration. It is
+// the same *tls.Config for the lifetime of Client — safe to share across
+// every connection — because all certificate/CA updates happen via the
+// atomic pointers its callbacks dereference, not by mutating this struct.
 func (c *Client) GetTLSConfig() *tls.Config {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return c.tlsConfig.Clone()
+	return c.tlsConfig
 }
 
-// loadTLSConfig loads certificates and creates TLS configuration
+// loadTLSConfig loads certificate material into the atomic pointers the
+// persistent tls.Config's callbacks read from, dispatching to a pluggable
+// CertificateSource when config.Source is set, else to multi-certificate
+// mode when config.CertDir is set. RotateCAPool is then the canonical CA
+// pool loader, superseding whatever CA pool the dispatched loader set.
 func (c *Client) loadTLSConfig() error {
-	// Load client certificate and private key
-	cert, err := tls.LoadX509KeyPair(c.config.CertFile, c.config.KeyFile)
+	switch {
+	case c.config.Source != nil:
+		return c.fetchFromSource(c.config.Source)
+	case c.config.CertDir != "":
+		if err := c.loadMultiCertTLSConfig(); err != nil {
+			return err
+		}
+		return c.RotateCAPool()
+	default:
+		if err := c.loadSingleCertTLSConfig(); err != nil {
+			return err
+		}
+		return c.RotateCAPool()
+	}
+}
+
+// loadSingleCertTLSConfig loads the single CertFile/KeyFile/CAFile this
+// client was configured with, via the default file-backed
+// CertificateSource.
+func (c *Client) loadSingleCertTLSConfig() error {
+	return c.fetchFromSource(newFileSource(c.config.CertFile, c.config.KeyFile, c.config.CAFile))
+}
+
+// fetchFromSource loads certificate/CA material from src and stores it
+// into the single-certificate atomics, used by both loadSingleCertTLSConfig
+// (via fileSource) and config.Source.
+func (c *Client) fetchFromSource(src CertificateSource) error {
+	cert, pool, err := src.Fetch(context.Background())
+	if err != nil {
+		return err
+	}
+
+	c.cert.Store(&cert)
+	c.caPool.Store(pool)
+	return nil
+}
+
+// loadMultiCertTLSConfig scans config.CertDir into a fresh set of
+// CertBundles and stores them (and the resolved default) atomically.
+func (c *Client) loadMultiCertTLSConfig() error {
+	bundles, err := loadCertBundles(c.config.CertDir)
 	if err != nil {
-		return fmt.Errorf("failed to load client certificate: %w", err)
+		return err
+	}
+
+	defaultBundle, ok := bundles[c.config.DefaultCertBundle]
+	if !ok {
+		return fmt.Errorf("default cert bundle %q not found under %s", c.config.DefaultCertBundle, c.config.CertDir)
 	}
 
-	// Load CA certificate
-	caCert, err := os.ReadFile(c.config.CAFile)
+	caCertPool, err := loadCAPool(c.config.CAFile)
 	if err != nil {
-		return fmt.Errorf("failed to read CA certificate: %w", err)
+		return err
+	}
+
+	c.bundles.Store(&bundles)
+	c.defaultBundle.Store(defaultBundle)
+	c.caPool.Store(caCertPool)
+	return nil
+}
+
+// getCertificate implements tls.Config.GetCertificate. In multi-
+// certificate mode it selects the CertBundle whose Hostnames match the
+// ClientHello's SNI (wildcard-aware, via matchesSNI), falling back to
+// DefaultCertBundle — never an arbitrary bundle — when SNI is absent or
+// matches nothing. In single-certificate mode it always returns the
+// active cert.
+func (c *Client) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if bundles := c.bundles.Load(); bundles != nil {
+		if hello.ServerName != "" {
+			for _, bundle := range *bundles {
+				for _, hostname := range bundle.Hostnames {
+					if matchesSNI(hostname, hello.ServerName) {
+						return &bundle.Certificate, nil
+					}
+				}
+			}
+		}
+		if defaultBundle := c.defaultBundle.Load(); defaultBundle != nil {
+			return &defaultBundle.Certificate, nil
+		}
+		return nil, fmt.Errorf("no certificate bundles loaded")
+	}
+
+	cert := c.cert.Load()
+	if cert == nil {
+		return nil, fmt.Errorf("no certificate loaded")
+	}
+	return cert, nil
+}
+
+// getClientCertificate implements tls.Config.GetClientCertificate, used
+// when this Client dials out as a TLS client. There's no SNI to match
+// here — the peer's CertificateRequestInfo describes what it will
+// accept, not which of our identities to present — so this always
+// returns the default identity: DefaultCertBundle in multi-certificate
+// mode, or the single active cert otherwise.
+func (c *Client) getClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	if defaultBundle := c.defaultBundle.Load(); defaultBundle != nil {
+		return &defaultBundle.Certificate, nil
+	}
+
+	cert := c.cert.Load()
+	if cert == nil {
+		return nil, fmt.Errorf("no certificate loaded")
+	}
+	return cert, nil
+}
+
+// verifyPeerCertificate manually verifies the peer's certificate chain
+// against the atomically-swappable CA pool, standing in for tls.Config's
+// built-in RootCAs/ClientCAs verification (disabled in NewClient via
+// InsecureSkipVerify/RequireAnyClientCert) so the pool can be hot-reloaded
+// without mutating the persistent tls.Config.
+func (c *Client) verifyPeerCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("no peer certificate presented")
+	}
+
+	pool := c.caPool.Load()
+	if pool == nil {
+		return fmt.Errorf("no CA pool loaded")
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("parse peer certificate: %w", err)
+		}
+		certs[i] = cert
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}); err != nil {
+		return fmt.Errorf("verify peer certificate: %w", err)
 	}
 
-	caCertPool := x509.NewCertPool()
-	if !caCertPool.AppendCertsFromPEM(caCert) {
-		return fmt.Errorf("failed to parse CA certificate")
+	return c.verifySPIFFEID(certs[0])
+}
+
+// verifySPIFFEID enforces TLSConfig.TrustDomain/AllowedSPIFFEIDs against
+// the peer leaf certificate's spiffe:// URI SAN. A no-op when neither is
+// configured.
+func (c *Client) verifySPIFFEID(leaf *x509.Certificate) error {
+	if c.config.TrustDomain == "" && len(c.config.AllowedSPIFFEIDs) == 0 {
+		return nil
 	}
 
-	// Create TLS configuration
-	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		RootCAs:      caCertPool,
-		ClientCAs:    caCertPool,
-		MinVersion:   tls.VersionTLS13, // Enforce TLS 1.3
-		ClientAuth:   tls.RequireAndVerifyClientCert,
+	id, err := spiffeIDFromCertificate(leaf)
+	if err != nil {
+		return fmt.Errorf("peer certificate has no SPIFFE ID: %w", err)
 	}
 
-	// Update client's TLS config (thread-safe)
-	c.mu.Lock()
-	c.tlsConfig = tlsConfig
-	c.mu.Unlock()
+	if len(c.config.AllowedSPIFFEIDs) > 0 {
+		for _, allowed := range c.config.AllowedSPIFFEIDs {
+			if id == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("peer SPIFFE ID %q is not in the allowed list", id)
+	}
 
+	prefix := "spiffe://" + c.config.TrustDomain + "/"
+	if id != "spiffe://"+c.config.TrustDomain && !strings.HasPrefix(id, prefix) {
+		return fmt.Errorf("peer SPIFFE ID %q is not in trust domain %q", id, c.config.TrustDomain)
+	}
 	return nil
 }
 
-// startAutoReload starts watching certificate files for changes
+// spiffeIDFromCertificate returns the spiffe:// URI SAN on cert, per the
+// SPIFFE X.509-SVID spec (exactly one, in the leaf's URIs).
+func spiffeIDFromCertificate(cert *x509.Certificate) (string, error) {
+	for _, uri := range cert.URIs {
+		if uri.Scheme == "spiffe" {
+			return uri.String(), nil
+		}
+	}
+	return "", fmt.Errorf("no spiffe:// URI SAN present")
+}
+
+// loadCAPool reads and parses a PEM-encoded CA bundle from path.
+func loadCAPool(path string) (*x509.CertPool, error) {
+	pool, _, err := loadCAPoolFromFiles([]string{path})
+	return pool, err
+}
+
+// loadCAPoolFromFiles reads and parses every PEM-encoded CA bundle in
+// paths into a single pool, plus the individual parsed certificates (for
+// RotateCAPool's expiry checks).
+func loadCAPoolFromFiles(paths []string) (*x509.CertPool, []caCertificate, error) {
+	pool := x509.NewCertPool()
+	var certs []caCertificate
+
+	for _, path := range paths {
+		pemBytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CA file %s: %w", path, err)
+		}
+		if !pool.AppendCertsFromPEM(pemBytes) {
+			return nil, nil, fmt.Errorf("failed to parse CA file %s", path)
+		}
+
+		parsed, err := parseCertificatesPEM(pemBytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse CA file %s: %w", path, err)
+		}
+		for _, cert := range parsed {
+			certs = append(certs, caCertificate{file: path, cert: cert})
+		}
+	}
+
+	return pool, certs, nil
+}
+
+// parseCertificatesPEM parses every CERTIFICATE PEM block in data.
+func parseCertificatesPEM(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// RotateCAPool reloads the root CA pool from TLSConfig.CAFile/CAFiles —
+// their union — independent of any leaf certificate reload, so an
+// operator can introduce a new root (or retire an old one) without
+// touching leaf certs. Called automatically by loadTLSConfig and by the
+// dedicated CA-file watcher; exposed so callers can also trigger it
+// directly (e.g. from an admin endpoint). A no-op when config.Source is
+// set, since a CertificateSource supplies its own trust bundle.
+func (c *Client) RotateCAPool() error {
+	if c.config.Source != nil {
+		return nil
+	}
+
+	paths := c.config.caFilePaths()
+	if len(paths) == 0 {
+		return nil
+	}
+
+	pool, certs, err := loadCAPoolFromFiles(paths)
+	if err != nil {
+		return err
+	}
+
+	c.caPool.Store(pool)
+	c.caCerts.Store(&certs)
+	c.warnExpiringCAs(certs)
+	return nil
+}
+
+// warnExpiringCAs records expiry gauges for every CA certificate and, for
+// any within 7 days of NotAfter, logs and calls TLSConfig.OnCAWarning and
+// ExpiryNotifier.
+func (c *Client) warnExpiringCAs(certs []caCertificate) {
+	for _, ca := range certs {
+		recordCertTimestamps(ca.file, ca.cert)
+
+		warn, daysLeft := expiryWarning(ca.cert)
+		if !warn {
+			continue
+		}
+
+		subject := ca.cert.Subject.String()
+		c.logger.Warn("CA certificate expiring soon",
+			"file", ca.file, "subject", subject, "days_left", daysLeft)
+
+		if c.config.OnCAWarning != nil {
+			c.config.OnCAWarning(CAWarning{
+				File:     ca.file,
+				Subject:  subject,
+				NotAfter: ca.cert.NotAfter,
+				DaysLeft: daysLeft,
+			})
+		}
+		if c.config.ExpiryNotifier != nil {
+			c.config.ExpiryNotifier(ca.cert, daysLeft)
+		}
+	}
+}
+
+// startAutoReload starts watching for certificate changes: a
+// CertificateSource's Notify channel when config.Source is set, or
+// fsnotify on certificate files (or, in multi-certificate mode, CertDir)
+// otherwise.
 func (c *Client) startAutoReload() error {
+	if c.config.Source != nil {
+		go c.watchSource()
+		return nil
+	}
+
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return fmt.Errorf("failed to create file watcher: %w", err)
 	}
+	c.watcher = watcher
 
-	// Watch certificate files
-	if err := watcher.Add(c.config.CertFile); err != nil {
-		return fmt.Errorf("failed to watch cert file: %w", err)
-	}
-	if err := watcher.Add(c.config.KeyFile); err != nil {
-		return fmt.Errorf("failed to watch key file: %w", err)
-	}
-	if err := watcher.Add(c.config.CAFile); err != nil {
-		return fmt.Errorf("failed to watch CA file: %w", err)
+	if c.config.CertDir != "" {
+		if err := c.watchCertDir(); err != nil {
+			watcher.Close()
+			return err
+		}
+	} else {
+		// Watch each file's parent directory rather than the file itself:
+		// editors and k8s secret volume updates replace the file (a
+		// Create or Rename onto a new inode) rather than writing it in
+		// place, and fsnotify stops reporting events for a path once its
+		// original inode is gone.
+		c.watchedFiles = map[string]bool{
+			filepath.Clean(c.config.CertFile): true,
+			filepath.Clean(c.config.KeyFile):  true,
+		}
+		if err := c.watchParentDirs(c.config.CertFile, c.config.KeyFile); err != nil {
+			watcher.Close()
+			return err
+		}
 	}
 
-	c.watcher = watcher
+	// CA files are watched independently of the leaf certificate/bundle
+	// paths above, so a root rotation reloads only the CA pool
+	// (RotateCAPool) rather than the leaf cert too, and vice versa.
+	if paths := c.config.caFilePaths(); len(paths) > 0 {
+		caWatched := make(map[string]bool, len(paths))
+		for _, p := range paths {
+			caWatched[filepath.Clean(p)] = true
+		}
+		c.caWatchedFiles = caWatched
+		if err := c.watchParentDirs(paths...); err != nil {
+			watcher.Close()
+			return err
+		}
+	}
 
 	// Start watching in background
 	go c.watchCertificates()
@@ -124,38 +677,238 @@ func (c *Client) startAutoReload() error {
 	return nil
 }
 
-// watchCertificates watches for certificate file changes and reloads
+// watchParentDirs adds fsnotify watches for the deduplicated parent
+// directories of paths.
+func (c *Client) watchParentDirs(paths ...string) error {
+	dirs := make(map[string]bool)
+	for _, path := range paths {
+		dirs[filepath.Dir(path)] = true
+	}
+	for dir := range dirs {
+		if err := c.watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// watchCertDir (re)adds fsnotify watches for CertDir itself and every
+// bundle subdirectory currently loaded, so a new <name>/ directory
+// appearing is picked up on its next reload and an existing one being
+// removed stops being watched once fsnotify drops it. Safe to call
+// repeatedly — fsnotify.Add on an already-watched path is a no-op.
+func (c *Client) watchCertDir() error {
+	if err := c.watcher.Add(c.config.CertDir); err != nil {
+		return fmt.Errorf("failed to watch cert dir: %w", err)
+	}
+
+	bundles := c.bundles.Load()
+	if bundles == nil {
+		return nil
+	}
+	for name := range *bundles {
+		if err := c.watcher.Add(filepath.Join(c.config.CertDir, name)); err != nil {
+			return fmt.Errorf("failed to watch cert bundle dir %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// isReloadOp reports whether op is one fsnotify reports for a rotation:
+// a write in place, or a Create/Remove/Rename from an inode swap.
+func isReloadOp(op fsnotify.Op) bool {
+	return op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0
+}
+
+// isRelevantEvent reports whether a fsnotify event should trigger a leaf
+// certificate reload. In single-certificate mode, only the watched
+// files' own paths are relevant (their parent directory may contain
+// unrelated files, including CA files — see isCARelevantEvent); in
+// multi-certificate mode every event under CertDir/a bundle dir is.
+func (c *Client) isRelevantEvent(event fsnotify.Event) bool {
+	if !isReloadOp(event.Op) {
+		return false
+	}
+	path := filepath.Clean(event.Name)
+	if c.caWatchedFiles[path] {
+		return false
+	}
+	if len(c.watchedFiles) == 0 {
+		return true
+	}
+	return c.watchedFiles[path]
+}
+
+// isCARelevantEvent reports whether a fsnotify event should trigger
+// RotateCAPool.
+func (c *Client) isCARelevantEvent(event fsnotify.Event) bool {
+	if !isReloadOp(event.Op) {
+		return false
+	}
+	return c.caWatchedFiles[filepath.Clean(event.Name)]
+}
+
+// reload reloads certificate material and, in multi-certificate mode,
+// refreshes the watched bundle directories to match.
+func (c *Client) reload() error {
+	if err := c.loadTLSConfig(); err != nil {
+		return err
+	}
+	if c.config.CertDir != "" {
+		if err := c.watchCertDir(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyReload records a reload attempt's outcome and duration as
+// mtls_cert_reload_total/mtls_cert_reload_duration_seconds, then reports it
+// to TLSConfig.OnReload, if set.
+func (c *Client) notifyReload(event ReloadEvent) {
+	recordReload(event.Success, event.Duration)
+	if c.config.OnReload != nil {
+		c.config.OnReload(event)
+	}
+}
+
+// debouncedReload is one coalesced-reload pipeline: relevant events
+// (re)schedule timer debounceInterval out rather than reloading
+// immediately, and a failed reload is retried with capped exponential
+// backoff instead of being dropped until the next external trigger.
+// watchCertificates runs two independent instances — one for the leaf
+// certificate/bundle, one for the CA pool — so a root CA rotation
+// doesn't wait on, or trigger, a leaf certificate reload.
+type debouncedReload struct {
+	timer   *time.Timer
+	timerC  <-chan time.Time
+	attempt int
+}
+
+func (r *debouncedReload) schedule(d time.Duration) {
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	r.timer = time.NewTimer(d)
+	r.timerC = r.timer.C
+}
+
+func (r *debouncedReload) stop() {
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+}
+
+// watchCertificates watches for certificate and CA file changes and
+// reloads each independently; see debouncedReload.
 func (c *Client) watchCertificates() {
-	ticker := time.NewTicker(c.config.ReloadInterval)
+	ticker := time.NewTicker(c.config.reloadInterval())
 	defer ticker.Stop()
 
+	debounce := c.config.debounceInterval()
+	leaf := &debouncedReload{}
+	ca := &debouncedReload{}
+
 	for {
 		select {
 		case event, ok := <-c.watcher.Events:
 			if !ok {
+				leaf.stop()
+				ca.stop()
 				return
 			}
-
-			// Reload on write or create events
-			if event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create {
-				fmt.Printf("Certificate file changed: %s, reloading...\n", event.Name)
-				if err := c.loadTLSConfig(); err != nil {
-					fmt.Printf("Failed to reload TLS config: %v\n", err)
-				} else {
-					fmt.Println("TLS config reloaded successfully")
-				}
+			if c.isRelevantEvent(event) {
+				leaf.attempt = 0 // a fresh filesystem change restarts the retry chain
+				leaf.schedule(debounce)
+			}
+			if c.isCARelevantEvent(event) {
+				ca.attempt = 0
+				ca.schedule(debounce)
 			}
 
 		case err, ok := <-c.watcher.Errors:
 			if !ok {
+				leaf.stop()
+				ca.stop()
 				return
 			}
-			fmt.Printf("Certificate watcher error: %v\n", err)
+			certWatcherErrorsTotal.Inc()
+			c.logger.Warn("certificate watcher error", "error", err)
+
+		case <-leaf.timerC:
+			leaf.attempt++
+			start := time.Now()
+			err := c.reload()
+			duration := time.Since(start)
+			if err != nil {
+				backoff := c.config.retryBackoff(leaf.attempt)
+				c.logger.Warn("certificate reload failed, retrying",
+					"error", err, "attempt", leaf.attempt, "retry_in", backoff)
+				c.notifyReload(ReloadEvent{Success: false, Err: err, Attempt: leaf.attempt, Duration: duration})
+				leaf.schedule(backoff)
+			} else {
+				c.logger.Info("certificate reloaded", "attempt", leaf.attempt)
+				c.notifyReload(ReloadEvent{Success: true, Attempt: leaf.attempt, Duration: duration})
+				leaf.attempt = 0
+			}
+
+		case <-ca.timerC:
+			ca.attempt++
+			start := time.Now()
+			err := c.RotateCAPool()
+			duration := time.Since(start)
+			if err != nil {
+				backoff := c.config.retryBackoff(ca.attempt)
+				c.logger.Warn("CA pool rotation failed, retrying",
+					"error", err, "attempt", ca.attempt, "retry_in", backoff)
+				c.notifyReload(ReloadEvent{Success: false, Err: err, Attempt: ca.attempt, Duration: duration})
+				ca.schedule(backoff)
+			} else {
+				c.logger.Info("CA pool rotated", "attempt", ca.attempt)
+				c.notifyReload(ReloadEvent{Success: true, Attempt: ca.attempt, Duration: duration})
+				ca.attempt = 0
+			}
 
 		case <-ticker.C:
-			// Periodic reload check (in case fsnotify misses events)
-			if err := c.loadTLSConfig(); err != nil {
-				fmt.Printf("Failed to reload TLS config: %v\n", err)
+			// Periodic reload check, in case fsnotify missed an event
+			// entirely. Goes through the same debounce/retry pipelines,
+			// each starting a fresh attempt chain.
+			leaf.attempt = 0
+			leaf.schedule(0)
+			ca.attempt = 0
+			ca.schedule(0)
+
+		case <-c.stopChan:
+			leaf.stop()
+			ca.stop()
+			return
+		}
+	}
+}
+
+// watchSource rotates certificate material as soon as config.Source
+// pushes an update — no debounce or backoff: a CertificateSource only
+// delivers material it already considers valid (the Workload API, for
+// instance, validates a new SVID before handing it out), so there's
+// nothing here to coalesce or retry the way file-based fsnotify events
+// need.
+func (c *Client) watchSource() {
+	notify := c.config.Source.Notify()
+	for {
+		select {
+		case _, ok := <-notify:
+			if !ok {
+				return
+			}
+			start := time.Now()
+			err := c.loadTLSConfig()
+			duration := time.Since(start)
+			if err != nil {
+				c.logger.Warn("certificate source update failed", "error", err)
+				c.notifyReload(ReloadEvent{Success: false, Err: err, Duration: duration})
+			} else {
+				c.logger.Info("certificate rotated from source")
+				c.notifyReload(ReloadEvent{Success: true, Duration: duration})
 			}
 
 		case <-c.stopChan:
@@ -167,22 +920,52 @@ func (c *Client) watchCertificates() {
 // Close stops the auto-reload watcher and cleans up resources
 func (c *Client) Close() error {
 	close(c.stopChan)
+	var err error
 	if c.watcher != nil {
-		return c.watcher.Close()
+		err = c.watcher.Close()
 	}
-	return nil
+	if c.config.Source != nil {
+		if closeErr := c.config.Source.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
 }
 
-// ValidateCertificate checks if the certificate is valid and not expired
+// ValidateCertificate checks that the active certificate is valid and not
+// expired, and re-runs the CA pool's expiry check (warnExpiringCAs) so a CA
+// nearing NotAfter keeps surfacing OnCAWarning/ExpiryNotifier even when the
+// CA file itself never changes. In multi-certificate mode it checks every
+// loaded CertBundle, since any one of them could be selected for the next
+// connection. Called directly by callers that want an on-demand check, and
+// periodically by watchExpiry.
 func (c *Client) ValidateCertificate() error {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	if caCerts := c.caCerts.Load(); caCerts != nil {
+		c.warnExpiringCAs(*caCerts)
+	}
 
-	if len(c.tlsConfig.Certificates) == 0 {
+	if bundles := c.bundles.Load(); bundles != nil {
+		for name, bundle := range *bundles {
+			if err := c.validateCertificateExpiry("bundle:"+name, bundle.Certificate); err != nil {
+				return fmt.Errorf("cert bundle %q: %w", name, err)
+			}
+		}
+		return nil
+	}
+
+	cert := c.cert.Load()
+	if cert == nil {
 		return fmt.Errorf("no certificates loaded")
 	}
+	return c.validateCertificateExpiry("leaf", *cert)
+}
 
-	cert := c.tlsConfig.Certificates[0]
+// validateCertificateExpiry checks cert's validity window, recording its
+// expiry gauges and, when it's within 7 days of NotAfter, logging and
+// calling TLSConfig.ExpiryNotifier (but not failing). label identifies
+// cert for mtls_cert_expiry_timestamp_seconds/mtls_cert_not_before_timestamp_seconds
+// ("leaf" or "bundle:<name>").
+func (c *Client) validateCertificateExpiry(label string, cert tls.Certificate) error {
 	if len(cert.Certificate) == 0 {
 		return fmt.Errorf("invalid certificate")
 	}
@@ -193,6 +976,8 @@ func (c *Client) ValidateCertificate() error {
 		return fmt.Errorf("failed to parse certificate: %w", err)
 	}
 
+	recordCertTimestamps(label, x509Cert)
+
 	// Check expiration
 	now := time.Now()
 	if now.Before(x509Cert.NotBefore) {
@@ -202,12 +987,43 @@ func (c *Client) ValidateCertificate() error {
 		return fmt.Errorf("certificate expired on %v", x509Cert.NotAfter)
 	}
 
-	// Warn if expiring within 7 days
-	sevenDaysFromNow := now.Add(7 * 24 * time.Hour)
-	if sevenDaysFromNow.After(x509Cert.NotAfter) {
-		daysLeft := int(time.Until(x509Cert.NotAfter).Hours() / 24)
-		fmt.Printf("⚠️  WARNING: Certificate expires in %d days! Please renew.\n", daysLeft)
+	if warn, daysLeft := expiryWarning(x509Cert); warn {
+		c.logger.Warn("certificate expiring soon", "cert", label, "days_left", daysLeft)
+		if c.config.ExpiryNotifier != nil {
+			c.config.ExpiryNotifier(x509Cert, daysLeft)
+		}
 	}
 
 	return nil
 }
+
+// watchExpiry calls ValidateCertificate once a day so a certificate aging
+// towards its NotAfter with no reload activity at all — no file change, no
+// CertificateSource push — still surfaces an expiry warning. Runs for the
+// lifetime of Client, stopped by Close via stopChan.
+func (c *Client) watchExpiry() {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.ValidateCertificate(); err != nil {
+				c.logger.Warn("scheduled certificate validation failed", "error", err)
+			}
+		case <-c.stopChan:
+			return
+		}
+	}
+}
+
+// expiryWarning reports whether cert is within 7 days of NotAfter, and if
+// so how many days remain. Shared by validateCertificateExpiry (leaf
+// certs) and warnExpiringCAs (CA pool certs).
+func expiryWarning(cert *x509.Certificate) (warn bool, daysLeft int) {
+	sevenDaysFromNow := time.Now().Add(7 * 24 * time.Hour)
+	if !sevenDaysFromNow.After(cert.NotAfter) {
+		return false, 0
+	}
+	return true, int(time.Until(cert.NotAfter).Hours() / 24)
+}
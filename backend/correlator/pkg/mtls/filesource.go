@@ -0,0 +1,40 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// fileSource is the default CertificateSource, backing TLSConfig's plain
+// CertFile/KeyFile/CAFile single-certificate mode. It has no push signal
+// of its own — Client's existing fsnotify/ticker-driven
+// watchCertificates covers reload for it — so Notify returns nil.
+type fileSource struct {
+	certFile string
+	keyFile  string
+	caFile   string
+}
+
+func newFileSource(certFile, keyFile, caFile string) *fileSource {
+	return &fileSource{certFile: certFile, keyFile: keyFile, caFile: caFile}
+}
+
+func (s *fileSource) Fetch(_ context.Context) (tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	pool, err := loadCAPool(s.caFile)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+
+	return cert, pool, nil
+}
+
+func (s *fileSource) Notify() <-chan struct{} { return nil }
+
+func (s *fileSource) Close() error { return nil }
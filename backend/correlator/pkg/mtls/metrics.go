@@ -0,0 +1,74 @@
+package mtls
+
+import (
+	"crypto/x509"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// These are registered under an mtls_* namespace rather than pkg/metrics'
+// correlator_* (or engine's correlator_engine_*), since Client is reused
+// outside the correlator binary (e.g. by the registry service) and its
+// reload/expiry posture is worth paging on independently of either.
+var (
+	certReloadTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mtls_cert_reload_total",
+			Help: "Total certificate/CA pool reload attempts by mtls.Client, by result",
+		},
+		[]string{"result"}, // success, failure
+	)
+
+	certReloadDurationSeconds = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "mtls_cert_reload_duration_seconds",
+			Help:    "Duration of mtls.Client certificate/CA pool reload attempts",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 10), // 1ms to ~1s
+		},
+	)
+
+	certExpiryTimestampSeconds = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mtls_cert_expiry_timestamp_seconds",
+			Help: "NotAfter of a certificate mtls.Client is tracking, as a Unix timestamp, by file",
+		},
+		[]string{"file"},
+	)
+
+	certNotBeforeTimestampSeconds = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mtls_cert_not_before_timestamp_seconds",
+			Help: "NotBefore of a certificate mtls.Client is tracking, as a Unix timestamp, by file",
+		},
+		[]string{"file"},
+	)
+
+	certWatcherErrorsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "mtls_cert_watcher_errors_total",
+			Help: "Total fsnotify watcher errors observed by mtls.Client",
+		},
+	)
+)
+
+// recordReload records one reload attempt's outcome and duration. It is
+// called (via Client.notifyReload) for the watcher's leaf/CA debounced
+// reloads and their retries, and for a CertificateSource push; a direct
+// RotateCAPool call and NewClient's initial load are not recorded.
+func recordReload(success bool, duration time.Duration) {
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+	certReloadTotal.WithLabelValues(result).Inc()
+	certReloadDurationSeconds.Observe(duration.Seconds())
+}
+
+// recordCertTimestamps sets the expiry/not-before gauges for a certificate
+// tracked under the given file/bundle label.
+func recordCertTimestamps(file string, cert *x509.Certificate) {
+	certExpiryTimestampSeconds.WithLabelValues(file).Set(float64(cert.NotAfter.Unix()))
+	certNotBeforeTimestampSeconds.WithLabelValues(file).Set(float64(cert.NotBefore.Unix()))
+}
@@ -0,0 +1,43 @@
+package mtls
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryBackoffDoublesUpToMax(t *testing.T) {
+	cfg := &TLSConfig{RetryBackoffMin: time.Second, RetryBackoffMax: 8 * time.Second}
+
+	assert.Equal(t, time.Second, cfg.retryBackoff(1))
+	assert.Equal(t, 2*time.Second, cfg.retryBackoff(2))
+	assert.Equal(t, 4*time.Second, cfg.retryBackoff(3))
+	assert.Equal(t, 8*time.Second, cfg.retryBackoff(4))
+	assert.Equal(t, 8*time.Second, cfg.retryBackoff(10))
+}
+
+func TestRetryBackoffDefaultsWhenZeroValued(t *testing.T) {
+	cfg := &TLSConfig{}
+
+	assert.Equal(t, defaultRetryBackoffMin, cfg.retryBackoff(1))
+}
+
+func TestDebounceIntervalDefaultsWhenZeroValued(t *testing.T) {
+	cfg := &TLSConfig{}
+	assert.Equal(t, defaultDebounceInterval, cfg.debounceInterval())
+
+	cfg.DebounceInterval = 2 * time.Second
+	assert.Equal(t, 2*time.Second, cfg.debounceInterval())
+}
+
+func TestReloadIntervalDefaultsWhenZeroOrNegative(t *testing.T) {
+	cfg := &TLSConfig{}
+	assert.Equal(t, defaultReloadInterval, cfg.reloadInterval())
+
+	cfg.ReloadInterval = -time.Second
+	assert.Equal(t, defaultReloadInterval, cfg.reloadInterval())
+
+	cfg.ReloadInterval = time.Minute
+	assert.Equal(t, time.Minute, cfg.reloadInterval())
+}
@@ -0,0 +1,33 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+)
+
+// CertificateSource supplies the certificate and trusted CA pool Client
+// holds in its atomic cert/caPool fields, decoupling how identity
+// material is obtained — static files on disk, a SPIFFE Workload API
+// subscription, ... — from how Client serves or dials connections with
+// it. fileSource (the default, built from TLSConfig.CertFile/KeyFile/
+// CAFile) and spiffeSource are the two implementations; TLSConfig.Source
+// lets a caller supply its own.
+type CertificateSource interface {
+	// Fetch returns the current certificate and CA pool. Called once at
+	// startup and again every time Notify's channel fires; fileSource is
+	// also re-fetched on each fsnotify/ticker-driven reload.
+	Fetch(ctx context.Context) (tls.Certificate, *x509.CertPool, error)
+
+	// Notify returns a channel that receives a value whenever new
+	// certificate material is available, so Client can rotate
+	// immediately instead of polling or watching the filesystem. A
+	// source with no push signal of its own may return a nil channel —
+	// a receive on a nil channel blocks forever, which is the correct
+	// "never fires" behavior for select.
+	Notify() <-chan struct{}
+
+	// Close releases any resources (e.g. a Workload API connection) held
+	// by the source.
+	Close() error
+}
@@ -0,0 +1,164 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+)
+
+// SpiffeSourceConfig configures a spiffeSource.
+type SpiffeSourceConfig struct {
+	// SocketPath is the SPIFFE Workload API's unix domain socket, e.g.
+	// "unix:///run/spire/agent.sock". Required.
+	SocketPath string
+
+	Logger logging.Logger
+}
+
+// spiffeSource is a CertificateSource backed by a live streaming
+// subscription to a local SPIFFE Workload API (typically a SPIRE Agent).
+// Unlike fileSource, it never polls: the Workload API pushes a new
+// X.509-SVID as soon as one is issued or rotated, and Notify's channel
+// fires immediately on that push.
+type spiffeSource struct {
+	logger logging.Logger
+	cancel context.CancelFunc
+	notify chan struct{}
+
+	// current is written by OnX509ContextUpdate (the Workload API's
+	// callback goroutine) and read by Fetch (called from whatever
+	// goroutine is rotating certificates), so it's held behind an atomic
+	// rather than a plain field, matching Client's atomics-only approach
+	// to cross-goroutine certificate state.
+	current atomic.Pointer[workloadapi.X509Context]
+}
+
+// NewSpiffeSource connects to the Workload API at cfg.SocketPath and
+// begins streaming X.509-SVID updates in the background. The returned
+// source already has an initial SVID by the time this call returns.
+func NewSpiffeSource(ctx context.Context, cfg SpiffeSourceConfig) (*spiffeSource, error) {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = logging.Noop()
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	s := &spiffeSource{
+		logger: logger,
+		cancel: cancel,
+		notify: make(chan struct{}, 1),
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		err := workloadapi.WatchX509Context(watchCtx, &x509ContextWatcher{source: s, ready: ready}, workloadapi.WithAddr(cfg.SocketPath))
+		if err != nil && watchCtx.Err() == nil {
+			logger.Error("SPIFFE Workload API watch ended", "error", err)
+		}
+	}()
+
+	select {
+	case err := <-ready:
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to fetch initial X.509-SVID: %w", err)
+		}
+	case <-ctx.Done():
+		cancel()
+		return nil, ctx.Err()
+	}
+
+	return s, nil
+}
+
+// x509ContextWatcher adapts workloadapi.WatchX509Context's push callbacks
+// onto spiffeSource's current/notify fields, signaling the first
+// successful update (or the first error) on ready.
+type x509ContextWatcher struct {
+	source *spiffeSource
+	ready  chan error
+	once   bool
+}
+
+func (w *x509ContextWatcher) OnX509ContextUpdate(c *workloadapi.X509Context) {
+	w.source.current.Store(c)
+
+	select {
+	case w.source.notify <- struct{}{}:
+	default:
+	}
+
+	if !w.once {
+		w.once = true
+		w.ready <- nil
+	}
+}
+
+func (w *x509ContextWatcher) OnX509ContextWatchError(err error) {
+	w.source.logger.Warn("SPIFFE Workload API watch error", "error", err)
+	if !w.once {
+		w.once = true
+		w.ready <- err
+	}
+}
+
+// Fetch returns the most recently received SVID and trust bundle. It
+// never blocks on the network — the streaming subscription in
+// NewSpiffeSource keeps current up to date independently of calls here.
+func (s *spiffeSource) Fetch(_ context.Context) (tls.Certificate, *x509.CertPool, error) {
+	ctx := s.current.Load()
+	if ctx == nil || len(ctx.SVIDs) == 0 {
+		return tls.Certificate{}, nil, fmt.Errorf("no X.509-SVID received from Workload API yet")
+	}
+
+	svid := ctx.SVIDs[0]
+	cert, err := certificateFromSVID(svid)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+
+	pool := x509.NewCertPool()
+	for _, bundle := range ctx.Bundles.Bundles() {
+		for _, authority := range bundle.X509Authorities() {
+			pool.AddCert(authority)
+		}
+	}
+
+	return cert, pool, nil
+}
+
+// Notify fires once per X.509-SVID update pushed by the Workload API.
+func (s *spiffeSource) Notify() <-chan struct{} {
+	return s.notify
+}
+
+func (s *spiffeSource) Close() error {
+	s.cancel()
+	return nil
+}
+
+// certificateFromSVID converts an x509svid.SVID (a leaf cert plus its
+// chain and private key, as delivered by the Workload API) into the
+// tls.Certificate shape Client's atomics expect.
+func certificateFromSVID(svid *x509svid.SVID) (tls.Certificate, error) {
+	if len(svid.Certificates) == 0 {
+		return tls.Certificate{}, fmt.Errorf("SVID %s has no certificates", svid.ID)
+	}
+
+	raw := make([][]byte, len(svid.Certificates))
+	for i, cert := range svid.Certificates {
+		raw[i] = cert.Raw
+	}
+
+	return tls.Certificate{
+		Certificate: raw,
+		PrivateKey:  svid.PrivateKey,
+		Leaf:        svid.Certificates[0],
+	}, nil
+}
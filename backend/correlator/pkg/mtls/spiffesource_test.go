@@ -0,0 +1,51 @@
+package mtls
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificateFromSVIDConvertsChain(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := generateLeafCert(t, dir, "workload", []string{"workload.internal"})
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	require.NoError(t, err)
+
+	certs := make([]*x509.Certificate, len(cert.Certificate))
+	for i, der := range cert.Certificate {
+		parsed, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		certs[i] = parsed
+	}
+
+	id, err := spiffeid.FromString("spiffe://example.org/workload")
+	require.NoError(t, err)
+
+	svid := &x509svid.SVID{
+		ID:           id,
+		Certificates: certs,
+		PrivateKey:   cert.PrivateKey.(crypto.Signer),
+	}
+
+	tlsCert, err := certificateFromSVID(svid)
+
+	assert.NoError(t, err)
+	assert.Equal(t, cert.Certificate, tlsCert.Certificate)
+	assert.NotNil(t, tlsCert.Leaf)
+}
+
+func TestCertificateFromSVIDNoCertificates(t *testing.T) {
+	id, err := spiffeid.FromString("spiffe://example.org/workload")
+	require.NoError(t, err)
+
+	_, err = certificateFromSVID(&x509svid.SVID{ID: id})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+)
+
+// GenericProvider POSTs the raw detection event as JSON to an arbitrary URL.
+type GenericProvider struct {
+	url        string
+	httpClient *http.Client
+}
+
+func newGenericProvider(url string, timeout time.Duration) *GenericProvider {
+	return &GenericProvider{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send POSTs event's JSON encoding to url.
+func (p *GenericProvider) Send(ctx context.Context, event *engine.DetectionEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection event: %w", err)
+	}
+
+	resp, err := p.post(ctx, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", p.url, resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *GenericProvider) post(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	return resp, nil
+}
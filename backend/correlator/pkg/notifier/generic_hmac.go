@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+)
+
+// GenericHMACProvider POSTs the raw detection event as JSON to an arbitrary
+// URL, signing the body with an HMAC-SHA256 shared secret so the receiver
+// can verify the webhook originated from this correlator.
+type GenericHMACProvider struct {
+	url        string
+	secret     []byte
+	httpClient *http.Client
+}
+
+func newGenericHMACProvider(url, secret string, timeout time.Duration) *GenericHMACProvider {
+	return &GenericHMACProvider{
+		url:        url,
+		secret:     []byte(secret),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send POSTs event's JSON encoding to url with an X-Signature header.
+func (p *GenericHMACProvider) Send(ctx context.Context, event *engine.DetectionEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", "sha256="+p.sign(body))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", p.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// sign returns the hex-encoded HMAC-SHA256 of body using the shared secret.
+func (p *GenericHMACProvider) sign(body []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,204 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+)
+
+// Provider type identifiers accepted by ProviderConfig.Type.
+const (
+	ProviderTypeSlack       = "slack"
+	ProviderTypePagerDuty   = "pagerduty"
+	ProviderTypeGeneric     = "generic"
+	ProviderTypeGenericHMAC = "generic-hmac"
+)
+
+// Config holds notifier configuration
+type Config struct {
+	// Threshold is the default minimum score a detection event must reach
+	// to be dispatched. Providers may override it per-provider.
+	Threshold int
+	// DetectionTypes is the default allowlist of detection types to notify
+	// on, e.g. ["process", "network"]. An empty list allows all types.
+	// Providers may override it per-provider.
+	DetectionTypes []string
+	Providers      []ProviderConfig
+	Logger         logging.Logger
+}
+
+// ProviderConfig configures a single notification provider. Type selects
+// the implementation; the remaining fields are interpreted according to
+// Type and left empty otherwise.
+type ProviderConfig struct {
+	Type string // one of the ProviderType* constants
+	Name string // operator-chosen label, used only in logs
+
+	URL        string // generic, generic-hmac
+	Secret     string // generic-hmac: HMAC shared secret
+	WebhookURL string // slack
+	RoutingKey string // pagerduty
+
+	// Threshold and DetectionTypes override Config's defaults for this
+	// provider only. Zero/empty means "use the notifier-level default".
+	Threshold      int
+	DetectionTypes []string
+}
+
+// notifierProvider pairs a constructed Provider with its resolved filters.
+type notifierProvider struct {
+	name           string
+	provider       Provider
+	threshold      int
+	detectionTypes map[string]bool
+}
+
+// Notifier dispatches detection events to the providers configured in
+// Config whose filters match, with retry/backoff on delivery failure.
+type Notifier struct {
+	providers []notifierProvider
+	logger    logging.Logger
+}
+
+// New builds a Notifier from config. A nil Logger falls back to a
+// discarding logger so existing callers are not forced to supply one.
+func New(config *Config) (*Notifier, error) {
+	logger := config.Logger
+	if logger == nil {
+		logger = logging.Noop()
+	}
+
+	providers := make([]notifierProvider, 0, len(config.Providers))
+	for _, pc := range config.Providers {
+		provider, err := newProvider(pc)
+		if err != nil {
+			return nil, fmt.Errorf("provider %q: %w", pc.Name, err)
+		}
+
+		threshold := pc.Threshold
+		if threshold <= 0 {
+			threshold = config.Threshold
+		}
+		detectionTypes := pc.DetectionTypes
+		if len(detectionTypes) == 0 {
+			detectionTypes = config.DetectionTypes
+		}
+
+		providers = append(providers, notifierProvider{
+			name:           providerLabel(pc),
+			provider:       provider,
+			threshold:      threshold,
+			detectionTypes: toSet(detectionTypes),
+		})
+	}
+
+	return &Notifier{providers: providers, logger: logger}, nil
+}
+
+// providerLabel returns pc.Name if set, falling back to pc.Type so log
+// lines are never blank for unnamed providers.
+func providerLabel(pc ProviderConfig) string {
+	if pc.Name != "" {
+		return pc.Name
+	}
+	return pc.Type
+}
+
+func toSet(values []string) map[string]bool {
+	if len(values) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+const (
+	maxSendAttempts  = 3
+	initialSendDelay = 500 * time.Millisecond
+)
+
+// Dispatch sends event to every provider whose score threshold and
+// detection-type allowlist it satisfies. Each delivery runs in its own
+// goroutine with exponential-backoff retries, so one slow or failing
+// provider never blocks the others or the caller.
+func (n *Notifier) Dispatch(ctx context.Context, event *engine.DetectionEvent) {
+	for _, np := range n.providers {
+		if !np.matches(event) {
+			continue
+		}
+		np := np
+		go n.send(ctx, np, event)
+	}
+}
+
+func (np notifierProvider) matches(event *engine.DetectionEvent) bool {
+	if event.Score < np.threshold {
+		return false
+	}
+	if np.detectionTypes != nil && !np.detectionTypes[event.DetectionType] {
+		return false
+	}
+	return true
+}
+
+func (n *Notifier) send(ctx context.Context, np notifierProvider, event *engine.DetectionEvent) {
+	delay := initialSendDelay
+	var lastErr error
+	for attempt := 1; attempt <= maxSendAttempts; attempt++ {
+		if err := np.provider.Send(ctx, event); err != nil {
+			lastErr = err
+			n.logger.Warn("notification delivery failed",
+				"provider", np.name, "attempt", attempt, "error", err)
+
+			if attempt == maxSendAttempts {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay *= 2
+			continue
+		}
+		return
+	}
+	n.logger.Error("notification delivery exhausted retries",
+		"provider", np.name, "event_id", event.EventID, "error", lastErr)
+}
+
+// newProvider constructs the Provider selected by pc.Type.
+func newProvider(pc ProviderConfig) (Provider, error) {
+	const sendTimeout = 10 * time.Second
+
+	switch pc.Type {
+	case ProviderTypeSlack:
+		if pc.WebhookURL == "" {
+			return nil, fmt.Errorf("slack provider requires webhook_url")
+		}
+		return newSlackProvider(pc.WebhookURL, sendTimeout), nil
+	case ProviderTypePagerDuty:
+		if pc.RoutingKey == "" {
+			return nil, fmt.Errorf("pagerduty provider requires routing_key")
+		}
+		return newPagerDutyProvider(pc.RoutingKey, sendTimeout), nil
+	case ProviderTypeGeneric:
+		if pc.URL == "" {
+			return nil, fmt.Errorf("generic provider requires url")
+		}
+		return newGenericProvider(pc.URL, sendTimeout), nil
+	case ProviderTypeGenericHMAC:
+		if pc.URL == "" || pc.Secret == "" {
+			return nil, fmt.Errorf("generic-hmac provider requires url and secret")
+		}
+		return newGenericHMACProvider(pc.URL, pc.Secret, sendTimeout), nil
+	default:
+		return nil, fmt.Errorf("unsupported provider type: %q", pc.Type)
+	}
+}
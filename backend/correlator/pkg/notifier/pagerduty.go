@@ -0,0 +1,89 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+)
+
+// pagerDutyEventsURL is PagerDuty's Events API v2 ingestion endpoint.
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyProvider triggers a PagerDuty incident via the Events API v2.
+type PagerDutyProvider struct {
+	routingKey string
+	httpClient *http.Client
+}
+
+// pagerDutyPayload mirrors the Events API v2 trigger request body.
+type pagerDutyPayload struct {
+	RoutingKey  string             `json:"routing_key"`
+	EventAction string             `json:"event_action"`
+	Payload     pagerDutyEventBody `json:"payload"`
+}
+
+type pagerDutyEventBody struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	CustomDetails map[string]interface{} `json:"custom_details"`
+}
+
+func newPagerDutyProvider(routingKey string, timeout time.Duration) *PagerDutyProvider {
+	return &PagerDutyProvider{
+		routingKey: routingKey,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send triggers a PagerDuty incident for event.
+func (p *PagerDutyProvider) Send(ctx context.Context, event *engine.DetectionEvent) error {
+	body, err := json.Marshal(pagerDutyPayload{
+		RoutingKey:  p.routingKey,
+		EventAction: "trigger",
+		Payload: pagerDutyEventBody{
+			Summary:       fmt.Sprintf("MCPeeker detection: %s (score %d)", event.DetectionType, event.Score),
+			Source:        event.HostID,
+			Severity:      pagerDutySeverity(event.Score),
+			CustomDetails: event.Evidence,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal pagerduty payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pagerDutyEventsURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build pagerduty request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send pagerduty event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("pagerduty events API returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// pagerDutySeverity maps a detection score onto PagerDuty's fixed severity
+// vocabulary; higher scores indicate higher confidence of unauthorized use.
+func pagerDutySeverity(score int) string {
+	switch {
+	case score >= 80:
+		return "critical"
+	case score >= 50:
+		return "error"
+	default:
+		return "warning"
+	}
+}
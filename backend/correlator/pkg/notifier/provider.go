@@ -0,0 +1,18 @@
+// Package notifier fans out high-score detection events to external
+// alerting channels (Slack, PagerDuty, generic webhooks) as configured by
+// operators, without requiring a recompile to add or change providers.
+// Reference: FR-002 (Multi-layer detection)
+package notifier
+
+import (
+	"context"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+)
+
+// Provider delivers a detection event to a single external destination.
+// Implementations should treat Send as best-effort for a single attempt;
+// Notifier applies retry/backoff around the call.
+type Provider interface {
+	Send(ctx context.Context, event *engine.DetectionEvent) error
+}
@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+)
+
+// SlackProvider posts a message to a Slack incoming webhook URL.
+type SlackProvider struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// slackMessage mirrors Slack's incoming webhook payload shape.
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+func newSlackProvider(webhookURL string, timeout time.Duration) *SlackProvider {
+	return &SlackProvider{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send posts event as a plain-text Slack message.
+func (p *SlackProvider) Send(ctx context.Context, event *engine.DetectionEvent) error {
+	body, err := json.Marshal(slackMessage{Text: fmt.Sprintf(
+		"MCPeeker detection: type=%s score=%d host=%s event=%s",
+		event.DetectionType, event.Score, event.HostID, event.EventID,
+	)})
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send slack notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
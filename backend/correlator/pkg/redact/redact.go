@@ -0,0 +1,128 @@
+// Package redact masks secrets and PII in detection evidence snippets
+// before they reach ClickHouse. pkg/clickhouse.Writer runs this as a
+// belt-and-braces pass on top of the scanner probes' own redaction.
+// Reference: FR-029 (90-day evidence retention)
+package redact
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mask replaces every detected secret value.
+const Mask = "[REDACTED]"
+
+// Result is the outcome of a String redaction pass.
+type Result struct {
+	Text  string
+	Count int
+}
+
+type secretPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// secretPatterns matches common token shapes regardless of which field
+// (if any) they appear under, so a secret embedded in an unlabelled args
+// string or free-text snippet is still caught.
+var secretPatterns = []secretPattern{
+	{"aws_access_key_id", regexp.MustCompile(`AKIA[0-9A-Z]{16}`)},
+	{"github_token", regexp.MustCompile(`gh[oprsu]_[A-Za-z0-9]{36,}`)},
+	{"slack_token", regexp.MustCompile(`xox[baprs]-[A-Za-z0-9-]+`)},
+	{"jwt", regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)},
+	{"bearer_token", regexp.MustCompile(`(?i)Bearer\s+[A-Za-z0-9\-_.=]+`)},
+	{"url_userinfo", regexp.MustCompile(`://[^/\s:@]+:[^/\s@]+@`)},
+}
+
+// isSensitiveKey reports whether a field name's value should be masked
+// outright regardless of its shape: token, api_key, authorization,
+// password, secret, and any *_KEY (e.g. an env map's STRIPE_SECRET_KEY).
+func isSensitiveKey(key string) bool {
+	k := strings.ToLower(key)
+	return strings.Contains(k, "token") ||
+		strings.Contains(k, "apikey") ||
+		strings.Contains(k, "api_key") ||
+		strings.Contains(k, "authorization") ||
+		strings.Contains(k, "password") ||
+		strings.Contains(k, "secret") ||
+		strings.HasSuffix(k, "_key")
+}
+
+// String redacts s in two passes: the regex secretPatterns against the
+// raw text, then, if s parses as JSON or YAML, a structural pass masking
+// string values under a sensitive key.
+func String(s string) Result {
+	text := s
+	count := 0
+	for _, p := range secretPatterns {
+		text = p.re.ReplaceAllStringFunc(text, func(match string) string {
+			count++
+			if p.name == "url_userinfo" {
+				return "://" + Mask + "@"
+			}
+			return Mask
+		})
+	}
+
+	if redacted, structCount, ok := redactStructured(text); ok && structCount > 0 {
+		text = redacted
+		count += structCount
+	}
+
+	return Result{Text: text, Count: count}
+}
+
+// redactStructured tries to parse text as JSON, then YAML, walking
+// whichever succeeds first and masking sensitive-keyed string values.
+// ok is false if text parses as neither.
+func redactStructured(text string) (redacted string, count int, ok bool) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(text), &v); err == nil {
+		var n int
+		out, err := json.Marshal(redactValue("", v, &n))
+		if err == nil {
+			return string(out), n, true
+		}
+	}
+
+	if err := yaml.Unmarshal([]byte(text), &v); err == nil {
+		var n int
+		out, err := yaml.Marshal(redactValue("", v, &n))
+		if err == nil {
+			return string(out), n, true
+		}
+	}
+
+	return "", 0, false
+}
+
+// redactValue walks a decoded JSON/YAML value, masking string leaves
+// whose parent key is sensitive and recursing through maps and slices.
+func redactValue(key string, v interface{}, count *int) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, vv := range val {
+			out[k] = redactValue(k, vv, count)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, vv := range val {
+			out[i] = redactValue(key, vv, count)
+		}
+		return out
+	case string:
+		if isSensitiveKey(key) && val != "" && val != Mask {
+			*count++
+			return Mask
+		}
+		return val
+	default:
+		return val
+	}
+}
@@ -0,0 +1,131 @@
+package registry
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheConfig controls CheckMatch's in-memory response cache, keyed by
+// the normalized MatchRequest tuple.
+type CacheConfig struct {
+	// MaxEntries bounds the cache size; the least recently used entry is
+	// evicted once it's exceeded. MaxEntries <= 0 disables the cache.
+	MaxEntries int
+	// TTL is how long a cached MatchResponse is served before the next
+	// lookup for that key goes to the network again.
+	TTL time.Duration
+}
+
+// DefaultCacheConfig is applied by NewClient when Config.Cache is the
+// zero value.
+var DefaultCacheConfig = CacheConfig{
+	MaxEntries: 1024,
+	TTL:        30 * time.Second,
+}
+
+// normalizeKey builds the cache key from req's tuple, in the same field
+// order CheckMatch already uses to build query parameters, so two
+// equivalent requests always collide on the same key.
+func normalizeKey(req MatchRequest) string {
+	return req.CompositeID + "|" + req.HostIDHash + "|" + strconv.Itoa(req.Port) + "|" + req.ManifestHash
+}
+
+type cacheEntry struct {
+	key       string
+	response  MatchResponse
+	expiresAt time.Time
+}
+
+// responseCache is a fixed-size LRU cache of MatchResponse with a TTL on
+// top, so a stale authorized match can't be served forever even under
+// constant traffic for the same composite_id.
+type responseCache struct {
+	cfg   CacheConfig
+	mu    sync.Mutex
+	items map[string]*list.Element
+	order *list.List // front = most recently used
+}
+
+func newResponseCache(cfg CacheConfig) *responseCache {
+	return &responseCache{
+		cfg:   cfg,
+		items: make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+func (c *responseCache) get(key string) (MatchResponse, bool) {
+	if c.cfg.MaxEntries <= 0 {
+		return MatchResponse{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return MatchResponse{}, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeLocked(elem)
+		return MatchResponse{}, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.response, true
+}
+
+func (c *responseCache) put(key string, resp MatchResponse) {
+	if c.cfg.MaxEntries <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.response = resp
+		entry.expiresAt = time.Now().Add(c.cfg.TTL)
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&cacheEntry{key: key, response: resp, expiresAt: time.Now().Add(c.cfg.TTL)})
+	c.items[key] = elem
+
+	for c.order.Len() > c.cfg.MaxEntries {
+		if oldest := c.order.Back(); oldest != nil {
+			c.removeLocked(oldest)
+		}
+	}
+}
+
+// removeLocked removes elem from both the LRU list and the index map.
+// Callers must hold c.mu.
+func (c *responseCache) removeLocked(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*cacheEntry).key)
+}
+
+// invalidate drops every cached entry for compositeID, so a
+// registry-update webhook can force the next CheckMatch for it to go to
+// the network regardless of what else was in the request tuple.
+func (c *responseCache) invalidate(compositeID string) {
+	prefix := compositeID + "|"
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.removeLocked(elem)
+		}
+	}
+}
+
+func (c *responseCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
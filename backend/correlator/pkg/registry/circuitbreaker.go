@@ -0,0 +1,120 @@
+package registry
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState is one of the states a circuitBreaker can be in.
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreakerConfig controls when CheckMatch trips its circuit open
+// and how long it waits before probing the registry again.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that trips
+	// the circuit from closed to open.
+	FailureThreshold int
+	// OpenDuration is how long the circuit stays open before allowing a
+	// single half-open trial request through.
+	OpenDuration time.Duration
+}
+
+// DefaultCircuitBreakerConfig is applied by NewClient when
+// Config.CircuitBreaker is the zero value.
+var DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+	FailureThreshold: 5,
+	OpenDuration:     30 * time.Second,
+}
+
+// circuitBreaker is a closed/open/half-open breaker guarding CheckMatch:
+// it trips open after FailureThreshold consecutive failures, and after
+// OpenDuration lets exactly one trial call through (half-open) whose
+// outcome decides whether it closes again or reopens.
+type circuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu              sync.Mutex
+	state           CircuitState
+	consecutiveFail int
+	openedAt        time.Time
+}
+
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{cfg: cfg, state: CircuitClosed}
+}
+
+// allow reports whether a call should proceed. An open circuit whose
+// OpenDuration has elapsed transitions to half-open and lets this one
+// call through as the trial.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitClosed:
+		return true
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.cfg.OpenDuration {
+			return false
+		}
+		b.state = CircuitHalfOpen
+		return true
+	case CircuitHalfOpen:
+		// Only the call that flipped us to half-open gets through; every
+		// other caller is short-circuited until it resolves.
+		return false
+	default:
+		return false
+	}
+}
+
+// recordSuccess closes the circuit and resets the failure count.
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = CircuitClosed
+	b.consecutiveFail = 0
+}
+
+// recordFailure counts a failure, tripping the circuit open once
+// FailureThreshold consecutive failures accumulate, or immediately
+// reopening it if the half-open trial call itself failed.
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen {
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+		return
+	}
+
+	b.consecutiveFail++
+	if b.consecutiveFail >= b.cfg.FailureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *circuitBreaker) currentState() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -5,12 +5,17 @@ package registry
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"sync/atomic"
 	"time"
 
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/metrics"
 	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/mtls"
 )
 
@@ -29,29 +34,72 @@ type MatchResponse struct {
 	Penalty int                    `json:"penalty"` // -6 if matched
 }
 
-// Client is a registry API client
+// Auth schemes accepted by Config.AuthScheme, matching what the registry
+// API's auth middleware expects in the Authorization header.
+const (
+	AuthSchemeBearer = "bearer" // Authorization: Bearer <jwt> (default)
+	AuthSchemeToken  = "token"  // Authorization: Token <static-token>
+)
+
+// Client is a registry API client. CheckMatch goes through a response
+// cache and a circuit breaker before any network call, and retries
+// transient network-call failures with jittered backoff; see cache.go,
+// circuitbreaker.go, and retry.go.
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
 	authToken  string
+	authScheme string
+	logger     logging.Logger
+
+	retry   RetryConfig
+	breaker *circuitBreaker
+	cache   *responseCache
+
+	totalRequests        atomic.Int64
+	cacheHits            atomic.Int64
+	cacheMisses          atomic.Int64
+	retries              atomic.Int64
+	circuitShortCircuits atomic.Int64
 }
 
 // Config holds registry client configuration
 type Config struct {
-	BaseURL    string
-	TLSConfig  *mtls.TLSConfig
-	AuthToken  string
+	BaseURL   string
+	TLSConfig *mtls.TLSConfig
+	AuthToken string
+	// AuthScheme selects how AuthToken is presented: AuthSchemeBearer
+	// (default) for HS256/RS256 JWTs, or AuthSchemeToken for a static
+	// bearer token loaded from env/file.
+	AuthScheme string
 	Timeout    time.Duration
+	Logger     logging.Logger
+
+	// Retry, CircuitBreaker, and Cache tune CheckMatch's resilience
+	// behavior. Each defaults to its package-level Default*Config when
+	// left zero-valued.
+	Retry          RetryConfig
+	CircuitBreaker CircuitBreakerConfig
+	Cache          CacheConfig
 }
 
-// NewClient creates a new registry API client
+// NewClient creates a new registry API client. A nil Logger falls back to a
+// discarding logger so existing callers are not forced to supply one.
 func NewClient(config *Config) (*Client, error) {
+	logger := config.Logger
+	if logger == nil {
+		logger = logging.Noop()
+	}
+
 	// Create HTTP client with optional mTLS
 	httpClient := &http.Client{
 		Timeout: config.Timeout,
 	}
 
 	if config.TLSConfig != nil {
+		if config.TLSConfig.Logger == nil {
+			config.TLSConfig.Logger = logger
+		}
 		tlsClient, err := mtls.NewClient(config.TLSConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create mTLS client: %w", err)
@@ -62,15 +110,155 @@ func NewClient(config *Config) (*Client, error) {
 		}
 	}
 
+	authScheme := config.AuthScheme
+	if authScheme == "" {
+		authScheme = AuthSchemeBearer
+	}
+
+	retry := config.Retry
+	if retry == (RetryConfig{}) {
+		retry = DefaultRetryConfig
+	}
+	breakerCfg := config.CircuitBreaker
+	if breakerCfg == (CircuitBreakerConfig{}) {
+		breakerCfg = DefaultCircuitBreakerConfig
+	}
+	cacheCfg := config.Cache
+	if cacheCfg == (CacheConfig{}) {
+		cacheCfg = DefaultCacheConfig
+	}
+
 	return &Client{
 		baseURL:    config.BaseURL,
 		httpClient: httpClient,
 		authToken:  config.AuthToken,
+		authScheme: authScheme,
+		logger:     logger,
+		retry:      retry,
+		breaker:    newCircuitBreaker(breakerCfg),
+		cache:      newResponseCache(cacheCfg),
 	}, nil
 }
 
-// CheckMatch checks if a detection matches any registry entry
+// authHeader returns the Authorization header value for the configured
+// token and scheme, or "" if no token is set.
+func (c *Client) authHeader() string {
+	if c.authToken == "" {
+		return ""
+	}
+	if c.authScheme == AuthSchemeToken {
+		return fmt.Sprintf("Token %s", c.authToken)
+	}
+	return fmt.Sprintf("Bearer %s", c.authToken)
+}
+
+// unknownMatchResponse is what CheckMatch returns while the circuit
+// breaker is open: "unknown" rather than "no match", so callers don't
+// apply a registry mismatch penalty based on the registry simply being
+// unreachable.
+var unknownMatchResponse = MatchResponse{Matched: false, Entry: nil, Penalty: 0}
+
+// CheckMatch checks if a detection matches any registry entry. It first
+// consults an in-memory cache keyed by req's normalized tuple, then the
+// circuit breaker: while the breaker is open, CheckMatch returns
+// unknownMatchResponse without touching the network. Otherwise it issues
+// the HTTP call, retrying 5xx/429/network failures with jittered
+// backoff (honoring Retry-After) up to Retry.MaxRetries times.
 func (c *Client) CheckMatch(ctx context.Context, req MatchRequest) (*MatchResponse, error) {
+	c.totalRequests.Add(1)
+	key := normalizeKey(req)
+
+	if cached, ok := c.cache.get(key); ok {
+		c.cacheHits.Add(1)
+		metrics.RegistryCacheHitsTotal.Inc()
+		result := cached
+		return &result, nil
+	}
+	c.cacheMisses.Add(1)
+	metrics.RegistryCacheMissesTotal.Inc()
+
+	if !c.breaker.allow() {
+		c.circuitShortCircuits.Add(1)
+		metrics.RegistryCircuitShortCircuitsTotal.Inc()
+		c.logger.Debug("registry circuit breaker open, skipping network call", "composite_id", req.CompositeID)
+		result := unknownMatchResponse
+		return &result, nil
+	}
+
+	start := time.Now()
+	matchResp, err := c.checkMatchWithRetry(ctx, req)
+	metrics.RegistryLookupLatencySeconds.Observe(time.Since(start).Seconds())
+	metrics.RegistryCircuitBreakerState.Set(float64(c.breaker.currentState()))
+	if err != nil {
+		c.breaker.recordFailure()
+		metrics.RegistryCircuitBreakerState.Set(float64(c.breaker.currentState()))
+		return nil, err
+	}
+	c.breaker.recordSuccess()
+	metrics.RegistryCircuitBreakerState.Set(float64(c.breaker.currentState()))
+
+	c.cache.put(key, *matchResp)
+	c.logger.Debug("registry match check completed", "composite_id", req.CompositeID, "matched", matchResp.Matched)
+	return matchResp, nil
+}
+
+// checkMatchWithRetry issues checkMatchOnce, retrying up to
+// c.retry.MaxRetries times on a retryable failure (5xx, 429, or a
+// network error) with full-jitter exponential backoff, honoring any
+// Retry-After the registry sends in place of the computed backoff.
+func (c *Client) checkMatchWithRetry(ctx context.Context, req MatchRequest) (*MatchResponse, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.retry.MaxRetries; attempt++ {
+		if attempt > 0 {
+			c.retries.Add(1)
+			metrics.RegistryRetriesTotal.WithLabelValues(strconv.Itoa(attempt)).Inc()
+		}
+
+		resp, retryAfter, err := c.checkMatchOnce(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+
+		var httpErr *retryableStatusError
+		if !errors.As(err, &httpErr) {
+			// Non-retryable (e.g. 4xx other than 429, or decode failure).
+			return nil, err
+		}
+		if attempt == c.retry.MaxRetries {
+			break
+		}
+
+		delay := c.retry.backoffDelay(attempt)
+		if retryAfter > 0 {
+			delay = retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	return nil, lastErr
+}
+
+// retryableStatusError wraps a registry failure that CheckMatch's retry
+// loop should retry: a 5xx or 429 response, or a transport-level error
+// (status 0). A request-construction error, any other 4xx, or a decode
+// error is returned as a plain error and is not retried.
+type retryableStatusError struct {
+	status int
+	body   string
+}
+
+func (e *retryableStatusError) Error() string {
+	return fmt.Sprintf("registry API returned status %d: %s", e.status, e.body)
+}
+
+// checkMatchOnce issues a single CheckMatch HTTP call. retryAfter is
+// populated only when err is a *retryableStatusError and the response
+// carried a Retry-After header.
+func (c *Client) checkMatchOnce(ctx context.Context, req MatchRequest) (resp *MatchResponse, retryAfter time.Duration, err error) {
 	// Build query parameters
 	params := url.Values{}
 	if req.CompositeID != "" {
@@ -92,34 +280,72 @@ func (c *Client) CheckMatch(ctx context.Context, req MatchRequest) (*MatchRespon
 	// Create request
 	httpReq, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Add auth token if available
-	if c.authToken != "" {
-		httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.authToken))
+	if header := c.authHeader(); header != "" {
+		httpReq.Header.Set("Authorization", header)
 	}
 
-	// Execute request
-	resp, err := c.httpClient.Do(httpReq)
+	// Execute request. A transport-level error (timeout, connection
+	// refused, DNS failure) is treated the same as a 5xx: retryable.
+	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, 0, &retryableStatusError{status: 0, body: err.Error()}
 	}
-	defer resp.Body.Close()
+	defer httpResp.Body.Close()
 
 	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("registry API returned status %d: %s", resp.StatusCode, string(body))
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(httpResp.Body)
+		if !isRetryableStatus(httpResp.StatusCode) {
+			return nil, 0, fmt.Errorf("registry API returned status %d: %s", httpResp.StatusCode, string(body))
+		}
+		delay, _ := retryAfterDelay(httpResp)
+		return nil, delay, &retryableStatusError{status: httpResp.StatusCode, body: string(body)}
 	}
 
 	// Parse response
 	var matchResp MatchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&matchResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.NewDecoder(httpResp.Body).Decode(&matchResp); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &matchResp, nil
+	return &matchResp, 0, nil
+}
+
+// Invalidate drops any cached CheckMatch response for compositeID, so a
+// registry-update webhook can force the next lookup to go to the
+// network instead of serving a stale cached entry.
+func (c *Client) Invalidate(compositeID string) {
+	c.cache.invalidate(compositeID)
+}
+
+// Stats is a point-in-time snapshot of Client's resilience counters,
+// exposed for diagnostics alongside the Prometheus metrics in
+// pkg/metrics.
+type Stats struct {
+	TotalRequests        int64
+	CacheHits            int64
+	CacheMisses          int64
+	Retries              int64
+	CircuitShortCircuits int64
+	CircuitState         CircuitState
+	CacheSize            int
+}
+
+// Stats returns a snapshot of this client's resilience counters.
+func (c *Client) Stats() Stats {
+	return Stats{
+		TotalRequests:        c.totalRequests.Load(),
+		CacheHits:            c.cacheHits.Load(),
+		CacheMisses:          c.cacheMisses.Load(),
+		Retries:              c.retries.Load(),
+		CircuitShortCircuits: c.circuitShortCircuits.Load(),
+		CircuitState:         c.breaker.currentState(),
+		CacheSize:            c.cache.len(),
+	}
 }
 
 // GetEntry retrieves a specific registry entry by ID
@@ -131,8 +357,8 @@ func (c *Client) GetEntry(ctx context.Context, entryID string) (map[string]inter
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	if c.authToken != "" {
-		httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.authToken))
+	if header := c.authHeader(); header != "" {
+		httpReq.Header.Set("Authorization", header)
 	}
 
 	resp, err := c.httpClient.Do(httpReq)
@@ -0,0 +1,174 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryConfigBackoffDelayRespectsMaxDelay(t *testing.T) {
+	cfg := RetryConfig{BaseDelay: 100 * time.Millisecond, MaxDelay: 200 * time.Millisecond}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := cfg.backoffDelay(attempt)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, cfg.MaxDelay)
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	assert.True(t, isRetryableStatus(http.StatusTooManyRequests))
+	assert.True(t, isRetryableStatus(http.StatusInternalServerError))
+	assert.True(t, isRetryableStatus(http.StatusBadGateway))
+	assert.False(t, isRetryableStatus(http.StatusOK))
+	assert.False(t, isRetryableStatus(http.StatusNotFound))
+}
+
+func TestRetryAfterDelaySeconds(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"5"}}}
+
+	delay, ok := retryAfterDelay(resp)
+
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, delay)
+}
+
+func TestRetryAfterDelayMissingHeader(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+
+	_, ok := retryAfterDelay(resp)
+
+	assert.False(t, ok)
+}
+
+func TestRetryAfterDelayHTTPDate(t *testing.T) {
+	when := time.Now().Add(10 * time.Second)
+	resp := httptest.NewRecorder().Result()
+	resp.Header.Set("Retry-After", when.UTC().Format(http.TimeFormat))
+
+	delay, ok := retryAfterDelay(resp)
+
+	assert.True(t, ok)
+	assert.InDelta(t, 10*time.Second, delay, float64(2*time.Second))
+}
+
+func TestCircuitBreakerTripsAfterThreshold(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Hour})
+
+	assert.True(t, cb.allow())
+	cb.recordFailure()
+	assert.Equal(t, CircuitClosed, cb.currentState())
+
+	cb.recordFailure()
+	assert.Equal(t, CircuitOpen, cb.currentState())
+	assert.False(t, cb.allow())
+}
+
+func TestCircuitBreakerHalfOpenAfterOpenDuration(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Millisecond})
+
+	cb.recordFailure()
+	assert.Equal(t, CircuitOpen, cb.currentState())
+
+	time.Sleep(2 * time.Millisecond)
+	assert.True(t, cb.allow())
+	assert.Equal(t, CircuitHalfOpen, cb.currentState())
+
+	// Only the trial call that flipped it to half-open gets through.
+	assert.False(t, cb.allow())
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Millisecond})
+
+	cb.recordFailure()
+	time.Sleep(2 * time.Millisecond)
+	cb.allow() // flips to half-open
+
+	cb.recordFailure()
+	assert.Equal(t, CircuitOpen, cb.currentState())
+}
+
+func TestCircuitBreakerSuccessClosesAndResets(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Hour})
+
+	cb.recordFailure()
+	cb.recordSuccess()
+	assert.Equal(t, CircuitClosed, cb.currentState())
+
+	// The reset should require a full FailureThreshold of new failures to trip again.
+	cb.recordFailure()
+	assert.Equal(t, CircuitClosed, cb.currentState())
+}
+
+func TestResponseCacheGetPutRoundTrip(t *testing.T) {
+	cache := newResponseCache(CacheConfig{MaxEntries: 2, TTL: time.Hour})
+	req := MatchRequest{CompositeID: "c1", HostIDHash: "h1", Port: 8080, ManifestHash: "m1"}
+	key := normalizeKey(req)
+
+	_, ok := cache.get(key)
+	assert.False(t, ok)
+
+	cache.put(key, MatchResponse{Matched: true, Penalty: -6})
+	resp, ok := cache.get(key)
+	assert.True(t, ok)
+	assert.True(t, resp.Matched)
+}
+
+func TestResponseCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newResponseCache(CacheConfig{MaxEntries: 2, TTL: time.Hour})
+
+	cache.put("a", MatchResponse{})
+	cache.put("b", MatchResponse{})
+	cache.get("a") // "a" is now most recently used; "b" is next to evict
+	cache.put("c", MatchResponse{})
+
+	_, aOK := cache.get("a")
+	_, bOK := cache.get("b")
+	_, cOK := cache.get("c")
+	assert.True(t, aOK)
+	assert.False(t, bOK)
+	assert.True(t, cOK)
+	assert.Equal(t, 2, cache.len())
+}
+
+func TestResponseCacheExpiresAfterTTL(t *testing.T) {
+	cache := newResponseCache(CacheConfig{MaxEntries: 2, TTL: time.Millisecond})
+
+	cache.put("a", MatchResponse{Matched: true})
+	time.Sleep(2 * time.Millisecond)
+
+	_, ok := cache.get("a")
+	assert.False(t, ok)
+}
+
+func TestResponseCacheDisabledWhenMaxEntriesNonPositive(t *testing.T) {
+	cache := newResponseCache(CacheConfig{MaxEntries: 0, TTL: time.Hour})
+
+	cache.put("a", MatchResponse{Matched: true})
+	_, ok := cache.get("a")
+
+	assert.False(t, ok)
+}
+
+func TestResponseCacheInvalidateByCompositeID(t *testing.T) {
+	cache := newResponseCache(CacheConfig{MaxEntries: 10, TTL: time.Hour})
+	req1 := MatchRequest{CompositeID: "c1", HostIDHash: "h1"}
+	req2 := MatchRequest{CompositeID: "c1", HostIDHash: "h2"}
+	req3 := MatchRequest{CompositeID: "c2", HostIDHash: "h1"}
+	cache.put(normalizeKey(req1), MatchResponse{})
+	cache.put(normalizeKey(req2), MatchResponse{})
+	cache.put(normalizeKey(req3), MatchResponse{})
+
+	cache.invalidate("c1")
+
+	_, ok1 := cache.get(normalizeKey(req1))
+	_, ok2 := cache.get(normalizeKey(req2))
+	_, ok3 := cache.get(normalizeKey(req3))
+	assert.False(t, ok1)
+	assert.False(t, ok2)
+	assert.True(t, ok3)
+}
@@ -0,0 +1,69 @@
+package registry
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryConfig controls CheckMatch's retries on 5xx, 429, and network
+// errors. MaxRetries 0 disables retries, but an entirely zero-valued
+// Config.Retry is replaced by DefaultRetryConfig in NewClient.
+type RetryConfig struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryConfig is applied by NewClient when Config.Retry is the
+// zero value.
+var DefaultRetryConfig = RetryConfig{
+	MaxRetries: 3,
+	BaseDelay:  100 * time.Millisecond,
+	MaxDelay:   5 * time.Second,
+}
+
+// backoffDelay returns the full-jitter exponential backoff for the given
+// retry attempt (0-indexed): a uniform random duration between 0 and
+// min(MaxDelay, BaseDelay*2^attempt).
+func (c RetryConfig) backoffDelay(attempt int) time.Duration {
+	shift := attempt
+	if shift > 20 { // guard against overflow before the cap below applies
+		shift = 20
+	}
+	capped := c.BaseDelay << uint(shift)
+	if capped <= 0 || capped > c.MaxDelay {
+		capped = c.MaxDelay
+	}
+	if capped <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(capped) + 1))
+}
+
+// isRetryableStatus reports whether status warrants a retry under this
+// client's 5xx/429 policy.
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryAfterDelay parses resp's Retry-After header (either delay-seconds
+// or an HTTP-date) and returns the wait it specifies, or ok=false if the
+// header is absent or unparsable.
+func retryAfterDelay(resp *http.Response) (time.Duration, bool) {
+	raw := resp.Header.Get("Retry-After")
+	if raw == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(raw); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(raw); err == nil {
+		if delay := time.Until(when); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/engine"
+	"github.com/ozlabs/mcpeeker/backend/correlator/pkg/logging"
 )
 
 // RetrospectiveScorer handles re-scoring of detections when Judge service recovers
@@ -17,6 +18,7 @@ type RetrospectiveScorer struct {
 	clickhouseConn ClickHouseConnection
 	maxRetries     int
 	retryInterval  time.Duration
+	logger         logging.Logger
 }
 
 // JudgeClient interface for calling Judge service
@@ -39,20 +41,27 @@ type JudgeClassification struct {
 	ScoreContribution int
 }
 
-// NewRetrospectiveScorer creates a new retrospective scorer
+// NewRetrospectiveScorer creates a new retrospective scorer. A nil Logger
+// falls back to a discarding logger so existing callers are not forced to
+// supply one.
 func NewRetrospectiveScorer(
 	correlator *engine.Correlator,
 	judgeClient JudgeClient,
 	clickhouseConn ClickHouseConnection,
 	maxRetries int,
 	retryInterval time.Duration,
+	logger logging.Logger,
 ) *RetrospectiveScorer {
+	if logger == nil {
+		logger = logging.Noop()
+	}
 	return &RetrospectiveScorer{
 		correlator:     correlator,
 		judgeClient:    judgeClient,
 		clickhouseConn: clickhouseConn,
 		maxRetries:     maxRetries,
 		retryInterval:  retryInterval,
+		logger:         logger,
 	}
 }
 
@@ -74,14 +83,14 @@ func (rs *RetrospectiveScorer) RunRetrospectiveScoring(ctx context.Context, batc
 		return nil // Nothing to rescore
 	}
 
-	fmt.Printf("Found %d detections without Judge evidence, starting retrospective scoring\n", len(detections))
+	rs.logger.Info("found detections without judge evidence, starting retrospective scoring", "count", len(detections))
 
 	successCount := 0
 	errorCount := 0
 
 	for _, detection := range detections {
 		if err := rs.rescoreDetection(ctx, detection); err != nil {
-			fmt.Printf("Failed to rescore detection %s: %v\n", detection.CompositeID, err)
+			rs.logger.Error("failed to rescore detection", "composite_id", detection.CompositeID, "error", err)
 			errorCount++
 		} else {
 			successCount++
@@ -95,7 +104,7 @@ func (rs *RetrospectiveScorer) RunRetrospectiveScoring(ctx context.Context, batc
 		}
 	}
 
-	fmt.Printf("Retrospective scoring complete: %d succeeded, %d failed\n", successCount, errorCount)
+	rs.logger.Info("retrospective scoring complete", "succeeded", successCount, "failed", errorCount)
 	return nil
 }
 
@@ -157,8 +166,14 @@ func (rs *RetrospectiveScorer) rescoreDetection(ctx context.Context, detection *
 		return fmt.Errorf("failed to update detection: %w", err)
 	}
 
-	fmt.Printf("Successfully rescored detection %s: new score=%d, classification=%s\n",
-		detection.CompositeID, detection.Score, detection.Classification)
+	logging.WithDetection(rs.logger, detection.CompositeID, detection.HostIDHash, detection.Classification, detection.Score).
+		With(
+			"evidence_type", judgeEvidence.Type,
+			"evidence_source", judgeEvidence.Source,
+			"score_contribution", judgeEvidence.ScoreContribution,
+			"judge_confidence", classification.Confidence,
+		).
+		Info("successfully rescored detection")
 
 	return nil
 }
@@ -173,10 +188,10 @@ func (rs *RetrospectiveScorer) SchedulePeriodicScoring(ctx context.Context, inte
 		case <-ticker.C:
 			// Run retrospective scoring
 			if err := rs.RunRetrospectiveScoring(ctx, batchSize); err != nil {
-				fmt.Printf("Retrospective scoring error: %v\n", err)
+				rs.logger.Error("retrospective scoring error", "error", err)
 			}
 		case <-ctx.Done():
-			fmt.Println("Stopping retrospective scoring scheduler")
+			rs.logger.Info("stopping retrospective scoring scheduler")
 			return
 		}
 	}
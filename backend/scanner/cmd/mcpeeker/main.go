@@ -0,0 +1,45 @@
+// mcpeeker is an operator CLI for MCPeeker scanner administration tasks.
+// Reference: FR-016 (JSON Schema validation)
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/config"
+)
+
+const defaultConfigPath = "/etc/mcpeeker/config"
+
+func main() {
+	if len(os.Args) < 3 || os.Args[1] != "config" || os.Args[2] != "validate" {
+		fmt.Fprintln(os.Stderr, "usage: mcpeeker config validate [config-dir]")
+		os.Exit(2)
+	}
+
+	configDir := defaultConfigPath
+	if len(os.Args) > 3 {
+		configDir = os.Args[3]
+	}
+
+	if err := validateConfigDir(configDir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("config OK: %s\n", configDir)
+}
+
+// validateConfigDir runs schema validation followed by the full semantic
+// Validate() checks LoadConfig already performs, so CI catches anything a
+// normal service start would reject.
+func validateConfigDir(configDir string) error {
+	if err := config.ValidateFile(fmt.Sprintf("%s/global.yaml", configDir)); err != nil {
+		return err
+	}
+	if err := config.ValidateFile(fmt.Sprintf("%s/scanner.yaml", configDir)); err != nil {
+		return err
+	}
+	_, err := config.LoadConfig(configDir)
+	return err
+}
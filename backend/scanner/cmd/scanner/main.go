@@ -4,88 +4,158 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	obsserver "github.com/ozlabs/mcpeeker/backend/scanner/internal/httpserver"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/buildinfo"
 	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/config"
-	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/filescan"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/httpserver"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/logging"
 	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/metrics"
-	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/procscan"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/probe"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/proctrace"
 	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/publisher"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	// Blank-imported so each probe package self-registers into pkg/probe's
+	// registry from its own init(). A site-specific build can add its own
+	// probe package here (or in a separate binary importing this one's
+	// packages) without touching the registry or the scan loop below.
+	_ "github.com/ozlabs/mcpeeker/backend/scanner/pkg/probe/containerscan"
+	_ "github.com/ozlabs/mcpeeker/backend/scanner/pkg/probe/filescan"
+	_ "github.com/ozlabs/mcpeeker/backend/scanner/pkg/probe/procscan"
 )
 
 const (
-	defaultConfigPath       = "/etc/mcpeeker/config"
-	defaultSchemaPath       = "/etc/mcpeeker/schemas/endpoint-event.schema.json"
-	defaultScanInterval     = 12 * time.Hour
-	defaultMaxFileSize      = 10 * 1024 * 1024 // 10MB
-	defaultMaxProcesses     = 1000
-	defaultPublishTimeout   = 5 * time.Second
-	defaultMetricsPort      = ":8080"
-	defaultHealthPort       = ":8081"
+	defaultConfigPath     = "/etc/mcpeeker/config"
+	defaultSchemaPath     = "/etc/mcpeeker/schemas/endpoint-event.schema.json"
+	defaultScanInterval   = 12 * time.Hour
+	defaultMaxFileSize    = 10 * 1024 * 1024 // 10MB
+	defaultMaxProcesses   = 1000
+	defaultPublishTimeout = 5 * time.Second
+	defaultMetricsPort    = ":8080"
+	defaultHealthPort     = ":8081"
 )
 
 func main() {
-	log.Println("Starting MCPeeker Scanner Service...")
-
 	// Load configuration
 	cfg, err := loadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		// No logger yet (config failed to load), fall back to a bootstrap one.
+		logging.New(logging.Config{Name: "scanner"}).Error("failed to load configuration", "error", err)
+		os.Exit(1)
 	}
 
+	logger := logging.New(logging.Config{
+		Name:            "scanner",
+		Level:           cfg.LogLevel,
+		Format:          cfg.LogFormat,
+		DebugSampleRate: 20,
+	})
+	logger.Info("Starting MCPeeker Scanner Service...", "build", buildinfo.String())
+	metrics.RecordBuildInfo(buildinfo.Version, buildinfo.GitCommit, buildinfo.BuildDate, buildinfo.GoVersion)
+
+	watcher := config.NewWatcherWithSnapshot(configPath(), cfg, logger.Named("config"), applyDefaults)
+
 	// Get host ID
-	hostID := getHostID()
-	log.Printf("Host ID: %s", hostID)
+	hostID := getHostID(logger)
+	logger.Info("resolved host ID", "host_id", hostID)
 
 	// Create NATS publisher
 	pub, err := createPublisher(cfg)
 	if err != nil {
-		log.Fatalf("Failed to create publisher: %v", err)
+		logger.Error("failed to create publisher", "error", err)
+		os.Exit(1)
 	}
 	defer pub.Close()
 
-	// Start metrics server
-	go startMetricsServer(cfg.MetricsPort)
-
-	// Start health check server
-	go startHealthServer(cfg.HealthPort)
-
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Initialize distributed tracing (see pkg/tracing). Disabled by
+	// default, in which case this leaves the no-op TracerProvider in
+	// place and shutdownTracing is a no-op.
+	shutdownTracing, err := tracing.Init(ctx, cfg.Tracing, "scanner", logger.Named("tracing"))
+	if err != nil {
+		logger.Error("failed to initialize tracing", "error", err)
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+
+	// Start the merged metrics/health server (see internal/httpserver):
+	// /metrics, /healthz, /readyz alongside the existing /health, /ready,
+	// /version, /-/reload, all on one lifecycle-managed listener instead of
+	// the former separate metrics and health servers.
+	var lastScanSuccess atomic.Int64
+	obsServer, err := startObservabilityServer(ctx, cfg.HealthPort, cfg.HTTPServer, pub, watcher, &lastScanSuccess, logger)
+	if err != nil {
+		logger.Error("failed to start metrics/health server", "error", err)
+		os.Exit(1)
+	}
+
 	// Setup signal handling
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
+	// Watch for SIGHUP/fsnotify config reloads
+	go watcher.Watch(ctx)
+
+	// Build and configure every registered probe (see pkg/probe)
+	probes, err := buildProbes(cfg, hostID, logger)
+	if err != nil {
+		logger.Error("failed to configure probes", "error", err)
+		os.Exit(1)
+	}
+
 	// Start scan loop
-	go runScanLoop(ctx, cfg, pub, hostID)
+	go runScanLoop(ctx, watcher, probes, pub, hostID, &lastScanSuccess, logger)
+
+	// Start any probe's continuous watch mode (currently only filescan's
+	// fsnotify watcher, opted into via probes.filescan.watch)
+	runProbeWatchers(ctx, probes, pub, logger)
+
+	// Start real-time eBPF process tracing (no-op unless cfg.ProcTrace.Enabled)
+	go runProcessTracer(ctx, cfg, pub, hostID, logger)
 
 	// Wait for shutdown signal
 	<-sigChan
-	log.Println("Shutdown signal received, stopping scanner...")
+	logger.Info("shutdown signal received, stopping scanner")
 	cancel()
 
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := obsServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error("metrics/health server shutdown error", "error", err)
+	}
+
 	// Give ongoing scans time to complete
 	time.Sleep(5 * time.Second)
-	log.Println("Scanner stopped")
+	logger.Info("scanner stopped")
 }
 
-// loadConfig loads scanner configuration
-func loadConfig() (*config.FlatConfig, error) {
-	configPath := os.Getenv("CONFIG_PATH")
-	if configPath == "" {
-		configPath = defaultConfigPath
+// configPath returns the directory scanner config is loaded from.
+func configPath() string {
+	if p := os.Getenv("CONFIG_PATH"); p != "" {
+		return p
 	}
+	return defaultConfigPath
+}
 
-	cfg, err := config.Load(configPath)
+// loadConfig loads scanner configuration
+func loadConfig() (*config.FlatConfig, error) {
+	cfg, err := config.Load(configPath())
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +166,13 @@ func loadConfig() (*config.FlatConfig, error) {
 		return nil, err
 	}
 
-	// Apply defaults if not set
+	applyDefaults(flatCfg)
+	return flatCfg, nil
+}
+
+// applyDefaults fills in zero-valued tunables so both the initial load and
+// every later hot reload land on the same defaults.
+func applyDefaults(flatCfg *config.FlatConfig) {
 	if flatCfg.ScanInterval == 0 {
 		flatCfg.ScanInterval = defaultScanInterval
 	}
@@ -112,12 +188,10 @@ func loadConfig() (*config.FlatConfig, error) {
 	if flatCfg.HealthPort == "" {
 		flatCfg.HealthPort = defaultHealthPort
 	}
-
-	return flatCfg, nil
 }
 
 // getHostID retrieves or generates host ID
-func getHostID() string {
+func getHostID(logger logging.Logger) string {
 	// Try to read from environment
 	if hostID := os.Getenv("HOST_ID"); hostID != "" {
 		return hostID
@@ -126,7 +200,7 @@ func getHostID() string {
 	// Try to read hostname
 	hostname, err := os.Hostname()
 	if err != nil {
-		log.Printf("Warning: failed to get hostname: %v", err)
+		logger.Warn("failed to get hostname", "error", err)
 		return "unknown-host"
 	}
 
@@ -141,162 +215,359 @@ func createPublisher(cfg *config.FlatConfig) (*publisher.Publisher, error) {
 	}
 
 	pubConfig := &publisher.Config{
+		BrokerType:       cfg.BrokerType,
+		Codec:            cfg.Codec,
 		NATSUrl:          cfg.NATSUrl,
 		Subject:          "endpoint.events",
 		SchemaPath:       schemaPath,
 		EnableValidation: cfg.EnableSchemaValidation,
 		ConnectTimeout:   10 * time.Second,
 		PublishTimeout:   defaultPublishTimeout,
+		TLSEnabled:       cfg.NATSTLSEnabled,
+		TLSCertFile:      cfg.NATSTLSCertFile,
+		TLSKeyFile:       cfg.NATSTLSKeyFile,
+		TLSCAFile:        cfg.NATSTLSCAFile,
+		NKeySeedFile:     cfg.NATSNKeySeedFile,
+		CredsFile:        cfg.NATSCredsFile,
+		Token:            cfg.NATSToken,
+		AMQPUrl:          cfg.AMQPUrl,
+		AMQPExchange:     cfg.AMQPExchange,
+		AMQPRoutingKey:   cfg.AMQPRoutingKey,
+		AMQPPersistent:   cfg.AMQPPersistent,
 	}
 
 	return publisher.NewPublisher(pubConfig)
 }
 
-// runScanLoop runs the scan loop at configured interval
-func runScanLoop(ctx context.Context, cfg *config.FlatConfig, pub *publisher.Publisher, hostID string) {
-	// Run initial scan immediately
-	runScan(ctx, cfg, pub, hostID)
+// configuredProbe pairs a pkg/probe.Probe (already Init'd) with its
+// scheduling interval. A zero interval means the probe runs on the
+// scanner-wide ScanInterval inside runScanLoop; a non-zero interval (set
+// via the `probes` map, see pkg/config) gets its own ticker in
+// runProbeLoop instead, so e.g. a lightweight probe can run every minute
+// without forcing every other probe onto the same cadence.
+type configuredProbe struct {
+	probe.Probe
+	interval time.Duration
+	watch    bool
+}
+
+// buildProbes configures one instance of every registered probe (see
+// pkg/probe.Registered) whose `probes` map entry is enabled. A registered
+// probe with no entry in the map is skipped: unlike the legacy
+// procscan/filescan/containerscan knobs, a new third-party probe starts
+// disabled until an operator opts in.
+func buildProbes(cfg *config.FlatConfig, hostID string, logger logging.Logger) ([]configuredProbe, error) {
+	var configured []configuredProbe
+	for _, p := range probe.Registered() {
+		probeCfg, ok := cfg.Probes[p.Name()]
+		if !ok || !probeCfg.Enabled {
+			logger.Debug("probe disabled, skipping", "probe", p.Name())
+			continue
+		}
+
+		var interval time.Duration
+		if probeCfg.Interval != "" {
+			parsed, err := time.ParseDuration(probeCfg.Interval)
+			if err != nil {
+				return nil, fmt.Errorf("probe %q: invalid interval %q: %w", p.Name(), probeCfg.Interval, err)
+			}
+			interval = parsed
+		}
+
+		var stateDBPath string
+		if cfg.Incremental.Enabled {
+			stateDBPath = cfg.Incremental.StateDBPath
+		}
+
+		if err := p.Init(probe.Config{
+			Enabled:         probeCfg.Enabled,
+			Interval:        interval,
+			Patterns:        probeCfg.Patterns,
+			Detectors:       probeCfg.Detectors,
+			StateDBPath:     stateDBPath,
+			Watch:           probeCfg.Watch,
+			FilesystemRoots: cfg.FilesystemRoots,
+			MaxFileSize:     cfg.MaxFileSize,
+			MaxProcesses:    cfg.MaxProcesses,
+			PortPatterns:    cfg.PortPatterns,
+			Logger:          logger.Named(p.Name()),
+		}, hostID); err != nil {
+			return nil, fmt.Errorf("probe %q: init: %w", p.Name(), err)
+		}
 
-	// Create ticker for periodic scans
-	ticker := time.NewTicker(cfg.ScanInterval)
+		logger.Info("probe configured", "probe", p.Name(), "interval", interval, "watch", probeCfg.Watch)
+		configured = append(configured, configuredProbe{Probe: p, interval: interval, watch: probeCfg.Watch})
+	}
+	return configured, nil
+}
+
+// runScanLoop runs every probe with no custom interval at the scanner-wide
+// ScanInterval, re-reading the interval from watcher on every tick so a hot
+// config reload takes effect without a restart. Probes with a custom
+// interval (see configuredProbe) are scheduled separately in runProbeLoop.
+func runScanLoop(ctx context.Context, watcher *config.Watcher, probes []configuredProbe, pub *publisher.Publisher, hostID string, lastScan *atomic.Int64, logger logging.Logger) {
+	var sharedProbes []configuredProbe
+	for _, cp := range probes {
+		if cp.interval == 0 {
+			sharedProbes = append(sharedProbes, cp)
+		} else {
+			go runProbeLoop(ctx, cp, pub, logger)
+		}
+	}
+
+	cfg := watcher.Current()
+	runScan(ctx, cfg, sharedProbes, pub, hostID, lastScan, logger)
+
+	currentInterval := cfg.ScanInterval
+	ticker := time.NewTicker(currentInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			runScan(ctx, cfg, pub, hostID)
+			cfg := watcher.Current()
+			if cfg.ScanInterval != currentInterval {
+				ticker.Reset(cfg.ScanInterval)
+				currentInterval = cfg.ScanInterval
+				logger.Info("scan interval updated via config reload", "interval", currentInterval)
+			}
+			runScan(ctx, cfg, sharedProbes, pub, hostID, lastScan, logger)
 		case <-ctx.Done():
-			log.Println("Scan loop stopped")
+			logger.Info("scan loop stopped")
 			return
 		}
 	}
 }
 
-// runScan performs a single scan cycle
-func runScan(ctx context.Context, cfg *config.FlatConfig, pub *publisher.Publisher, hostID string) {
-	log.Println("Starting scan cycle...")
-	scanStart := time.Now()
+// runProbeLoop runs a single probe on its own custom interval (set via the
+// `probes` map), independent of the scanner-wide scan cycle and any later
+// ScanInterval hot reload.
+func runProbeLoop(ctx context.Context, cp configuredProbe, pub *publisher.Publisher, logger logging.Logger) {
+	runProbeOnce(ctx, cp.Probe, pub, logger)
+
+	ticker := time.NewTicker(cp.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			runProbeOnce(ctx, cp.Probe, pub, logger)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
 
-	// Run file scan
-	fileDetections := runFileScan(ctx, cfg, pub, hostID)
+// runProcessTracer runs pkg/proctrace's eBPF tracer for the lifetime of the
+// process, publishing realtime exec detections as they happen rather than
+// waiting for the next scan cycle. Unlike ScanInterval, ProcTrace.Enabled
+// is read once at startup: attaching/detaching eBPF probes on every config
+// reload isn't worth the complexity for a flag operators set once per host.
+func runProcessTracer(ctx context.Context, cfg *config.FlatConfig, pub *publisher.Publisher, hostID string, logger logging.Logger) {
+	tracer := proctrace.NewTracer(cfg.ProcTrace, hostID, logger.Named("proctrace"))
+	if err := tracer.Run(ctx, pub); err != nil {
+		logger.Error("eBPF tracer stopped with error", "error", err)
+	}
+}
+
+// runScan performs a single scan cycle as a root trace span, so every
+// detection published within it (see publisher.PublishDetection) carries a
+// traceparent the correlator can continue. It runs every shared-interval
+// probe (see configuredProbe) in turn, then records the cycle's completion
+// time in lastScan for the /readyz readiness closure (see
+// startObservabilityServer) to judge scan freshness by.
+func runScan(ctx context.Context, cfg *config.FlatConfig, probes []configuredProbe, pub *publisher.Publisher, hostID string, lastScan *atomic.Int64, logger logging.Logger) {
+	ctx, span := tracing.Tracer.Start(ctx, "Scanner.ScanCycle", trace.WithAttributes(
+		attribute.String("host_id_hash", hashHostID(hostID)),
+		attribute.String("scan.interval", cfg.ScanInterval.String()),
+		attribute.Int("scan.roots_count", len(cfg.FilesystemRoots)),
+		attribute.Int("scan.probe_count", len(probes)),
+	))
+	defer span.End()
+
+	logger.Info("starting scan cycle", "probes", len(probes))
+	scanStart := time.Now()
 
-	// Run process scan
-	processDetections := runProcessScan(ctx, cfg, pub, hostID)
+	totalDetections := 0
+	for _, cp := range probes {
+		totalDetections += runProbeOnce(ctx, cp.Probe, pub, logger)
+	}
 
-	// Record scan metrics
 	scanDuration := time.Since(scanStart)
 	metrics.ScanDurationSeconds.Observe(scanDuration.Seconds())
-
-	totalDetections := fileDetections + processDetections
-	log.Printf("Scan cycle completed in %v: %d file detections, %d process detections",
-		scanDuration, fileDetections, processDetections)
-
-	// Update last scan time metric
-	metrics.LastScanTimestamp.SetToCurrentTime()
+	lastScan.Store(scanStart.Add(scanDuration).Unix())
+	logger.Info("scan cycle completed", "duration", scanDuration, "total_detections", totalDetections)
 }
 
-// runFileScan runs filesystem scan
-func runFileScan(ctx context.Context, cfg *config.FlatConfig, pub *publisher.Publisher, hostID string) int {
-	log.Println("Running file scan...")
+// hashHostID hashes hostID for the scan span's host_id_hash attribute, so
+// traces never carry the raw host identifier. It is a plain unkeyed SHA256,
+// unlike the correlator's keyed identifier.HashHostID, so the two values
+// do not match.
+func hashHostID(hostID string) string {
+	hash := sha256.Sum256([]byte(hostID))
+	return hex.EncodeToString(hash[:])
+}
 
-	// Create file scanner
-	fileScanner := filescan.NewScanner(
-		cfg.FilesystemRoots,
-		cfg.ManifestPatterns,
-		cfg.MaxFileSize,
-		hostID,
-	)
+// runProbeOnce runs a single detection pass for p and publishes whatever it
+// finds, labeling metrics with p.Name() so procscan/filescan/containerscan
+// and any third-party probe are all observable the same way. Returns the
+// number of detections published.
+func runProbeOnce(ctx context.Context, p probe.Probe, pub *publisher.Publisher, logger logging.Logger) int {
+	logger.Debug("running probe", "probe", p.Name())
 
-	// Perform scan
-	detections, err := fileScanner.Scan()
+	detections, err := p.Scan(ctx)
 	if err != nil {
-		log.Printf("File scan error: %v", err)
-		metrics.ErrorsTotal.WithLabelValues("file_scan").Inc()
+		logger.Error("probe scan error", "probe", p.Name(), "error", err)
+		metrics.ScanErrorsTotal.WithLabelValues(p.Name()).Inc()
 		return 0
 	}
 
-	// Publish detections
 	publishedCount := 0
 	for _, detection := range detections {
 		if err := pub.PublishDetection(ctx, detection); err != nil {
-			log.Printf("Failed to publish file detection: %v", err)
-			metrics.ErrorsTotal.WithLabelValues("publish").Inc()
+			logger.Error("failed to publish detection", "probe", p.Name(), "error", err)
+			metrics.ScanErrorsTotal.WithLabelValues("publish").Inc()
 			continue
 		}
 		publishedCount++
-		metrics.EventPublishedTotal.WithLabelValues("file").Inc()
-		metrics.DetectionsFoundTotal.WithLabelValues("file").Inc()
+		metrics.EventPublishedTotal.WithLabelValues(p.Name()).Inc()
+		metrics.DetectionsFoundTotal.WithLabelValues(p.Name()).Inc()
 	}
 
-	log.Printf("File scan completed: %d detections found, %d published", len(detections), publishedCount)
+	logger.Info("probe completed", "probe", p.Name(), "found", len(detections), "published", publishedCount)
 	return publishedCount
 }
 
-// runProcessScan runs process scan
-func runProcessScan(ctx context.Context, cfg *config.FlatConfig, pub *publisher.Publisher, hostID string) int {
-	log.Println("Running process scan...")
-
-	// Create process scanner
-	procScanner := procscan.NewScanner(
-		cfg.ProcessPatterns,
-		cfg.PortPatterns,
-		cfg.MaxProcesses,
-		hostID,
-	)
-
-	// Perform scan
-	detections, err := procScanner.Scan()
-	if err != nil {
-		log.Printf("Process scan error: %v", err)
-		metrics.ErrorsTotal.WithLabelValues("process_scan").Inc()
-		return 0
-	}
-
-	// Publish detections
-	publishedCount := 0
-	for _, detection := range detections {
-		if err := pub.PublishDetection(ctx, detection); err != nil {
-			log.Printf("Failed to publish process detection: %v", err)
-			metrics.ErrorsTotal.WithLabelValues("publish").Inc()
+// runProbeWatchers starts probe.Watcher.Watch for every configured probe
+// with watch enabled, publishing each Detection as it arrives rather than
+// waiting for the next Scan cycle. A probe without watch enabled, or that
+// doesn't implement probe.Watcher, is left to the periodic Scan loop.
+func runProbeWatchers(ctx context.Context, probes []configuredProbe, pub *publisher.Publisher, logger logging.Logger) {
+	for _, cp := range probes {
+		if !cp.watch {
 			continue
 		}
-		publishedCount++
-		metrics.EventPublishedTotal.WithLabelValues("process").Inc()
-		metrics.DetectionsFoundTotal.WithLabelValues("process").Inc()
+		watcher, ok := cp.Probe.(probe.Watcher)
+		if !ok {
+			logger.Warn("probe configured for watch but doesn't support it, ignoring", "probe", cp.Name())
+			continue
+		}
+		go runProbeWatcher(ctx, cp.Name(), watcher, pub, logger)
 	}
+}
 
-	log.Printf("Process scan completed: %d detections found, %d published", len(detections), publishedCount)
-	return publishedCount
+// runProbeWatcher runs one probe's Watch for the lifetime of the process,
+// publishing detections as runProbeOnce does for periodic scans.
+func runProbeWatcher(ctx context.Context, name string, watcher probe.Watcher, pub *publisher.Publisher, logger logging.Logger) {
+	detections := make(chan *probe.Detection, 64)
+	go func() {
+		for detection := range detections {
+			if err := pub.PublishDetection(ctx, detection); err != nil {
+				logger.Error("failed to publish watched detection", "probe", name, "error", err)
+				metrics.ScanErrorsTotal.WithLabelValues("publish").Inc()
+				continue
+			}
+			metrics.EventPublishedTotal.WithLabelValues(name).Inc()
+			metrics.DetectionsFoundTotal.WithLabelValues(name).Inc()
+		}
+	}()
+
+	if err := watcher.Watch(ctx, detections); err != nil {
+		logger.Error("probe watcher stopped with error", "probe", name, "error", err)
+	}
+	close(detections)
 }
 
-// startMetricsServer starts Prometheus metrics HTTP server
-func startMetricsServer(port string) {
-	http.Handle("/metrics", promhttp.Handler())
-	log.Printf("Metrics server listening on %s", port)
-	if err := http.ListenAndServe(port, nil); err != nil {
-		log.Fatalf("Metrics server failed: %v", err)
+// startObservabilityServer builds and starts the merged metrics/health
+// server on port (see internal/httpserver), replacing the former separate
+// metrics and health listeners with a single lifecycle-managed one behind
+// cfg's TLS/mTLS and basic auth. It carries the pre-existing /health,
+// /ready, /version, and /-/reload routes alongside the new /metrics,
+// /healthz, and /readyz ones internal/httpserver.New adds. pub's broker
+// connection backs /ready as before; scanReadiness backs /readyz.
+func startObservabilityServer(ctx context.Context, port string, cfg httpserver.Config, pub *publisher.Publisher, watcher *config.Watcher, lastScan *atomic.Int64, logger logging.Logger) (*obsserver.Server, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", healthCheckHandler)
+	mux.HandleFunc("/ready", readinessCheckHandler(pub))
+	mux.HandleFunc("/version", versionHandler)
+	mux.HandleFunc("/-/reload", reloadHandler(watcher))
+
+	srv, err := obsserver.New(port, mux, cfg, scanReadiness(lastScan, watcher), logger.Named("obs-server"))
+	if err != nil {
+		return nil, fmt.Errorf("configure metrics/health server: %w", err)
 	}
+	srv.Start(ctx)
+	return srv, nil
 }
 
-// startHealthServer starts health check HTTP server
-func startHealthServer(port string) {
-	http.HandleFunc("/health", healthCheckHandler)
-	http.HandleFunc("/ready", readinessCheckHandler)
-	log.Printf("Health server listening on %s", port)
-	if err := http.ListenAndServe(port, nil); err != nil {
-		log.Fatalf("Health server failed: %v", err)
+// scanReadiness reports ready once at least one scan cycle has completed
+// within the last two scan intervals, per watcher's current config; a
+// scanner that has never completed a cycle, or has gone quiet for longer
+// than that, is not ready to be considered healthy by its orchestrator.
+func scanReadiness(lastScan *atomic.Int64, watcher *config.Watcher) obsserver.ReadyFunc {
+	return func(ctx context.Context) error {
+		last := lastScan.Load()
+		if last == 0 {
+			return fmt.Errorf("no scan cycle has completed yet")
+		}
+
+		maxAge := 2 * watcher.Current().ScanInterval
+		if maxAge <= 0 {
+			maxAge = 2 * defaultScanInterval
+		}
+		if age := time.Since(time.Unix(last, 0)); age > maxAge {
+			return fmt.Errorf("last successful scan was %s ago", age.Round(time.Second))
+		}
+		return nil
 	}
 }
 
 // healthCheckHandler handles liveness probe
 func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, "OK")
+	w.Write([]byte("OK"))
 }
 
-// readinessCheckHandler handles readiness probe
-func readinessCheckHandler(w http.ResponseWriter, r *http.Request) {
-	// Could check NATS connection, config loaded, etc.
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, "Ready")
+// readinessCheckHandler returns a readiness probe that reports healthy
+// only while pub's broker connection is up, rather than always returning
+// 200.
+func readinessCheckHandler(pub *publisher.Publisher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !pub.Connected() {
+			http.Error(w, "broker connection down", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Ready"))
+	}
+}
+
+// reloadHandler triggers the same validated config reload as SIGHUP and the
+// fsnotify watcher, for operators who'd rather hit an endpoint than signal
+// the process (e.g. from a Kubernetes postStart hook or CI).
+func reloadHandler(watcher *config.Watcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := watcher.Reload(); err != nil {
+			http.Error(w, "reload rejected: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("reloaded"))
+	}
+}
+
+// versionHandler returns the build metadata of the running binary as JSON.
+func versionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"version":    buildinfo.Version,
+		"git_commit": buildinfo.GitCommit,
+		"build_date": buildinfo.BuildDate,
+		"go_version": buildinfo.GoVersion,
+		"branch":     buildinfo.Branch,
+	})
 }
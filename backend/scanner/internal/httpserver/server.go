@@ -0,0 +1,81 @@
+// Package httpserver provides a single lifecycle-managed HTTP server that
+// exposes Prometheus metrics and Kubernetes-style liveness/readiness
+// endpoints, so main doesn't have to hand-roll a goroutine and a shutdown
+// path for every service it starts. It wraps pkg/httpserver for the actual
+// TLS/mTLS/basic-auth transport.
+package httpserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/httpserver"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/logging"
+)
+
+// ReadyFunc reports whether the service is ready to take traffic. A
+// returned error fails /readyz with its message as the response body.
+type ReadyFunc func(ctx context.Context) error
+
+// Server binds addr behind cfg's TLS/mTLS/basic auth (see pkg/httpserver)
+// and serves /metrics, /healthz, and /readyz on it.
+type Server struct {
+	inner  *httpserver.Server
+	addr   string
+	logger logging.Logger
+}
+
+// New builds a Server. mux is the caller's handler tree (e.g. already
+// carrying /health, /version, and any other service-specific routes);
+// /metrics, /healthz, and /readyz are added to it here. ready is polled on
+// every /readyz request; a nil ready always reports healthy.
+func New(addr string, mux *http.ServeMux, cfg httpserver.Config, ready ReadyFunc, logger logging.Logger) (*Server, error) {
+	if logger == nil {
+		logger = logging.Noop()
+	}
+	if ready == nil {
+		ready = func(context.Context) error { return nil }
+	}
+
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := ready(r.Context()); err != nil {
+			http.Error(w, "not ready: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+
+	inner, err := httpserver.New(addr, mux, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{inner: inner, addr: addr, logger: logger}, nil
+}
+
+// Start launches the listener and the basic-auth-file reload watcher (see
+// pkg/httpserver.Server.WatchForReload) in their own goroutines. A
+// ListenAndServe error other than the one Shutdown causes is logged, since
+// Start doesn't block for callers to check it themselves.
+func (s *Server) Start(ctx context.Context) {
+	go s.inner.WatchForReload(ctx)
+	go func() {
+		s.logger.Info("metrics/health server listening", "addr", s.addr)
+		if err := s.inner.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("metrics/health server failed", "error", err)
+		}
+	}()
+}
+
+// Shutdown gracefully stops the server, waiting for in-flight scrapes and
+// health checks to finish until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.inner.Shutdown(ctx)
+}
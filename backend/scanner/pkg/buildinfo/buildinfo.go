@@ -0,0 +1,25 @@
+// Package buildinfo carries the version metadata stamped into the scanner
+// binary at link time, so running instances can be identified precisely in
+// logs, metrics, and published events instead of relying on the free-form
+// GlobalConfig.Version string from YAML.
+package buildinfo
+
+import "runtime"
+
+// Version, GitCommit, BuildDate, and Branch are populated via
+// `-ldflags "-X ..."` at build time (see the Makefile's `build` target).
+// They default to "dev"/"unknown" for local `go build`/`go run` invocations.
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildDate = "unknown"
+	Branch    = "unknown"
+)
+
+// GoVersion is the Go toolchain version the binary was compiled with.
+var GoVersion = runtime.Version()
+
+// String returns a one-line summary suitable for a startup banner.
+func String() string {
+	return "version=" + Version + " commit=" + GitCommit + " built=" + BuildDate + " branch=" + Branch + " go=" + GoVersion
+}
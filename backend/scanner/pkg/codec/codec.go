@@ -0,0 +1,49 @@
+// Package codec provides a pluggable wire format for detection events so a
+// deployment can trade encoding/json's convenience for a smaller, faster
+// format on high-volume endpoints without touching Publisher call sites.
+package codec
+
+import "fmt"
+
+// Codec encodes and decodes detection events for transport over a
+// MessageBroker.
+type Codec interface {
+	// Name identifies the codec, e.g. for the event-codec broker header.
+	Name() string
+	Encode(v any) ([]byte, error)
+	Decode(data []byte, v any) error
+}
+
+var registry = map[string]Codec{}
+
+func init() {
+	Register(JSONCodec{})
+	Register(ProtobufCodec{})
+	Register(CBORCodec{})
+}
+
+// Register adds a codec to the registry, keyed by its Name(). Re-registering
+// a name replaces the previous codec.
+func Register(c Codec) {
+	registry[c.Name()] = c
+}
+
+// Get looks up a codec by name, defaulting to the JSON codec for an empty
+// name so callers that never configured a codec keep today's behavior.
+func Get(name string) (Codec, error) {
+	if name == "" {
+		name = "json"
+	}
+	c, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown codec: %q", name)
+	}
+	return c, nil
+}
+
+// SupportsSchemaValidation reports whether c's output can be checked against
+// a JSON Schema. Only the "json" codec can; non-JSON codecs (protobuf, cbor)
+// must be skipped rather than schema-validated.
+func SupportsSchemaValidation(c Codec) bool {
+	return c.Name() == "json"
+}
@@ -0,0 +1,186 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers from detection_event.proto. Keep in sync with that file.
+const (
+	fieldEventID       = 1
+	fieldTimestamp     = 2
+	fieldHostID        = 3
+	fieldDetectionType = 4
+	fieldScore         = 5
+	fieldEvidenceJSON  = 6
+)
+
+// detectionEvent is the Go-side mirror of detection_event.proto's
+// DetectionEvent message.
+type detectionEvent struct {
+	EventID           string
+	TimestampUnixNano int64
+	HostID            string
+	DetectionType     string
+	Score             int32
+	EvidenceJSON      []byte
+}
+
+// jsonEvent is the intermediate shape ProtobufCodec bridges through: any
+// detection type scanner/correlator uses already marshals to this JSON
+// shape (see filescan.Detection, procscan.Detection, engine.DetectionEvent),
+// so Encode/Decode can work with all of them without importing their
+// concrete types.
+type jsonEvent struct {
+	EventID       string          `json:"event_id"`
+	Timestamp     time.Time       `json:"timestamp"`
+	HostID        string          `json:"host_id"`
+	DetectionType string          `json:"detection_type"`
+	Score         int             `json:"score"`
+	Evidence      json.RawMessage `json:"evidence"`
+}
+
+// ProtobufCodec encodes detection events using the wire format described in
+// detection_event.proto, cutting bandwidth and marshalling cost relative to
+// JSON on high-volume endpoints.
+type ProtobufCodec struct{}
+
+// Name implements Codec.
+func (ProtobufCodec) Name() string { return "protobuf" }
+
+// Encode implements Codec. v must marshal to JSON matching jsonEvent's
+// shape (event_id, timestamp, host_id, detection_type, score, evidence).
+func (ProtobufCodec) Encode(v any) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("protobuf codec: marshal intermediate JSON: %w", err)
+	}
+
+	var je jsonEvent
+	if err := json.Unmarshal(raw, &je); err != nil {
+		return nil, fmt.Errorf("protobuf codec: decode intermediate JSON: %w", err)
+	}
+
+	return marshalDetectionEvent(&detectionEvent{
+		EventID:           je.EventID,
+		TimestampUnixNano: je.Timestamp.UnixNano(),
+		HostID:            je.HostID,
+		DetectionType:     je.DetectionType,
+		Score:             int32(je.Score),
+		EvidenceJSON:      je.Evidence,
+	}), nil
+}
+
+// Decode implements Codec, populating v via the same intermediate JSON
+// shape Encode reads from.
+func (ProtobufCodec) Decode(data []byte, v any) error {
+	evt, err := unmarshalDetectionEvent(data)
+	if err != nil {
+		return fmt.Errorf("protobuf codec: %w", err)
+	}
+
+	raw, err := json.Marshal(jsonEvent{
+		EventID:       evt.EventID,
+		Timestamp:     time.Unix(0, evt.TimestampUnixNano).UTC(),
+		HostID:        evt.HostID,
+		DetectionType: evt.DetectionType,
+		Score:         int(evt.Score),
+		Evidence:      evt.EvidenceJSON,
+	})
+	if err != nil {
+		return fmt.Errorf("protobuf codec: encode intermediate JSON: %w", err)
+	}
+
+	return json.Unmarshal(raw, v)
+}
+
+// marshalDetectionEvent writes e in the protobuf wire format described by
+// detection_event.proto.
+func marshalDetectionEvent(e *detectionEvent) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, fieldEventID, protowire.BytesType)
+	b = protowire.AppendString(b, e.EventID)
+	b = protowire.AppendTag(b, fieldTimestamp, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(e.TimestampUnixNano))
+	b = protowire.AppendTag(b, fieldHostID, protowire.BytesType)
+	b = protowire.AppendString(b, e.HostID)
+	b = protowire.AppendTag(b, fieldDetectionType, protowire.BytesType)
+	b = protowire.AppendString(b, e.DetectionType)
+	b = protowire.AppendTag(b, fieldScore, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(int64(e.Score)))
+	if len(e.EvidenceJSON) > 0 {
+		b = protowire.AppendTag(b, fieldEvidenceJSON, protowire.BytesType)
+		b = protowire.AppendBytes(b, e.EvidenceJSON)
+	}
+	return b
+}
+
+// unmarshalDetectionEvent parses the wire format written by
+// marshalDetectionEvent, ignoring any unknown fields.
+func unmarshalDetectionEvent(data []byte) (*detectionEvent, error) {
+	e := &detectionEvent{}
+
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, fmt.Errorf("invalid tag: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		switch num {
+		case fieldEventID:
+			s, n := protowire.ConsumeString(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid event_id: %w", protowire.ParseError(n))
+			}
+			e.EventID = s
+			data = data[n:]
+		case fieldTimestamp:
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid timestamp_unix_nano: %w", protowire.ParseError(n))
+			}
+			e.TimestampUnixNano = int64(v)
+			data = data[n:]
+		case fieldHostID:
+			s, n := protowire.ConsumeString(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid host_id: %w", protowire.ParseError(n))
+			}
+			e.HostID = s
+			data = data[n:]
+		case fieldDetectionType:
+			s, n := protowire.ConsumeString(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid detection_type: %w", protowire.ParseError(n))
+			}
+			e.DetectionType = s
+			data = data[n:]
+		case fieldScore:
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid score: %w", protowire.ParseError(n))
+			}
+			e.Score = int32(v)
+			data = data[n:]
+		case fieldEvidenceJSON:
+			v, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid evidence_json: %w", protowire.ParseError(n))
+			}
+			e.EvidenceJSON = append([]byte(nil), v...)
+			data = data[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid field %d: %w", num, protowire.ParseError(n))
+			}
+			data = data[n:]
+		}
+	}
+
+	return e, nil
+}
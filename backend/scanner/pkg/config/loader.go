@@ -1,4 +1,7 @@
 // Package config provides YAML configuration loading for the scanner service.
+// Every global.yaml/scanner.yaml is validated against an embedded JSON Schema
+// (see validate.go) before it is unmarshalled, so malformed config fails fast
+// with every violation listed, not just the first.
 // Reference: FR-015 (declarative YAML configuration), FR-016 (JSON Schema validation)
 package config
 
@@ -8,6 +11,11 @@ import (
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/containerscan"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/httpserver"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/proctrace"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/tracing"
 )
 
 // Config represents the scanner service configuration
@@ -21,6 +29,12 @@ type Config struct {
 	// NATS connection settings
 	NATS NATSConfig `yaml:"nats"`
 
+	// Message broker backend selection
+	Broker BrokerConfig `yaml:"broker"`
+
+	// Event wire format selection
+	Codec CodecConfig `yaml:"codec"`
+
 	// Observability settings
 	Observability ObservabilityConfig `yaml:"observability"`
 }
@@ -29,6 +43,7 @@ type Config struct {
 type GlobalConfig struct {
 	Environment string `yaml:"environment"` // dev, staging, prod
 	LogLevel    string `yaml:"log_level"`   // debug, info, warn, error
+	LogFormat   string `yaml:"log_format"`  // text, json
 	Version     string `yaml:"version"`     // Service version
 }
 
@@ -51,19 +66,104 @@ type ScannerConfig struct {
 
 	// Manifest file patterns to search for
 	ManifestPatterns []string `yaml:"manifest_patterns"`
+
+	// Container-aware scanning (see pkg/containerscan). Disabled by
+	// default since it requires engine socket access.
+	ContainerScan ContainerScanConfig `yaml:"container_scan"`
+
+	// Real-time eBPF process tracing (see pkg/proctrace). Disabled by
+	// default since it requires CAP_BPF/CAP_PERFMON and a 4.18+ kernel.
+	ProcTrace ProcTraceConfig `yaml:"proctrace"`
+
+	// Incremental scan state (see pkg/filescan.Scanner.State). Disabled
+	// by default, in which case every cycle rehashes every manifest file
+	// it finds, matching pre-incremental-mode behavior.
+	Incremental IncrementalConfig `yaml:"incremental"`
+
+	// Probes maps a pkg/probe registry name (e.g. "filescan", "procscan",
+	// "containerscan", or a third-party probe blank-imported by a custom
+	// build) to its enabled/interval/patterns settings. A probe missing
+	// from this map keeps running with the legacy defaults below, so
+	// existing scanner.yaml files don't need to list every built-in probe.
+	Probes map[string]ProbeConfig `yaml:"probes"`
+}
+
+// ProbeConfig is one entry in Probes. See pkg/probe.Config, which this
+// mirrors plus the string Interval this unmarshals from YAML.
+type ProbeConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Interval string   `yaml:"interval"`
+	Patterns []string `yaml:"patterns"`
+
+	// Detectors narrows a probe's pluggable sub-detectors (currently
+	// only filescan's ManifestDetectors; see
+	// pkg/filescan.Scanner.EnableDetectors). Empty keeps the probe's
+	// default set enabled.
+	Detectors []string `yaml:"detectors"`
+
+	// Watch starts a probe's continuous, event-driven mode (see
+	// probe.Watcher) alongside its periodic Scan. Ignored by probes that
+	// don't implement probe.Watcher.
+	Watch bool `yaml:"watch"`
+}
+
+// IncrementalConfig controls pkg/filescan's persistent per-file state,
+// which lets Scan skip files unchanged since the last cycle instead of
+// rereading and rehashing them every time.
+type IncrementalConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// StateDBPath is the BoltDB file the state is persisted to. Required
+	// when Enabled is true.
+	StateDBPath string `yaml:"state_db_path"`
+}
+
+// ProcTraceConfig controls pkg/proctrace, which streams exec/bind events
+// straight from the kernel via eBPF as a low-latency complement to the
+// polling-based process scan.
+type ProcTraceConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// ContainerScanConfig controls pkg/containerscan, which inspects running
+// Docker/Podman containers for MCP servers invisible to the host-only
+// filesystem and process scans.
+type ContainerScanConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	DockerSocket string `yaml:"docker_socket"`
+	PodmanSocket string `yaml:"podman_socket"`
 }
 
 // NATSConfig contains NATS JetStream connection settings
 type NATSConfig struct {
-	URL             string        `yaml:"url"`              // NATS server URL
-	Subject         string        `yaml:"subject"`          // Subject to publish to
-	MaxReconnects   int           `yaml:"max_reconnects"`   // Max reconnection attempts
-	ReconnectWait   time.Duration `yaml:"reconnect_wait"`   // Wait time between reconnects
-	Timeout         time.Duration `yaml:"timeout"`          // Connection timeout
-	TLSEnabled      bool          `yaml:"tls_enabled"`      // Enable mTLS
-	TLSCertFile     string        `yaml:"tls_cert_file"`    // Client certificate
-	TLSKeyFile      string        `yaml:"tls_key_file"`     // Client key
-	TLSCAFile       string        `yaml:"tls_ca_file"`      // CA certificate
+	URL           string        `yaml:"url"`            // NATS server URL
+	Subject       string        `yaml:"subject"`        // Subject to publish to
+	MaxReconnects int           `yaml:"max_reconnects"` // Max reconnection attempts
+	ReconnectWait time.Duration `yaml:"reconnect_wait"` // Wait time between reconnects
+	Timeout       time.Duration `yaml:"timeout"`        // Connection timeout
+	TLSEnabled    bool          `yaml:"tls_enabled"`    // Enable mTLS
+	TLSCertFile   string        `yaml:"tls_cert_file"`  // Client certificate
+	TLSKeyFile    string        `yaml:"tls_key_file"`   // Client key
+	TLSCAFile     string        `yaml:"tls_ca_file"`    // CA certificate
+	NKeySeedFile  string        `yaml:"nkey_seed_file"` // NKey seed file for NATS auth
+	CredsFile     string        `yaml:"creds_file"`     // NATS JWT+NKey credentials file
+	Token         string        `yaml:"token"`          // Plain NATS auth token
+}
+
+// BrokerConfig selects the message broker backend and holds backend-specific
+// settings not already covered by NATSConfig.
+type BrokerConfig struct {
+	Type string `yaml:"type"` // "nats" (default) or "amqp"
+
+	AMQPUrl        string `yaml:"amqp_url"`
+	AMQPExchange   string `yaml:"amqp_exchange"`
+	AMQPRoutingKey string `yaml:"amqp_routing_key"`
+	AMQPPersistent bool   `yaml:"amqp_persistent"`
+}
+
+// CodecConfig selects the wire format for published detection events.
+type CodecConfig struct {
+	Name string `yaml:"name"` // "json" (default), "protobuf", or "cbor"
 }
 
 // ObservabilityConfig contains metrics and logging settings
@@ -76,6 +176,31 @@ type ObservabilityConfig struct {
 
 	// Health check port
 	HealthCheckPort int `yaml:"health_check_port"`
+
+	// TLS/mTLS and HTTP basic auth for the metrics and health endpoints
+	// (see pkg/httpserver). Leaving these unset serves plain HTTP,
+	// matching existing deployments.
+	TLSCertFile        string `yaml:"tls_cert_file"`
+	TLSKeyFile         string `yaml:"tls_key_file"`
+	TLSCAFile          string `yaml:"tls_ca_file"`
+	ClientAuth         string `yaml:"client_auth"`
+	BasicAuthUsersFile string `yaml:"basic_auth_users_file"`
+
+	// Distributed tracing (see pkg/tracing). Leaving Tracing.Enabled
+	// false keeps the default no-op TracerProvider, matching existing
+	// deployments.
+	Tracing TracingConfig `yaml:"tracing"`
+}
+
+// TracingConfig configures the scanner's OpenTelemetry OTLP exporter. See
+// pkg/tracing.Config, which this mirrors field-for-field.
+type TracingConfig struct {
+	Enabled      bool              `yaml:"enabled"`
+	Endpoint     string            `yaml:"endpoint"`
+	Protocol     string            `yaml:"protocol"`
+	Insecure     bool              `yaml:"insecure"`
+	Headers      map[string]string `yaml:"headers"`
+	SamplerRatio float64           `yaml:"sampler_ratio"`
 }
 
 // LoadConfig loads configuration from YAML files.
@@ -92,12 +217,18 @@ func LoadConfig(configDir string) (*Config, error) {
 
 	// Load global configuration
 	globalPath := fmt.Sprintf("%s/global.yaml", configDir)
+	if err := ValidateFile(globalPath); err != nil {
+		return nil, err
+	}
 	if err := loadYAMLFile(globalPath, config); err != nil {
 		return nil, fmt.Errorf("failed to load global.yaml: %w", err)
 	}
 
 	// Load scanner-specific configuration
 	scannerPath := fmt.Sprintf("%s/scanner.yaml", configDir)
+	if err := ValidateFile(scannerPath); err != nil {
+		return nil, err
+	}
 	if err := loadYAMLFile(scannerPath, config); err != nil {
 		return nil, fmt.Errorf("failed to load scanner.yaml: %w", err)
 	}
@@ -213,6 +344,81 @@ func (c *Config) GetHealthPort() string {
 	return fmt.Sprintf(":%d", c.Observability.HealthCheckPort)
 }
 
+// GetHTTPServerConfig returns the TLS/mTLS and basic auth configuration for
+// the metrics and health servers (see pkg/httpserver). The zero value
+// serves plain HTTP with no auth, matching existing deployments.
+func (c *Config) GetHTTPServerConfig() httpserver.Config {
+	return httpserver.Config{
+		TLSCertFile:        c.Observability.TLSCertFile,
+		TLSKeyFile:         c.Observability.TLSKeyFile,
+		TLSCAFile:          c.Observability.TLSCAFile,
+		ClientAuth:         httpserver.ClientAuthType(c.Observability.ClientAuth),
+		BasicAuthUsersFile: c.Observability.BasicAuthUsersFile,
+	}
+}
+
+// GetContainerScanConfig returns pkg/containerscan's configuration. The
+// zero value leaves container scanning disabled, matching existing
+// deployments.
+func (c *Config) GetContainerScanConfig() containerscan.Config {
+	return containerscan.Config{
+		Enabled:          c.Scanner.ContainerScan.Enabled,
+		DockerSocket:     c.Scanner.ContainerScan.DockerSocket,
+		PodmanSocket:     c.Scanner.ContainerScan.PodmanSocket,
+		ManifestPatterns: c.Scanner.ManifestPatterns,
+		MaxFileSizeBytes: c.Scanner.MaxFileSizeBytes,
+	}
+}
+
+// GetProcTraceConfig returns pkg/proctrace's configuration. MCPPatterns is
+// filled in by Flatten from the same process patterns procscan uses, so
+// both detection paths classify processes identically.
+func (c *Config) GetProcTraceConfig() proctrace.Config {
+	return proctrace.Config{
+		Enabled: c.Scanner.ProcTrace.Enabled,
+	}
+}
+
+// defaultProbeConfigs describes each built-in probe's settings absent an
+// explicit `probes` entry, so existing scanner.yaml files keep today's
+// behavior (procscan/filescan always on, containerscan following its own
+// container_scan.enabled flag) without listing every probe by name. An
+// empty Interval means "use the scanner-wide ScanInterval".
+func (c *Config) defaultProbeConfigs(processPatterns []string) map[string]ProbeConfig {
+	return map[string]ProbeConfig{
+		"procscan":      {Enabled: true, Patterns: processPatterns},
+		"filescan":      {Enabled: true, Patterns: c.Scanner.ManifestPatterns},
+		"containerscan": {Enabled: c.Scanner.ContainerScan.Enabled, Patterns: c.Scanner.ManifestPatterns},
+	}
+}
+
+// GetProbeConfigs returns the raw Probes settings for every built-in probe,
+// overlaying any explicit `probes` entries from scanner.yaml onto the
+// defaults above. FilesystemRoots/MaxFileSize/Logger aren't part of the
+// `probes` YAML map; cmd/scanner fills those into probe.Config after
+// Flatten, since only it has a logger.
+func (c *Config) GetProbeConfigs(processPatterns []string) map[string]ProbeConfig {
+	probes := c.defaultProbeConfigs(processPatterns)
+	for name, override := range c.Scanner.Probes {
+		probes[name] = override
+	}
+	return probes
+}
+
+// GetTracingConfig returns the OpenTelemetry tracing configuration (see
+// pkg/tracing). The zero value leaves tracing disabled, matching existing
+// deployments.
+func (c *Config) GetTracingConfig() tracing.Config {
+	return tracing.Config{
+		Enabled:      c.Observability.Tracing.Enabled,
+		Endpoint:     c.Observability.Tracing.Endpoint,
+		Protocol:     c.Observability.Tracing.Protocol,
+		Insecure:     c.Observability.Tracing.Insecure,
+		Headers:      c.Observability.Tracing.Headers,
+		SamplerRatio: c.Observability.Tracing.SamplerRatio,
+	}
+}
+
 // Flattened config for easier access
 type FlatConfig struct {
 	ScanInterval           time.Duration
@@ -223,9 +429,30 @@ type FlatConfig struct {
 	MaxFileSize            int64
 	MaxProcesses           int
 	NATSUrl                string
+	NATSTLSEnabled         bool
+	NATSTLSCertFile        string
+	NATSTLSKeyFile         string
+	NATSTLSCAFile          string
+	NATSNKeySeedFile       string
+	NATSCredsFile          string
+	NATSToken              string
+	BrokerType             string
+	AMQPUrl                string
+	AMQPExchange           string
+	AMQPRoutingKey         string
+	AMQPPersistent         bool
+	Codec                  string
 	EnableSchemaValidation bool
 	MetricsPort            string
 	HealthPort             string
+	HTTPServer             httpserver.Config
+	Tracing                tracing.Config
+	ContainerScan          containerscan.Config
+	ProcTrace              proctrace.Config
+	Incremental            IncrementalConfig
+	Probes                 map[string]ProbeConfig
+	LogLevel               string
+	LogFormat              string
 }
 
 // Flatten converts nested config to flat structure
@@ -235,15 +462,23 @@ func (c *Config) Flatten() (*FlatConfig, error) {
 		return nil, err
 	}
 
+	processPatterns := []string{
+		`mcp.*server`,
+		`.*mcp.*`,
+		`stdio.*mcp`,
+	}
+
+	containerScanCfg := c.GetContainerScanConfig()
+	containerScanCfg.ProcessPatterns = processPatterns
+
+	procTraceCfg := c.GetProcTraceConfig()
+	procTraceCfg.MCPPatterns = processPatterns
+
 	return &FlatConfig{
 		ScanInterval:     interval,
 		FilesystemRoots:  c.Scanner.FilesystemRoots,
 		ManifestPatterns: c.Scanner.ManifestPatterns,
-		ProcessPatterns: []string{
-			`mcp.*server`,
-			`.*mcp.*`,
-			`stdio.*mcp`,
-		},
+		ProcessPatterns:  processPatterns,
 		PortPatterns: []string{
 			`--port[=\s]+(\d+)`,
 			`-p[=\s]+(\d+)`,
@@ -251,8 +486,45 @@ func (c *Config) Flatten() (*FlatConfig, error) {
 		MaxFileSize:            c.Scanner.MaxFileSizeBytes,
 		MaxProcesses:           c.GetMaxProcesses(),
 		NATSUrl:                c.NATS.URL,
+		NATSTLSEnabled:         c.NATS.TLSEnabled,
+		NATSTLSCertFile:        c.NATS.TLSCertFile,
+		NATSTLSKeyFile:         c.NATS.TLSKeyFile,
+		NATSTLSCAFile:          c.NATS.TLSCAFile,
+		NATSNKeySeedFile:       c.NATS.NKeySeedFile,
+		NATSCredsFile:          c.NATS.CredsFile,
+		NATSToken:              c.NATS.Token,
+		BrokerType:             c.Broker.Type,
+		AMQPUrl:                c.Broker.AMQPUrl,
+		AMQPExchange:           c.Broker.AMQPExchange,
+		AMQPRoutingKey:         c.Broker.AMQPRoutingKey,
+		AMQPPersistent:         c.Broker.AMQPPersistent,
+		Codec:                  c.Codec.Name,
 		EnableSchemaValidation: true,
 		MetricsPort:            c.GetMetricsPort(),
 		HealthPort:             c.GetHealthPort(),
+		HTTPServer:             c.GetHTTPServerConfig(),
+		Tracing:                c.GetTracingConfig(),
+		ContainerScan:          containerScanCfg,
+		ProcTrace:              procTraceCfg,
+		Incremental:            c.Scanner.Incremental,
+		Probes:                 c.GetProbeConfigs(processPatterns),
+		LogLevel:               c.GetLogLevel(),
+		LogFormat:              c.GetLogFormat(),
 	}, nil
 }
+
+// GetLogLevel returns the configured log level, defaulting to "info".
+func (c *Config) GetLogLevel() string {
+	if c.Global.LogLevel == "" {
+		return "info"
+	}
+	return c.Global.LogLevel
+}
+
+// GetLogFormat returns the configured log format, defaulting to "text".
+func (c *Config) GetLogFormat() string {
+	if c.Global.LogFormat == "" {
+		return "text"
+	}
+	return c.Global.LogFormat
+}
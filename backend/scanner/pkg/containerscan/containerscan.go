@@ -0,0 +1,469 @@
+// Package containerscan discovers MCP servers running inside Docker and
+// Podman containers on the local node. pkg/filescan only walks
+// FilesystemRoots and pkg/procscan only reads the host's /proc, so neither
+// sees into a container's mount or PID namespace; this package talks to
+// the container engine's own API instead.
+// Reference: FR-017 (12-hour scan cycle), FR-018 (process/filesystem detection), US1
+package containerscan
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/buildinfo"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/filescan"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/logging"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/metrics"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/procscan"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/tracing"
+)
+
+// Engine names a container runtime whose API this package speaks (Podman's
+// API is a compatible subset of Docker's, so both use the same client).
+type Engine string
+
+const (
+	EngineDocker Engine = "docker"
+	EnginePodman Engine = "podman"
+)
+
+const (
+	defaultDockerSocket = "/var/run/docker.sock"
+	manifestSearchLimit = 1024 // bytes read per manifest candidate, matching filescan's FR-009 snippet cap
+)
+
+// manifestSearchDirs are the directories probed for each ManifestPatterns
+// filename. Containers don't expose a filesystem walk over the engine API,
+// so candidates are bounded to common app roots rather than a full tree.
+var manifestSearchDirs = []string{"/", "/app", "/usr/src/app", "/workspace", "/srv"}
+
+// Config controls whether/how the scanner inspects running containers.
+type Config struct {
+	// Enabled turns on container scanning. Disabled by default since it
+	// requires engine socket access the scanner process may not have.
+	Enabled bool
+
+	// DockerSocket overrides the default Docker socket path
+	// (/var/run/docker.sock).
+	DockerSocket string
+
+	// PodmanSocket overrides the default rootless Podman socket path
+	// ($XDG_RUNTIME_DIR/podman/podman.sock). Empty disables Podman
+	// discovery unless XDG_RUNTIME_DIR is set.
+	PodmanSocket string
+
+	ManifestPatterns []string
+	ProcessPatterns  []string
+	MaxFileSizeBytes int64
+}
+
+// Scanner enumerates containers on every reachable engine socket and
+// produces filescan/procscan-shaped detections, annotated with the
+// container_id, image, image_digest, and pod_id that produced them.
+type Scanner struct {
+	cfg    Config
+	hostID string
+	logger logging.Logger
+}
+
+// NewScanner creates a container scanner. A nil logger falls back to a
+// discarding logger.
+func NewScanner(cfg Config, hostID string, logger logging.Logger) *Scanner {
+	if logger == nil {
+		logger = logging.Noop()
+	}
+	return &Scanner{cfg: cfg, hostID: hostID, logger: logger}
+}
+
+// socketCandidate pairs an engine with the socket path it listens on.
+type socketCandidate struct {
+	engine Engine
+	path   string
+}
+
+// sockets returns the engine sockets to probe, in a stable order.
+func (s *Scanner) sockets() []socketCandidate {
+	dockerSocket := s.cfg.DockerSocket
+	if dockerSocket == "" {
+		dockerSocket = defaultDockerSocket
+	}
+
+	podmanSocket := s.cfg.PodmanSocket
+	if podmanSocket == "" {
+		if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+			podmanSocket = runtimeDir + "/podman/podman.sock"
+		}
+	}
+
+	candidates := []socketCandidate{{EngineDocker, dockerSocket}}
+	if podmanSocket != "" {
+		candidates = append(candidates, socketCandidate{EnginePodman, podmanSocket})
+	}
+	return candidates
+}
+
+// Scan enumerates containers on every reachable engine socket. A socket
+// that doesn't exist (engine not installed/running) is silently skipped,
+// not an error, per this package's no-op-when-absent contract.
+func (s *Scanner) Scan(ctx context.Context) ([]*filescan.Detection, []*procscan.Detection, error) {
+	if !s.cfg.Enabled {
+		return nil, nil, nil
+	}
+
+	var fileDetections []*filescan.Detection
+	var procDetections []*procscan.Detection
+
+	for _, sock := range s.sockets() {
+		if _, err := os.Stat(sock.path); err != nil {
+			s.logger.Debug("container engine socket not present, skipping", "engine", sock.engine, "socket", sock.path)
+			continue
+		}
+
+		client := newEngineClient(sock.path)
+		containers, err := client.listContainers(ctx)
+		if err != nil {
+			s.logger.Warn("failed to list containers", "engine", sock.engine, "error", err)
+			metrics.ScanErrorsTotal.WithLabelValues("container_scan").Inc()
+			continue
+		}
+
+		metrics.MCPContainersScannedTotal.WithLabelValues(string(sock.engine)).Add(float64(len(containers)))
+
+		for _, container := range containers {
+			fd, pd := s.scanContainer(ctx, client, sock.engine, container)
+			fileDetections = append(fileDetections, fd...)
+			procDetections = append(procDetections, pd...)
+		}
+	}
+
+	return fileDetections, procDetections, nil
+}
+
+// scanContainer inspects a single container for MCP-matching processes and
+// manifest files, tagging every detection with the container identity it
+// came from.
+func (s *Scanner) scanContainer(ctx context.Context, client *engineClient, engine Engine, container containerSummary) ([]*filescan.Detection, []*procscan.Detection) {
+	ctx, span := tracing.Tracer.Start(ctx, "containerscan.scanContainer")
+	defer span.End()
+
+	inspect, err := client.inspectContainer(ctx, container.ID)
+	if err != nil {
+		s.logger.Warn("failed to inspect container", "container_id", container.ID, "error", err)
+		return nil, nil
+	}
+
+	identity := containerIdentity{
+		ContainerID: container.ID,
+		Image:       inspect.Config.Image,
+		ImageDigest: inspect.Image,
+		PodID:       podIDFromLabels(inspect.Config.Labels),
+	}
+
+	procDetections := s.scanContainerProcesses(ctx, client, engine, container.ID, identity)
+	fileDetections := s.scanContainerManifests(ctx, client, engine, container.ID, identity)
+
+	return fileDetections, procDetections
+}
+
+// containerIdentity is the container metadata every detection produced by
+// this package carries, so the correlator can extend GenerateCompositeID
+// with it (see backend/correlator's pkg/identifier).
+type containerIdentity struct {
+	ContainerID string
+	Image       string
+	ImageDigest string
+	PodID       string
+}
+
+func (id containerIdentity) metadata() map[string]interface{} {
+	return map[string]interface{}{
+		"container_id": id.ContainerID,
+		"image":        id.Image,
+		"image_digest": id.ImageDigest,
+		"pod_id":       id.PodID,
+	}
+}
+
+// podIDFromLabels looks for the pod identity under whichever label the
+// orchestrator set: Kubernetes' CRI labels or Podman's own pod annotation.
+func podIDFromLabels(labels map[string]string) string {
+	for _, key := range []string{"io.kubernetes.pod.uid", "io.podman.annotations.pod.id"} {
+		if v := labels[key]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// scanContainerProcesses lists the container's running processes via the
+// engine API and matches their command lines against ProcessPatterns,
+// producing procscan-shaped detections namespaced to the container.
+func (s *Scanner) scanContainerProcesses(ctx context.Context, client *engineClient, engine Engine, containerID string, identity containerIdentity) []*procscan.Detection {
+	cmdlines, err := client.topCommandLines(ctx, containerID)
+	if err != nil {
+		s.logger.Warn("failed to list container processes", "container_id", containerID, "error", err)
+		return nil
+	}
+
+	var detections []*procscan.Detection
+	for _, cmdline := range cmdlines {
+		if !matchesAny(s.cfg.ProcessPatterns, cmdline) {
+			continue
+		}
+
+		metadata := identity.metadata()
+		hash := sha256.Sum256([]byte(cmdline))
+
+		detections = append(detections, &procscan.Detection{
+			EventID:       uuid.New().String(),
+			Timestamp:     time.Now().UTC(),
+			HostID:        s.hostID,
+			DetectionType: "process",
+			Score:         60, // fixed score, not derived from procscan's or filescan's calculateScore; container visibility alone doesn't confirm a live endpoint
+			Evidence: procscan.Evidence{
+				Source:      "containerscan-" + string(engine) + "-" + buildinfo.Version,
+				CommandLine: cmdline,
+				ProcessHash: hex.EncodeToString(hash[:]),
+				Snippet:     truncate(cmdline, manifestSearchLimit),
+				Metadata:    metadata,
+			},
+		})
+	}
+	return detections
+}
+
+// scanContainerManifests copies manifest file candidates out of the
+// container's rootfs via the engine's archive endpoint and hashes them the
+// same way pkg/filescan does for host files.
+func (s *Scanner) scanContainerManifests(ctx context.Context, client *engineClient, engine Engine, containerID string, identity containerIdentity) []*filescan.Detection {
+	var detections []*filescan.Detection
+
+	for _, dir := range manifestSearchDirs {
+		for _, pattern := range s.cfg.ManifestPatterns {
+			filename := manifestFilename(pattern)
+			if filename == "" {
+				continue
+			}
+			containerPath := joinContainerPath(dir, filename)
+
+			content, err := client.copyFile(ctx, containerID, containerPath, s.cfg.MaxFileSizeBytes)
+			if err != nil {
+				continue // candidate absent in this container, not an error
+			}
+
+			var manifest map[string]interface{}
+			if err := json.Unmarshal(content, &manifest); err != nil {
+				continue
+			}
+
+			hash := sha256.Sum256(content)
+			metadata := identity.metadata()
+			metadata["file_path"] = containerPath
+
+			detections = append(detections, &filescan.Detection{
+				EventID:       uuid.New().String(),
+				Timestamp:     time.Now().UTC(),
+				HostID:        s.hostID,
+				DetectionType: "file",
+				Score:         80, // fixed score for a JSON manifest found in a container, not derived from filescan's calculateScore
+				Evidence: filescan.Evidence{
+					Source:   "containerscan-" + string(engine) + "-" + buildinfo.Version,
+					FilePath: containerID + ":" + containerPath,
+					FileHash: hex.EncodeToString(hash[:]),
+					Snippet:  truncate(string(content), manifestSearchLimit),
+					Metadata: metadata,
+				},
+			})
+		}
+	}
+
+	return detections
+}
+
+func manifestFilename(pattern string) string {
+	if idx := strings.LastIndex(pattern, "/"); idx >= 0 {
+		return pattern[idx+1:]
+	}
+	return pattern
+}
+
+func joinContainerPath(dir, filename string) string {
+	if strings.HasSuffix(dir, "/") {
+		return dir + filename
+	}
+	return dir + "/" + filename
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+func matchesAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if strings.Contains(strings.ToLower(s), strings.ToLower(strings.Trim(p, ".*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// containerSummary is the subset of the engine API's /containers/json
+// response this package needs.
+type containerSummary struct {
+	ID string `json:"Id"`
+}
+
+// inspectResponse is the subset of /containers/{id}/json this package
+// needs: the image reference, its resolved digest, and orchestrator labels.
+type inspectResponse struct {
+	Image  string `json:"Image"` // resolved image ID/digest
+	Config struct {
+		Image  string            `json:"Image"` // image reference as run, e.g. "mcp-server:latest"
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+}
+
+// engineClient speaks the Docker Engine API over a unix socket. Podman's
+// API is Docker-compatible for the endpoints used here, so one client
+// serves both.
+type engineClient struct {
+	httpClient *http.Client
+}
+
+func newEngineClient(socketPath string) *engineClient {
+	return &engineClient{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+func (c *engineClient) get(ctx context.Context, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://unix"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("engine API %s returned %s", path, resp.Status)
+	}
+	return resp, nil
+}
+
+func (c *engineClient) listContainers(ctx context.Context) ([]containerSummary, error) {
+	resp, err := c.get(ctx, "/containers/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var containers []containerSummary
+	if err := json.NewDecoder(resp.Body).Decode(&containers); err != nil {
+		return nil, fmt.Errorf("failed to decode containers list: %w", err)
+	}
+	return containers, nil
+}
+
+func (c *engineClient) inspectContainer(ctx context.Context, id string) (*inspectResponse, error) {
+	resp, err := c.get(ctx, "/containers/"+id+"/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var inspect inspectResponse
+	if err := json.NewDecoder(resp.Body).Decode(&inspect); err != nil {
+		return nil, fmt.Errorf("failed to decode container inspect: %w", err)
+	}
+	return &inspect, nil
+}
+
+// topProcesses is the /containers/{id}/top response shape: column titles
+// plus one row of field values per process.
+type topProcesses struct {
+	Titles    []string   `json:"Titles"`
+	Processes [][]string `json:"Processes"`
+}
+
+// topCommandLines lists the container's running processes' command lines
+// via the engine's "top" endpoint (the container-namespaced equivalent of
+// pkg/procscan reading host /proc).
+func (c *engineClient) topCommandLines(ctx context.Context, id string) ([]string, error) {
+	resp, err := c.get(ctx, "/containers/"+id+"/top?ps_args=-eo%20cmd")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var top topProcesses
+	if err := json.NewDecoder(resp.Body).Decode(&top); err != nil {
+		return nil, fmt.Errorf("failed to decode container top: %w", err)
+	}
+
+	cmdIdx := 0
+	for i, title := range top.Titles {
+		if strings.EqualFold(title, "CMD") || strings.EqualFold(title, "COMMAND") {
+			cmdIdx = i
+			break
+		}
+	}
+
+	cmdlines := make([]string, 0, len(top.Processes))
+	for _, row := range top.Processes {
+		if cmdIdx < len(row) {
+			cmdlines = append(cmdlines, strings.TrimSpace(row[cmdIdx]))
+		}
+	}
+	return cmdlines, nil
+}
+
+// copyFile extracts a single file from the container's rootfs via the
+// engine's archive endpoint (the "cp" API), enforcing maxSize the same way
+// pkg/filescan skips oversized host files.
+func (c *engineClient) copyFile(ctx context.Context, id, containerPath string, maxSize int64) ([]byte, error) {
+	resp, err := c.get(ctx, "/containers/"+id+"/archive?path="+containerPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	tr := tar.NewReader(resp.Body)
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive header: %w", err)
+	}
+	if maxSize > 0 && header.Size > maxSize {
+		return nil, fmt.Errorf("file %s exceeds max size", containerPath)
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, tr, header.Size); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read archive content: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,43 @@
+package filescan
+
+// ManifestFacts is the normalized result of a ManifestDetector
+// successfully parsing a file's content, regardless of the manifest
+// format it came from. Scanner scores and builds a Detection from these
+// fields rather than from any one format's raw structure.
+type ManifestFacts struct {
+	Name      string
+	Version   string
+	Transport string
+	Port      int
+	ToolCount int
+}
+
+// ManifestDetector recognizes and parses one on-disk MCP manifest format.
+// Scanner tries its configured detectors in order against each file its
+// walk finds; the first whose Matches returns true for the file's path
+// gets to Detect it, so at most one detector parses a given file.
+type ManifestDetector interface {
+	// Name identifies the detector for enabling/disabling via
+	// Scanner.EnableDetectors, e.g. "json", "yaml", "toml".
+	Name() string
+	// Matches reports whether path is a file this detector knows how to
+	// parse, based on its name (e.g. "pyproject.toml", "mcp.yaml").
+	Matches(path string) bool
+	// Detect parses content and returns the manifest's normalized facts,
+	// or nil (with a nil error) if content doesn't look like an MCP
+	// manifest in this detector's format.
+	Detect(content []byte) (*ManifestFacts, error)
+}
+
+// defaultManifestDetectors returns one instance of every built-in
+// ManifestDetector, in the order Scanner tries them. NewScanner uses this
+// unless the caller narrows it with EnableDetectors.
+func defaultManifestDetectors() []ManifestDetector {
+	return []ManifestDetector{
+		jsonManifestDetector{},
+		yamlManifestDetector{},
+		tomlManifestDetector{},
+		packageJSONManifestDetector{},
+		mcpConfigManifestDetector{},
+	}
+}
@@ -0,0 +1,277 @@
+package filescan
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// mapLooksLikeMCPManifest applies the same heuristic the original
+// JSON-only scanner used: at least 2 of {name, version, protocol/mcp,
+// tools} must be present. It's shared by the JSON and YAML detectors; the
+// .mcp/config.* detector checks for an mcpServers map instead.
+func mapLooksLikeMCPManifest(manifest map[string]interface{}) bool {
+	count := 0
+	if manifest["name"] != nil {
+		count++
+	}
+	if manifest["version"] != nil {
+		count++
+	}
+	if manifest["protocol"] != nil || manifest["mcp"] != nil {
+		count++
+	}
+	if manifest["tools"] != nil {
+		count++
+	}
+	return count >= 2
+}
+
+// factsFromMap builds ManifestFacts out of a generic JSON/YAML-shaped
+// manifest map, extracting the port from either a top-level "port" field
+// or a nested "server.port" one, matching the original extractPort.
+func factsFromMap(manifest map[string]interface{}) *ManifestFacts {
+	facts := &ManifestFacts{}
+
+	if name, ok := manifest["name"].(string); ok {
+		facts.Name = name
+	}
+	if version, ok := manifest["version"].(string); ok {
+		facts.Version = version
+	}
+	if manifest["protocol"] != nil || manifest["mcp"] != nil {
+		facts.Transport = "mcp"
+	}
+
+	portOf := func(v interface{}) (int, bool) {
+		switch p := v.(type) {
+		case float64: // encoding/json
+			return int(p), true
+		case int: // gopkg.in/yaml.v3
+			return p, true
+		}
+		return 0, false
+	}
+	if port, ok := portOf(manifest["port"]); ok {
+		facts.Port = port
+	} else if server, ok := manifest["server"].(map[string]interface{}); ok {
+		if port, ok := portOf(server["port"]); ok {
+			facts.Port = port
+		}
+	}
+
+	if tools, ok := manifest["tools"].([]interface{}); ok {
+		facts.ToolCount = len(tools)
+	} else if manifest["tools"] != nil {
+		facts.ToolCount = 1
+	}
+
+	return facts
+}
+
+// jsonManifestDetector parses generic MCP manifest files whose content is
+// a JSON object, the scanner's original and most common format.
+type jsonManifestDetector struct{}
+
+func (jsonManifestDetector) Name() string { return "json" }
+
+func (jsonManifestDetector) Matches(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json") &&
+		!strings.EqualFold(filepath.Base(path), "package.json")
+}
+
+func (jsonManifestDetector) Detect(content []byte) (*ManifestFacts, error) {
+	var manifest map[string]interface{}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		// Not valid JSON; not an error, just not this detector's file.
+		return nil, nil
+	}
+	if !mapLooksLikeMCPManifest(manifest) {
+		return nil, nil
+	}
+	return factsFromMap(manifest), nil
+}
+
+// yamlManifestDetector parses mcp.yaml/mcp.yml manifests, which Claude
+// Desktop and several MCP server scaffolds emit alongside or instead of a
+// JSON manifest.
+type yamlManifestDetector struct{}
+
+func (yamlManifestDetector) Name() string { return "yaml" }
+
+func (yamlManifestDetector) Matches(path string) bool {
+	switch strings.ToLower(filepath.Base(path)) {
+	case "mcp.yaml", "mcp.yml":
+		return true
+	default:
+		return false
+	}
+}
+
+func (yamlManifestDetector) Detect(content []byte) (*ManifestFacts, error) {
+	var manifest map[string]interface{}
+	if err := yaml.Unmarshal(content, &manifest); err != nil {
+		return nil, nil
+	}
+	if !mapLooksLikeMCPManifest(manifest) {
+		return nil, nil
+	}
+	return factsFromMap(manifest), nil
+}
+
+// tomlManifestDetector parses the MCP declarations Python and Rust
+// projects embed in their own build manifests: pyproject.toml's
+// [tool.mcp] table and Cargo.toml's [package.metadata.mcp] table.
+type tomlManifestDetector struct{}
+
+func (tomlManifestDetector) Name() string { return "toml" }
+
+func (tomlManifestDetector) Matches(path string) bool {
+	switch filepath.Base(path) {
+	case "pyproject.toml", "Cargo.toml":
+		return true
+	default:
+		return false
+	}
+}
+
+func (d tomlManifestDetector) Detect(content []byte) (*ManifestFacts, error) {
+	var doc map[string]interface{}
+	if _, err := toml.NewDecoder(bytes.NewReader(content)).Decode(&doc); err != nil {
+		return nil, nil
+	}
+
+	mcp := tomlSection(doc, "tool", "mcp")
+	if mcp == nil {
+		mcp = tomlSection(doc, "package", "metadata", "mcp")
+	}
+	if mcp == nil {
+		return nil, nil
+	}
+
+	facts := &ManifestFacts{Transport: "mcp"}
+	if name, ok := mcp["name"].(string); ok {
+		facts.Name = name
+	} else if project, ok := doc["project"].(map[string]interface{}); ok {
+		if name, ok := project["name"].(string); ok {
+			facts.Name = name
+		}
+	}
+	if version, ok := mcp["version"].(string); ok {
+		facts.Version = version
+	}
+	if port, ok := mcp["port"].(int64); ok {
+		facts.Port = int(port)
+	}
+	if tools, ok := mcp["tools"].([]interface{}); ok {
+		facts.ToolCount = len(tools)
+	} else {
+		facts.ToolCount = 1
+	}
+
+	return facts, nil
+}
+
+// tomlSection walks doc through a chain of nested table keys (e.g.
+// "tool", "mcp" for [tool.mcp]) and returns the table at the end, or nil
+// if any key along the way is missing or isn't itself a table.
+func tomlSection(doc map[string]interface{}, keys ...string) map[string]interface{} {
+	current := doc
+	for _, key := range keys {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+// packageJSONManifestDetector recognizes Node.js MCP servers declared in
+// package.json, either via a Claude/Cursor-style top-level "mcpServers"
+// object or a "bin" entry whose command name points at an MCP runtime.
+type packageJSONManifestDetector struct{}
+
+func (packageJSONManifestDetector) Name() string { return "package-json" }
+
+func (packageJSONManifestDetector) Matches(path string) bool {
+	return strings.EqualFold(filepath.Base(path), "package.json")
+}
+
+func (packageJSONManifestDetector) Detect(content []byte) (*ManifestFacts, error) {
+	var pkg map[string]interface{}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, nil
+	}
+
+	servers, hasServers := pkg["mcpServers"].(map[string]interface{})
+
+	hasMCPBin := false
+	if bin, ok := pkg["bin"].(map[string]interface{}); ok {
+		for name, target := range bin {
+			targetStr, _ := target.(string)
+			if strings.Contains(strings.ToLower(name), "mcp") || strings.Contains(strings.ToLower(targetStr), "mcp") {
+				hasMCPBin = true
+				break
+			}
+		}
+	}
+
+	if !hasServers && !hasMCPBin {
+		return nil, nil
+	}
+
+	facts := &ManifestFacts{Transport: "mcp"}
+	if name, ok := pkg["name"].(string); ok {
+		facts.Name = name
+	}
+	if version, ok := pkg["version"].(string); ok {
+		facts.Version = version
+	}
+	if hasServers {
+		facts.ToolCount = len(servers)
+	} else {
+		facts.ToolCount = 1
+	}
+
+	return facts, nil
+}
+
+// mcpConfigManifestDetector recognizes Claude/Cursor-style client configs
+// at .mcp/config.json, .mcp/config.yaml, or .mcp/config.yml, which list
+// MCP servers the client launches rather than describing one itself.
+type mcpConfigManifestDetector struct{}
+
+func (mcpConfigManifestDetector) Name() string { return "mcp-config" }
+
+func (mcpConfigManifestDetector) Matches(path string) bool {
+	if filepath.Base(filepath.Dir(path)) != ".mcp" {
+		return false
+	}
+	name := strings.ToLower(filepath.Base(path))
+	return strings.HasPrefix(name, "config.") &&
+		(strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml"))
+}
+
+func (mcpConfigManifestDetector) Detect(content []byte) (*ManifestFacts, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return nil, nil
+		}
+	}
+
+	servers, ok := doc["mcpServers"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	return &ManifestFacts{
+		Transport: "mcp",
+		ToolCount: len(servers),
+	}, nil
+}
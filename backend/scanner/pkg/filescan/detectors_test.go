@@ -0,0 +1,164 @@
+package filescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONManifestDetectorDetectsMCPManifest(t *testing.T) {
+	content := []byte(`{"name": "my-server", "version": "1.0.0", "protocol": "mcp", "tools": [{"name": "a"}, {"name": "b"}]}`)
+
+	facts, err := jsonManifestDetector{}.Detect(content)
+
+	require.NoError(t, err)
+	require.NotNil(t, facts)
+	assert.Equal(t, "my-server", facts.Name)
+	assert.Equal(t, "1.0.0", facts.Version)
+	assert.Equal(t, "mcp", facts.Transport)
+	assert.Equal(t, 2, facts.ToolCount)
+}
+
+func TestJSONManifestDetectorRejectsUnrelatedJSON(t *testing.T) {
+	facts, err := jsonManifestDetector{}.Detect([]byte(`{"foo": "bar"}`))
+
+	assert.NoError(t, err)
+	assert.Nil(t, facts)
+}
+
+func TestJSONManifestDetectorRejectsMalformedJSON(t *testing.T) {
+	facts, err := jsonManifestDetector{}.Detect([]byte(`{not json`))
+
+	assert.NoError(t, err)
+	assert.Nil(t, facts)
+}
+
+func TestYAMLManifestDetectorDetectsMCPManifest(t *testing.T) {
+	content := []byte("name: my-server\nversion: 1.0.0\nmcp: true\n")
+
+	facts, err := yamlManifestDetector{}.Detect(content)
+
+	require.NoError(t, err)
+	require.NotNil(t, facts)
+	assert.Equal(t, "my-server", facts.Name)
+	assert.Equal(t, "1.0.0", facts.Version)
+	assert.Equal(t, "mcp", facts.Transport)
+}
+
+func TestYAMLManifestDetectorRejectsMalformedYAML(t *testing.T) {
+	facts, err := yamlManifestDetector{}.Detect([]byte("not:\n- valid\n  yaml: ["))
+
+	assert.NoError(t, err)
+	assert.Nil(t, facts)
+}
+
+func TestTOMLManifestDetectorDetectsPyprojectToolMCP(t *testing.T) {
+	content := []byte(`
+[project]
+name = "my-project"
+
+[tool.mcp]
+version = "2.0.0"
+port = 9000
+tools = ["a", "b", "c"]
+`)
+
+	facts, err := tomlManifestDetector{}.Detect(content)
+
+	require.NoError(t, err)
+	require.NotNil(t, facts)
+	assert.Equal(t, "my-project", facts.Name)
+	assert.Equal(t, "2.0.0", facts.Version)
+	assert.Equal(t, 9000, facts.Port)
+	assert.Equal(t, 3, facts.ToolCount)
+}
+
+func TestTOMLManifestDetectorDetectsCargoPackageMetadataMCP(t *testing.T) {
+	content := []byte(`
+[package]
+name = "my-crate"
+
+[package.metadata.mcp]
+name = "my-server"
+version = "3.0.0"
+`)
+
+	facts, err := tomlManifestDetector{}.Detect(content)
+
+	require.NoError(t, err)
+	require.NotNil(t, facts)
+	assert.Equal(t, "my-server", facts.Name)
+	assert.Equal(t, "3.0.0", facts.Version)
+	assert.Equal(t, 1, facts.ToolCount)
+}
+
+func TestTOMLManifestDetectorRejectsUnrelatedToml(t *testing.T) {
+	facts, err := tomlManifestDetector{}.Detect([]byte(`[project]
+name = "no-mcp-here"
+`))
+
+	assert.NoError(t, err)
+	assert.Nil(t, facts)
+}
+
+// TestTOMLManifestDetectorRejectsMalformedToml is a regression test for the
+// Decode call returning (toml.MetaData, error): assigning it to a single
+// variable is an assignment-count mismatch that fails to compile.
+func TestTOMLManifestDetectorRejectsMalformedToml(t *testing.T) {
+	facts, err := tomlManifestDetector{}.Detect([]byte("not = [valid toml"))
+
+	assert.NoError(t, err)
+	assert.Nil(t, facts)
+}
+
+func TestPackageJSONManifestDetectorDetectsMCPServers(t *testing.T) {
+	content := []byte(`{"name": "my-pkg", "version": "1.2.3", "mcpServers": {"a": {}, "b": {}}}`)
+
+	facts, err := packageJSONManifestDetector{}.Detect(content)
+
+	require.NoError(t, err)
+	require.NotNil(t, facts)
+	assert.Equal(t, "my-pkg", facts.Name)
+	assert.Equal(t, 2, facts.ToolCount)
+}
+
+func TestPackageJSONManifestDetectorDetectsMCPBin(t *testing.T) {
+	content := []byte(`{"name": "my-pkg", "bin": {"mcp-server": "./bin/run.js"}}`)
+
+	facts, err := packageJSONManifestDetector{}.Detect(content)
+
+	require.NoError(t, err)
+	require.NotNil(t, facts)
+	assert.Equal(t, 1, facts.ToolCount)
+}
+
+func TestPackageJSONManifestDetectorRejectsUnrelatedPackageJSON(t *testing.T) {
+	facts, err := packageJSONManifestDetector{}.Detect([]byte(`{"name": "my-pkg", "bin": {"cli": "./bin/cli.js"}}`))
+
+	assert.NoError(t, err)
+	assert.Nil(t, facts)
+}
+
+func TestMCPConfigManifestDetectorMatchesOnlyUnderDotMCPDir(t *testing.T) {
+	d := mcpConfigManifestDetector{}
+
+	assert.True(t, d.Matches(".mcp/config.json"))
+	assert.True(t, d.Matches(".mcp/config.yaml"))
+	assert.False(t, d.Matches("config.json"))
+	assert.False(t, d.Matches(".mcp/other.json"))
+}
+
+func TestMCPConfigManifestDetectorDetectsJSONAndYAML(t *testing.T) {
+	d := mcpConfigManifestDetector{}
+
+	jsonFacts, err := d.Detect([]byte(`{"mcpServers": {"a": {}}}`))
+	require.NoError(t, err)
+	require.NotNil(t, jsonFacts)
+	assert.Equal(t, 1, jsonFacts.ToolCount)
+
+	yamlFacts, err := d.Detect([]byte("mcpServers:\n  a: {}\n  b: {}\n"))
+	require.NoError(t, err)
+	require.NotNil(t, yamlFacts)
+	assert.Equal(t, 2, yamlFacts.ToolCount)
+}
@@ -3,64 +3,114 @@
 package filescan
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/buildinfo"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/redact"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/tracing"
 )
 
 // Detection represents a detected MCP manifest file
 type Detection struct {
-	EventID      string    `json:"event_id"`
-	Timestamp    time.Time `json:"timestamp"`
-	HostID       string    `json:"host_id"`
-	DetectionType string   `json:"detection_type"`
-	Score        int       `json:"score"`
-	Evidence     Evidence  `json:"evidence"`
+	EventID       string    `json:"event_id"`
+	Timestamp     time.Time `json:"timestamp"`
+	HostID        string    `json:"host_id"`
+	DetectionType string    `json:"detection_type"`
+	Score         int       `json:"score"`
+	Evidence      Evidence  `json:"evidence"`
 }
 
 // Evidence contains detailed information about the detection
 type Evidence struct {
-	Source    string            `json:"source"`
-	FilePath  string            `json:"file_path"`
-	FileHash  string            `json:"file_hash"`
-	Snippet   string            `json:"snippet"`
-	Port      int               `json:"port,omitempty"`
-	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Source   string `json:"source"`
+	FilePath string `json:"file_path"`
+	FileHash string `json:"file_hash"`
+	Snippet  string `json:"snippet"`
+	// Redacted is true if Snippet had one or more secrets/PII masked by
+	// pkg/redact before being attached here.
+	Redacted bool                   `json:"redacted"`
+	Port     int                    `json:"port,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // Scanner scans filesystem for MCP manifest files
 type Scanner struct {
-	FilesystemRoots []string
+	FilesystemRoots  []string
 	ManifestPatterns []string
 	MaxFileSizeBytes int64
-	HostID          string
-	ScannerVersion  string
+	HostID           string
+	ScannerVersion   string
+
+	// Detectors are tried, in order, against every file the walk finds
+	// matching ManifestPatterns. NewScanner populates this with every
+	// built-in ManifestDetector; call EnableDetectors to narrow it.
+	Detectors []ManifestDetector
+
+	// State, if set, makes Scan incremental: a manifest file whose
+	// (mtime, size) matches its persisted FileState is skipped rather
+	// than reread and rehashed. Watch also consults and updates it, so a
+	// file Watch already picked up isn't redetected by the next Scan.
+	// Nil disables incremental mode, rehashing every matching file every
+	// cycle (the pre-incremental behavior).
+	State StateStore
+
+	skippedCount            atomic.Int64
+	rehashedCount           atomic.Int64
+	watchQueueOverflowCount atomic.Int64
 }
 
-// NewScanner creates a new filesystem scanner
+// NewScanner creates a new filesystem scanner with every built-in
+// ManifestDetector enabled (see EnableDetectors to narrow that).
 func NewScanner(roots []string, patterns []string, maxSize int64, hostID string) *Scanner {
 	return &Scanner{
-		FilesystemRoots: roots,
+		FilesystemRoots:  roots,
 		ManifestPatterns: patterns,
 		MaxFileSizeBytes: maxSize,
-		HostID:          hostID,
-		ScannerVersion:  "scanner-v1.0.0",
+		HostID:           hostID,
+		ScannerVersion:   "scanner-" + buildinfo.Version,
+		Detectors:        defaultManifestDetectors(),
+	}
+}
+
+// EnableDetectors narrows s.Detectors down to the built-in detectors
+// named (see ManifestDetector.Name), e.g. from an operator's
+// probes.filescan.detectors config. An unrecognized name is ignored.
+func (s *Scanner) EnableDetectors(names []string) {
+	enabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		enabled[name] = true
+	}
+
+	detectors := make([]ManifestDetector, 0, len(names))
+	for _, d := range defaultManifestDetectors() {
+		if enabled[d.Name()] {
+			detectors = append(detectors, d)
+		}
 	}
+	s.Detectors = detectors
 }
 
-// Scan performs a filesystem scan for MCP manifest files
-func (s *Scanner) Scan() ([]*Detection, error) {
+// Scan performs a filesystem scan for MCP manifest files, opening one child
+// span per filesystem root under the caller's span (see
+// cmd/scanner's Scanner.ScanCycle root span).
+func (s *Scanner) Scan(ctx context.Context) ([]*Detection, error) {
 	detections := []*Detection{}
 
 	for _, root := range s.FilesystemRoots {
-		rootDetections, err := s.scanRoot(root)
+		rootDetections, err := s.scanRoot(ctx, root)
 		if err != nil {
 			fmt.Printf("Error scanning root %s: %v\n", root, err)
 			continue
@@ -72,7 +122,10 @@ func (s *Scanner) Scan() ([]*Detection, error) {
 }
 
 // scanRoot scans a single filesystem root
-func (s *Scanner) scanRoot(root string) ([]*Detection, error) {
+func (s *Scanner) scanRoot(ctx context.Context, root string) ([]*Detection, error) {
+	_, span := tracing.Tracer.Start(ctx, "filescan.scanRoot", trace.WithAttributes(attribute.String("root", root)))
+	defer span.End()
+
 	detections := []*Detection{}
 
 	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
@@ -93,6 +146,11 @@ func (s *Scanner) scanRoot(root string) ([]*Detection, error) {
 
 		// Check if filename matches any manifest pattern
 		if s.isManifestFile(path) {
+			if s.unchangedSinceLastScan(path, info) {
+				s.skippedCount.Add(1)
+				return nil
+			}
+
 			detection, err := s.analyzeManifestFile(path, info)
 			if err != nil {
 				fmt.Printf("Error analyzing %s: %v\n", path, err)
@@ -106,10 +164,60 @@ func (s *Scanner) scanRoot(root string) ([]*Detection, error) {
 
 		return nil
 	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(attribute.Int("detections_found", len(detections)))
 
 	return detections, err
 }
 
+// unchangedSinceLastScan reports whether s.State holds a FileState for
+// path whose (mtime, size) still matches info, meaning the file hasn't
+// changed since it was last read and can be skipped this cycle. Always
+// false when incremental mode is off (s.State is nil).
+func (s *Scanner) unchangedSinceLastScan(path string, info os.FileInfo) bool {
+	if s.State == nil {
+		return false
+	}
+	state, ok, err := s.State.Get(path)
+	if err != nil || !ok {
+		return false
+	}
+	return state.ModTime.Equal(info.ModTime()) && state.Size == info.Size()
+}
+
+// recordFileState persists path's current (mtime, size, hash) to s.State
+// so a later cycle's unchangedSinceLastScan can skip it, a no-op when
+// incremental mode is off.
+func (s *Scanner) recordFileState(path string, info os.FileInfo, fileHash, detectionID string) {
+	if s.State == nil {
+		return
+	}
+	if err := s.State.Put(path, FileState{
+		ModTime:         info.ModTime(),
+		Size:            info.Size(),
+		SHA256:          fileHash,
+		LastDetectionID: detectionID,
+	}); err != nil {
+		fmt.Printf("Error persisting file state for %s: %v\n", path, err)
+	}
+}
+
+// SkippedCount returns, then resets, the number of files Scan/Watch have
+// skipped since the last call because their persisted FileState matched.
+func (s *Scanner) SkippedCount() int64 { return s.skippedCount.Swap(0) }
+
+// RehashedCount returns, then resets, the number of manifest files
+// Scan/Watch have actually read and hashed since the last call.
+func (s *Scanner) RehashedCount() int64 { return s.rehashedCount.Swap(0) }
+
+// WatchQueueOverflowCount returns, then resets, the number of filesystem
+// events Watch has dropped since the last call because its debounce queue
+// or detections channel was full.
+func (s *Scanner) WatchQueueOverflowCount() int64 { return s.watchQueueOverflowCount.Swap(0) }
+
 // isManifestFile checks if the file matches manifest patterns
 func (s *Scanner) isManifestFile(path string) bool {
 	filename := filepath.Base(path)
@@ -133,116 +241,98 @@ func (s *Scanner) isManifestFile(path string) bool {
 	return false
 }
 
-// analyzeManifestFile analyzes a potential MCP manifest file
+// analyzeManifestFile finds the first of s.Detectors whose Matches
+// accepts path and hands it the file's content, returning a Detection
+// built from the ManifestFacts it reports. A file matching no detector,
+// or whose matching detector doesn't recognize its content as an MCP
+// manifest, yields a nil Detection and a nil error.
 func (s *Scanner) analyzeManifestFile(path string, info os.FileInfo) (*Detection, error) {
+	var detector ManifestDetector
+	for _, d := range s.Detectors {
+		if d.Matches(path) {
+			detector = d
+			break
+		}
+	}
+	if detector == nil {
+		return nil, nil
+	}
+
 	// Read file content (up to 1KB per FR-009)
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	// Try to parse as JSON to validate it's a real manifest
-	var manifest map[string]interface{}
-	if err := json.Unmarshal(content, &manifest); err != nil {
-		// Not a valid JSON file, skip
-		return nil, nil
+	facts, err := detector.Detect(content)
+	if err != nil {
+		return nil, fmt.Errorf("detector %q: %w", detector.Name(), err)
 	}
-
-	// Check if it looks like an MCP manifest (has name, version, etc.)
-	if !s.looksLikeMCPManifest(manifest) {
+	if facts == nil {
 		return nil, nil
 	}
 
-	// Extract port if present
-	port := s.extractPort(manifest)
+	s.rehashedCount.Add(1)
 
 	// Generate SHA256 hash of file content
 	hash := sha256.Sum256(content)
 	fileHash := hex.EncodeToString(hash[:])
 
+	// Mask secrets/PII before truncating, so a token isn't chopped in
+	// half and left partially exposed.
+	redacted := redact.String(string(content))
+	snippet := redacted.Text
+
 	// Truncate snippet to 1KB (FR-009 privacy requirement)
-	snippet := string(content)
 	if len(snippet) > 1024 {
 		snippet = snippet[:1024]
 	}
 
 	// Calculate score (endpoint signals have highest weight per FR-003)
-	score := s.calculateScore(manifest)
+	score := calculateScore(facts)
 
 	// Create detection
 	detection := &Detection{
-		EventID:      uuid.New().String(),
-		Timestamp:    time.Now().UTC(),
-		HostID:       s.HostID,
+		EventID:       uuid.New().String(),
+		Timestamp:     time.Now().UTC(),
+		HostID:        s.HostID,
 		DetectionType: "file",
-		Score:        score,
+		Score:         score,
 		Evidence: Evidence{
 			Source:   s.ScannerVersion,
 			FilePath: path,
 			FileHash: fileHash,
 			Snippet:  snippet,
-			Port:     port,
+			Redacted: redacted.Count > 0,
+			Port:     facts.Port,
 			Metadata: map[string]interface{}{
-				"file_size_bytes": info.Size(),
+				"file_size_bytes":  info.Size(),
 				"scan_duration_ms": 0, // Will be set by caller
+				"detector":         detector.Name(),
+				"manifest_name":    facts.Name,
+				"manifest_version": facts.Version,
+				"redaction_count":  redacted.Count,
 			},
 		},
 	}
 
-	return detection, nil
-}
-
-// looksLikeMCPManifest checks if JSON looks like an MCP manifest
-func (s *Scanner) looksLikeMCPManifest(manifest map[string]interface{}) bool {
-	// Check for common MCP manifest fields
-	hasName := manifest["name"] != nil
-	hasVersion := manifest["version"] != nil
-	hasProtocol := manifest["protocol"] != nil || manifest["mcp"] != nil
-	hasTools := manifest["tools"] != nil
+	s.recordFileState(path, info, fileHash, detection.EventID)
 
-	// At least 2 of these fields should be present
-	count := 0
-	if hasName {
-		count++
-	}
-	if hasVersion {
-		count++
-	}
-	if hasProtocol {
-		count++
-	}
-	if hasTools {
-		count++
-	}
-
-	return count >= 2
-}
-
-// extractPort extracts port from manifest if present
-func (s *Scanner) extractPort(manifest map[string]interface{}) int {
-	// Try common port field names
-	if port, ok := manifest["port"].(float64); ok {
-		return int(port)
-	}
-	if server, ok := manifest["server"].(map[string]interface{}); ok {
-		if port, ok := server["port"].(float64); ok {
-			return int(port)
-		}
-	}
-	return 0
+	return detection, nil
 }
 
-// calculateScore assigns a score based on manifest confidence
-func (s *Scanner) calculateScore(manifest map[string]interface{}) int {
+// calculateScore assigns a score based on the detected manifest's
+// confidence, regardless of which detector produced facts.
+func calculateScore(facts *ManifestFacts) int {
 	// Endpoint signals have highest weight (typical: 11 per FR-003)
 	baseScore := 11
 
 	// Adjust based on confidence indicators
-	if manifest["protocol"] != nil || manifest["mcp"] != nil {
-		baseScore += 2 // High confidence - explicit MCP protocol
+	if facts.Transport != "" {
+		baseScore += 2 // High confidence - explicit MCP protocol/transport
 	}
-	if manifest["tools"] != nil {
-		baseScore += 1 // Tools array present
+	if facts.ToolCount > 0 {
+		baseScore += 1 // Tools present
 	}
 
 	return baseScore
@@ -0,0 +1,89 @@
+package filescan
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// fileStateBucket is the single bbolt bucket FileState records live in,
+// keyed by absolute file path.
+var fileStateBucket = []byte("file_state")
+
+// FileState is what a StateStore remembers about one previously-detected
+// manifest file, letting Scan skip it on a later cycle when neither its
+// mtime nor size has changed.
+type FileState struct {
+	ModTime         time.Time `json:"mod_time"`
+	Size            int64     `json:"size"`
+	SHA256          string    `json:"sha256"`
+	LastDetectionID string    `json:"last_detection_id"`
+}
+
+// StateStore persists FileState across scan cycles so Scanner.Scan can
+// skip rehashing files that haven't changed. A Scanner with no StateStore
+// rehashes every matching file on every cycle, matching the
+// pre-incremental-mode behavior.
+type StateStore interface {
+	Get(path string) (FileState, bool, error)
+	Put(path string, state FileState) error
+	Close() error
+}
+
+// BoltStateStore is the default, restart-surviving StateStore backed by a
+// local BoltDB file. One BoltStateStore owns its file exclusively (bbolt
+// takes a file lock), so only one scanner instance should point at a
+// given path.
+type BoltStateStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStateStore opens (creating if necessary) a BoltDB-backed file
+// state store at path.
+func NewBoltStateStore(path string) (*BoltStateStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file state db: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(fileStateBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create file state bucket: %w", err)
+	}
+
+	return &BoltStateStore{db: db}, nil
+}
+
+func (s *BoltStateStore) Get(path string) (FileState, bool, error) {
+	var state FileState
+	found := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(fileStateBucket).Get([]byte(path))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &state)
+	})
+	return state, found, err
+}
+
+func (s *BoltStateStore) Put(path string, state FileState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode file state for %s: %w", path, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(fileStateBucket).Put([]byte(path), data)
+	})
+}
+
+func (s *BoltStateStore) Close() error {
+	return s.db.Close()
+}
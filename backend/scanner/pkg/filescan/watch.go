@@ -0,0 +1,160 @@
+package filescan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long Watch waits after a path's last event before
+// analyzing it, coalescing the burst of CREATE+WRITE+CHMOD events a
+// single file write typically produces into one analysis.
+const watchDebounce = 2 * time.Second
+
+// maxPendingWatch bounds how many paths Watch debounces concurrently. A
+// path beyond this cap is dropped (and counted via
+// WatchQueueOverflowCount) rather than growing the pending set unbounded
+// under an event storm.
+const maxPendingWatch = 4096
+
+// Watch subscribes to create/write/rename events under every
+// FilesystemRoots entry and, after debouncing per path, analyzes changed
+// files the same way Scan does, sending any Detection found to out. It
+// blocks until ctx is canceled or fsnotify's Events channel closes, and is
+// meant to run alongside the periodic Scan, which remains the safety net
+// for events Watch misses (e.g. a root that doesn't exist yet when Watch
+// starts) and for initial enumeration.
+func (s *Scanner) Watch(ctx context.Context, out chan<- *Detection) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create fsnotify watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	for _, root := range s.FilesystemRoots {
+		if err := s.addWatchRecursive(watcher, root); err != nil {
+			fmt.Printf("Error watching root %s: %v\n", root, err)
+		}
+	}
+
+	var mu sync.Mutex
+	pending := make(map[string]*time.Timer)
+
+	schedule := func(path string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if timer, exists := pending[path]; exists {
+			timer.Reset(watchDebounce)
+			return
+		}
+		if len(pending) >= maxPendingWatch {
+			s.watchQueueOverflowCount.Add(1)
+			return
+		}
+		pending[path] = time.AfterFunc(watchDebounce, func() {
+			mu.Lock()
+			delete(pending, path)
+			mu.Unlock()
+			s.analyzeWatchedPath(path, out)
+		})
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			s.handleWatchEvent(watcher, event, schedule)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Printf("fsnotify error: %v\n", err)
+		}
+	}
+}
+
+// handleWatchEvent reacts to one fsnotify event: a newly created
+// directory is added to the watch so files created inside it are seen
+// too, and a created/written/renamed manifest file is handed to schedule
+// for debounced analysis.
+func (s *Scanner) handleWatchEvent(watcher *fsnotify.Watcher, event fsnotify.Event, schedule func(string)) {
+	if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Rename) == 0 {
+		return
+	}
+
+	info, err := os.Stat(event.Name)
+	if err == nil && info.IsDir() {
+		if event.Op&fsnotify.Create != 0 {
+			if err := s.addWatchRecursive(watcher, event.Name); err != nil {
+				fmt.Printf("Error watching new directory %s: %v\n", event.Name, err)
+			}
+		}
+		return
+	}
+
+	if !s.isManifestFile(event.Name) {
+		return
+	}
+	schedule(event.Name)
+}
+
+// addWatchRecursive adds a fsnotify watch on root and every directory
+// beneath it; fsnotify only watches one directory level per call, so
+// directories created afterward are picked up from their parent's Create
+// event in Watch's main loop instead.
+func (s *Scanner) addWatchRecursive(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if err := watcher.Add(path); err != nil {
+				fmt.Printf("Error adding watch for %s: %v\n", path, err)
+			}
+		}
+		return nil
+	})
+}
+
+// analyzeWatchedPath re-stats and analyzes path once its debounce window
+// has elapsed, sending a Detection to out if one is found. Unlike Scan's
+// walk, the file may have been removed again during the debounce window,
+// which is not an error.
+func (s *Scanner) analyzeWatchedPath(path string, out chan<- *Detection) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	if info.Size() > s.MaxFileSizeBytes {
+		return
+	}
+	if s.unchangedSinceLastScan(path, info) {
+		s.skippedCount.Add(1)
+		return
+	}
+
+	detection, err := s.analyzeManifestFile(path, info)
+	if err != nil {
+		fmt.Printf("Error analyzing %s: %v\n", path, err)
+		return
+	}
+	if detection == nil {
+		return
+	}
+
+	select {
+	case out <- detection:
+	default:
+		s.watchQueueOverflowCount.Add(1)
+	}
+}
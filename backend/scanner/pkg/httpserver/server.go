@@ -0,0 +1,246 @@
+// Package httpserver provides a reusable TLS + HTTP basic auth server for
+// MCPeeker's scrape/health endpoints, configured the same way the
+// Prometheus ecosystem configures its web servers (tls_server_config /
+// basic_auth_users): https://prometheus.io/docs/guides/tls-encryption/
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/logging"
+)
+
+// ClientAuthType names the crypto/tls ClientAuthType values config accepts,
+// so operators write e.g. "RequireAndVerifyClientCert" instead of a numeric
+// constant.
+type ClientAuthType string
+
+// Client auth modes accepted by Config.ClientAuth, matching the
+// crypto/tls.ClientAuthType values of the same name.
+const (
+	ClientAuthNoClientCert               ClientAuthType = "NoClientCert"
+	ClientAuthRequestClientCert          ClientAuthType = "RequestClientCert"
+	ClientAuthRequireAnyClientCert       ClientAuthType = "RequireAnyClientCert"
+	ClientAuthVerifyClientCertIfGiven    ClientAuthType = "VerifyClientCertIfGiven"
+	ClientAuthRequireAndVerifyClientCert ClientAuthType = "RequireAndVerifyClientCert"
+)
+
+func (t ClientAuthType) tlsClientAuth() (tls.ClientAuthType, error) {
+	switch t {
+	case "", ClientAuthNoClientCert:
+		return tls.NoClientCert, nil
+	case ClientAuthRequestClientCert:
+		return tls.RequestClientCert, nil
+	case ClientAuthRequireAnyClientCert:
+		return tls.RequireAnyClientCert, nil
+	case ClientAuthVerifyClientCertIfGiven:
+		return tls.VerifyClientCertIfGiven, nil
+	case ClientAuthRequireAndVerifyClientCert:
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return 0, fmt.Errorf("httpserver: unknown client_auth %q", t)
+	}
+}
+
+// Config configures a Server's transport security and HTTP basic auth.
+type Config struct {
+	// TLSCertFile and TLSKeyFile enable TLS when both are set. Leaving
+	// them empty serves plain HTTP, e.g. for local development.
+	TLSCertFile string `yaml:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file"`
+
+	// TLSCAFile, combined with ClientAuth, enables mTLS by verifying
+	// client certificates against this CA.
+	TLSCAFile  string         `yaml:"tls_ca_file"`
+	ClientAuth ClientAuthType `yaml:"client_auth"`
+
+	// BasicAuthUsersFile points at a YAML file mapping username to a
+	// bcrypt hash of their password (bcrypt.GenerateFromPassword output,
+	// never the raw password — see Prometheus's basic_auth_users). It is
+	// reloaded on SIGHUP by Server.WatchForReload so credentials can
+	// rotate without a restart. Leaving it empty disables basic auth.
+	BasicAuthUsersFile string `yaml:"basic_auth_users_file"`
+}
+
+// Server wraps http.Server with Config's TLS/mTLS transport and a basic
+// auth middleware applied in front of the handler it was built with.
+type Server struct {
+	httpServer *http.Server
+	usersFile  string
+	users      atomic.Pointer[map[string]string] // username -> bcrypt hash
+	logger     logging.Logger
+}
+
+// New builds a Server listening on addr that serves handler behind cfg's
+// TLS/mTLS transport and basic auth. A nil logger falls back to a
+// discarding logger.
+func New(addr string, handler http.Handler, cfg Config, logger logging.Logger) (*Server, error) {
+	if logger == nil {
+		logger = logging.Noop()
+	}
+
+	s := &Server{usersFile: cfg.BasicAuthUsersFile, logger: logger}
+
+	if cfg.BasicAuthUsersFile != "" {
+		users, err := loadUsersFile(cfg.BasicAuthUsersFile)
+		if err != nil {
+			return nil, fmt.Errorf("httpserver: failed to load basic_auth_users_file: %w", err)
+		}
+		s.users.Store(&users)
+	}
+
+	s.httpServer = &http.Server{
+		Addr:    addr,
+		Handler: s.requireBasicAuth(handler),
+	}
+
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		tlsConfig, err := buildTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		s.httpServer.TLSConfig = tlsConfig
+	}
+
+	return s, nil
+}
+
+// ListenAndServe starts serving, using TLS automatically when Config set a
+// certificate. It blocks until the server stops or errors, matching
+// http.Server's own ListenAndServe/ListenAndServeTLS contract.
+func (s *Server) ListenAndServe() error {
+	if s.httpServer.TLSConfig != nil {
+		// Certificates are already loaded into TLSConfig, so the
+		// filename arguments ListenAndServeTLS takes are unused here.
+		return s.httpServer.ListenAndServeTLS("", "")
+	}
+	return s.httpServer.ListenAndServe()
+}
+
+// Shutdown gracefully stops the server, waiting for in-flight requests to
+// finish until ctx is done. It delegates directly to http.Server.Shutdown.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// WatchForReload reloads the basic auth user file on SIGHUP until ctx is
+// cancelled. It is a no-op if Config had no BasicAuthUsersFile. Intended to
+// run in its own goroutine, alongside ListenAndServe.
+func (s *Server) WatchForReload(ctx context.Context) {
+	if s.usersFile == "" {
+		return
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigChan:
+			s.logger.Info("received SIGHUP, reloading basic auth users", "file", s.usersFile)
+			users, err := loadUsersFile(s.usersFile)
+			if err != nil {
+				s.logger.Error("basic auth user file reload rejected, keeping previous users", "error", err)
+				continue
+			}
+			s.users.Store(&users)
+			s.logger.Info("basic auth users reloaded", "count", len(users))
+		}
+	}
+}
+
+func loadUsersFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	users := make(map[string]string)
+	if err := yaml.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("parse basic auth users file: %w", err)
+	}
+	return users, nil
+}
+
+// dummyHash is compared against on an unknown username so a lookup miss
+// takes the same time as a wrong password, rather than leaking which
+// usernames exist via a fast-path rejection.
+const dummyHash = "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
+
+// requireBasicAuth wraps next with HTTP basic auth. An empty user set (no
+// BasicAuthUsersFile configured) disables auth entirely, so Server is also
+// usable as a plain TLS-only server.
+func (s *Server) requireBasicAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usersPtr := s.users.Load()
+		if usersPtr == nil || len(*usersPtr) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		username, password, ok := r.BasicAuth()
+		if !ok || !authenticate(*usersPtr, username, password) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="mcpeeker"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func authenticate(users map[string]string, username, password string) bool {
+	hash, ok := users[username]
+	if !ok {
+		hash = dummyHash
+	}
+	// bcrypt.CompareHashAndPassword is constant-time in the password
+	// comparison; falling back to dummyHash above keeps an unknown
+	// username from short-circuiting before that comparison runs.
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return ok && err == nil
+}
+
+func buildTLSConfig(cfg Config) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("httpserver: failed to load TLS certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS13,
+	}
+
+	if cfg.TLSCAFile != "" {
+		caCert, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("httpserver: failed to read tls_ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("httpserver: failed to parse tls_ca_file %s", cfg.TLSCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+	}
+
+	clientAuth, err := cfg.ClientAuth.tlsClientAuth()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.ClientAuth = clientAuth
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,192 @@
+package httpserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func bcryptHash(t *testing.T, password string) string {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	return string(hash)
+}
+
+func TestAuthenticateValidCredentials(t *testing.T) {
+	users := map[string]string{"alice": bcryptHash(t, "hunter2")}
+
+	assert.True(t, authenticate(users, "alice", "hunter2"))
+}
+
+func TestAuthenticateWrongPassword(t *testing.T) {
+	users := map[string]string{"alice": bcryptHash(t, "hunter2")}
+
+	assert.False(t, authenticate(users, "alice", "wrong"))
+}
+
+func TestAuthenticateUnknownUserComparesAgainstDummyHash(t *testing.T) {
+	users := map[string]string{"alice": bcryptHash(t, "hunter2")}
+
+	assert.False(t, authenticate(users, "bob", "hunter2"))
+}
+
+func TestRequireBasicAuthDisabledWithNoUsers(t *testing.T) {
+	s := &Server{}
+	handler := s.requireBasicAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRequireBasicAuthRejectsMissingCredentials(t *testing.T) {
+	s := &Server{}
+	users := map[string]string{"alice": bcryptHash(t, "hunter2")}
+	s.users.Store(&users)
+	handler := s.requireBasicAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRequireBasicAuthAcceptsValidCredentials(t *testing.T) {
+	s := &Server{}
+	users := map[string]string{"alice": bcryptHash(t, "hunter2")}
+	s.users.Store(&users)
+	handler := s.requireBasicAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.SetBasicAuth("alice", "hunter2")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestClientAuthTypeTlsClientAuthMapsKnownValues(t *testing.T) {
+	cases := map[ClientAuthType]bool{
+		"":                                   true,
+		ClientAuthNoClientCert:               true,
+		ClientAuthRequestClientCert:          true,
+		ClientAuthRequireAnyClientCert:       true,
+		ClientAuthVerifyClientCertIfGiven:    true,
+		ClientAuthRequireAndVerifyClientCert: true,
+		ClientAuthType("bogus"):              false,
+	}
+
+	for authType, wantOK := range cases {
+		_, err := authType.tlsClientAuth()
+		if wantOK {
+			assert.NoError(t, err, "authType=%q", authType)
+		} else {
+			assert.Error(t, err, "authType=%q", authType)
+		}
+	}
+}
+
+func writeTestCertAndKey(t *testing.T, dir string) (certPath, keyPath string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certPath = filepath.Join(dir, "cert.pem")
+	keyPath = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return certPath, keyPath
+}
+
+func TestBuildTLSConfigLoadsCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeTestCertAndKey(t, dir)
+
+	tlsConfig, err := buildTLSConfig(Config{TLSCertFile: certPath, TLSKeyFile: keyPath})
+
+	assert.NoError(t, err)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+}
+
+func TestBuildTLSConfigWithCAEnablesClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeTestCertAndKey(t, dir)
+
+	tlsConfig, err := buildTLSConfig(Config{
+		TLSCertFile: certPath,
+		TLSKeyFile:  keyPath,
+		TLSCAFile:   certPath, // self-signed cert doubles as its own CA bundle here
+		ClientAuth:  ClientAuthRequireAndVerifyClientCert,
+	})
+
+	require.NoError(t, err)
+	assert.NotNil(t, tlsConfig.ClientCAs)
+}
+
+func TestBuildTLSConfigRejectsUnknownClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeTestCertAndKey(t, dir)
+
+	_, err := buildTLSConfig(Config{TLSCertFile: certPath, TLSKeyFile: keyPath, ClientAuth: "bogus"})
+
+	assert.Error(t, err)
+}
+
+func TestLoadUsersFileParsesYAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "users.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("alice: "+bcryptHash(t, "hunter2")+"\n"), 0600))
+
+	users, err := loadUsersFile(path)
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Contains(t, users, "alice")
+}
+
+func TestLoadUsersFileMissingFile(t *testing.T) {
+	_, err := loadUsersFile("/nonexistent/users.yaml")
+	assert.Error(t, err)
+}
@@ -61,4 +61,121 @@ var (
 		},
 		[]string{"error_type"},
 	)
+
+	// ConfigReloadsTotal counts SIGHUP/fsnotify/-/reload config reloads,
+	// labeled by outcome.
+	ConfigReloadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "config_reloads_total",
+			Help: "Total number of config reloads, by result",
+		},
+		[]string{"result"}, // ok, error
+	)
+
+	// MCPContainersScannedTotal counts containers inspected by
+	// pkg/containerscan, labeled by container engine (docker, podman).
+	MCPContainersScannedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mcp_containers_scanned_total",
+			Help: "Total number of containers inspected for MCP servers, by engine",
+		},
+		[]string{"engine"},
+	)
+
+	// ProctraceEventsTotal counts raw kernel events observed by pkg/proctrace's
+	// eBPF probes, labeled by event kind (exec, bind).
+	ProctraceEventsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "proctrace_events_total",
+			Help: "Total number of raw kernel events observed via eBPF, by kind",
+		},
+		[]string{"kind"},
+	)
+
+	// ProctraceProbeAttachTotal counts pkg/proctrace probe attach attempts,
+	// labeled by probe name and result (ok, error).
+	ProctraceProbeAttachTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "proctrace_probe_attach_total",
+			Help: "Total number of eBPF probe attach attempts, by probe and result",
+		},
+		[]string{"probe", "result"},
+	)
+
+	// FilesSkippedTotal counts manifest files pkg/filescan's incremental
+	// mode skipped rehashing because their (mtime, size) matched the
+	// persisted state from a prior cycle.
+	FilesSkippedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "scanner_files_skipped_total",
+			Help: "Total number of manifest files skipped by incremental scan because they were unchanged",
+		},
+	)
+
+	// FilesRehashedTotal counts manifest files pkg/filescan actually read
+	// and hashed: files incremental mode did not skip and that a detector
+	// recognized as an MCP manifest. Files no detector recognizes are not
+	// counted.
+	FilesRehashedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "scanner_files_rehashed_total",
+			Help: "Total number of manifest files read and hashed",
+		},
+	)
+
+	// WatcherQueueOverflowTotal counts filesystem events pkg/filescan's
+	// Watch dropped because its debounce queue or detections channel was
+	// full, rather than growing either unbounded under an event storm.
+	WatcherQueueOverflowTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "scanner_watcher_queue_overflow_total",
+			Help: "Total number of filesystem watch events dropped due to queue overflow",
+		},
+	)
+
+	// RedactionsPerDetection tracks how many secrets/PII values pkg/redact
+	// masked in a single detection's manifest snippet before it was
+	// published, so a spike here flags a source leaking credentials into
+	// its manifest rather than a scanner bug.
+	RedactionsPerDetection = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "scanner_redactions_per_detection",
+			Help:    "Number of secret/PII redactions applied to a single detection's manifest snippet",
+			Buckets: []float64{0, 1, 2, 3, 5, 8, 13},
+		},
+	)
+
+	// BuildInfo is a gauge set to 1, labeled with build metadata, so
+	// Prometheus join-queries can attribute other series to the binary
+	// version that produced them.
+	BuildInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mcpeeker_build_info",
+			Help: "Build metadata for the running scanner binary, value is always 1",
+		},
+		[]string{"version", "commit", "build_date", "go_version"},
+	)
 )
+
+// RecordBuildInfo sets the mcpeeker_build_info gauge for the current binary.
+// Call once at startup.
+func RecordBuildInfo(version, commit, buildDate, goVersion string) {
+	BuildInfo.WithLabelValues(version, commit, buildDate, goVersion).Set(1)
+}
+
+// MCPProcesses is the shared MCPProcessCollector every procscan pass feeds
+// via UpdateMCPProcesses (see pkg/probe/procscan), registered once here so
+// /metrics exposes it alongside the promauto-registered metrics above. If
+// /proc is unavailable (e.g. non-Linux), it falls back to an unregistered
+// collector: UpdateMCPProcesses calls remain safe no-ops rather than
+// failing package init.
+var MCPProcesses = newRegisteredMCPProcessCollector()
+
+func newRegisteredMCPProcessCollector() *MCPProcessCollector {
+	c, err := NewMCPProcessCollector()
+	if err != nil {
+		return &MCPProcessCollector{procs: make(map[int]trackedMCPProcess)}
+	}
+	prometheus.MustRegister(c)
+	return c
+}
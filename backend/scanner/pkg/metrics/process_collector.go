@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs"
+)
+
+// MCPProcessInfo is the subset of a procscan detection's labels
+// MCPProcessCollector needs to report resource gauges for it: see
+// procscan.Evidence.ProcessHash/Port.
+type MCPProcessInfo struct {
+	ProcessHash string
+	Port        int
+}
+
+type trackedMCPProcess struct {
+	hostID string
+	info   MCPProcessInfo
+}
+
+// MCPProcessCollector is a prometheus.Collector exposing per-process
+// resource gauges (resident memory, CPU time, open fds, start time) for
+// every MCP process most recently reported via UpdateMCPProcesses. Unlike
+// pkg/procscan's gopsutil-based detection, it reads /proc/[pid]/stat,
+// /proc/[pid]/status, and /proc/[pid]/fd directly on every Collect call, so
+// values are never a stale snapshot from the last scan — the same
+// freshness Prometheus's own process collector gives the exporter's own
+// PID, scoped here to every detected MCP server instead.
+type MCPProcessCollector struct {
+	fs procfs.FS
+
+	mu    sync.Mutex
+	procs map[int]trackedMCPProcess
+
+	residentMemory *prometheus.Desc
+	cpuSeconds     *prometheus.Desc
+	openFDs        *prometheus.Desc
+	startTime      *prometheus.Desc
+}
+
+// NewMCPProcessCollector creates a collector reading from the default /proc
+// mount point.
+func NewMCPProcessCollector() (*MCPProcessCollector, error) {
+	fs, err := procfs.NewDefaultFS()
+	if err != nil {
+		return nil, err
+	}
+
+	labels := []string{"host_id", "process_hash", "port"}
+	return &MCPProcessCollector{
+		fs:    fs,
+		procs: make(map[int]trackedMCPProcess),
+		residentMemory: prometheus.NewDesc(
+			"mcp_process_resident_memory_bytes",
+			"Resident memory of a detected MCP process, in bytes",
+			labels, nil,
+		),
+		cpuSeconds: prometheus.NewDesc(
+			"mcp_process_cpu_seconds_total",
+			"Total user+system CPU time consumed by a detected MCP process, in seconds",
+			labels, nil,
+		),
+		openFDs: prometheus.NewDesc(
+			"mcp_process_open_fds",
+			"Number of open file descriptors held by a detected MCP process",
+			labels, nil,
+		),
+		startTime: prometheus.NewDesc(
+			"mcp_process_start_time_seconds",
+			"Start time of a detected MCP process, in seconds since the Unix epoch",
+			labels, nil,
+		),
+	}, nil
+}
+
+// UpdateMCPProcesses replaces the set of PIDs the collector reports on.
+// Callers (see pkg/probe/procscan) call this after every scan pass with
+// that pass's detections; any PID tracked by a previous call but absent
+// from procs is dropped, so a gauge for an exited process stops being
+// reported instead of going stale until the process is scraped away.
+func (c *MCPProcessCollector) UpdateMCPProcesses(hostID string, procs map[int]MCPProcessInfo) {
+	tracked := make(map[int]trackedMCPProcess, len(procs))
+	for pid, info := range procs {
+		tracked[pid] = trackedMCPProcess{hostID: hostID, info: info}
+	}
+
+	c.mu.Lock()
+	c.procs = tracked
+	c.mu.Unlock()
+}
+
+// Describe implements prometheus.Collector.
+func (c *MCPProcessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.residentMemory
+	ch <- c.cpuSeconds
+	ch <- c.openFDs
+	ch <- c.startTime
+}
+
+// Collect implements prometheus.Collector. A PID that has exited since the
+// last scan simply yields no metrics for this scrape; it's pruned for good
+// the next time UpdateMCPProcesses runs.
+func (c *MCPProcessCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	procs := make(map[int]trackedMCPProcess, len(c.procs))
+	for pid, tp := range c.procs {
+		procs[pid] = tp
+	}
+	c.mu.Unlock()
+
+	for pid, tp := range procs {
+		proc, err := c.fs.Proc(pid)
+		if err != nil {
+			continue
+		}
+
+		labels := []string{tp.hostID, tp.info.ProcessHash, strconv.Itoa(tp.info.Port)}
+
+		if status, err := proc.NewStatus(); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.residentMemory, prometheus.GaugeValue, float64(status.VmRSS), labels...)
+		}
+
+		stat, err := proc.Stat()
+		if err != nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.cpuSeconds, prometheus.CounterValue, stat.CPUTime(), labels...)
+		if startTime, err := stat.StartTime(); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.startTime, prometheus.GaugeValue, startTime, labels...)
+		}
+
+		if fds, err := proc.FileDescriptorsLen(); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.openFDs, prometheus.GaugeValue, float64(fds), labels...)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package containerscan adapts pkg/containerscan.Scanner to the
+// pkg/probe.Probe interface, so cmd/scanner composes it through the
+// registry instead of calling into pkg/containerscan directly.
+package containerscan
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/containerscan"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/probe"
+)
+
+func init() {
+	probe.Register("containerscan", func() probe.Probe { return &containerScanProbe{} })
+}
+
+type containerScanProbe struct {
+	scanner *containerscan.Scanner
+}
+
+func (p *containerScanProbe) Name() string { return "containerscan" }
+
+func (p *containerScanProbe) Init(cfg probe.Config, hostID string) error {
+	// DockerSocket/PodmanSocket are left empty: containerscan.Scanner
+	// falls back to its own defaults/auto-discovery (see
+	// pkg/containerscan's defaultDockerSocket and XDG_RUNTIME_DIR
+	// handling), which the generic `probes` map has no field for today.
+	p.scanner = containerscan.NewScanner(containerscan.Config{
+		Enabled:          cfg.Enabled,
+		ManifestPatterns: cfg.Patterns,
+		ProcessPatterns:  cfg.Patterns,
+		MaxFileSizeBytes: cfg.MaxFileSize,
+	}, hostID, cfg.Logger)
+	return nil
+}
+
+func (p *containerScanProbe) Scan(ctx context.Context) ([]*probe.Detection, error) {
+	fileDetections, procDetections, err := p.scanner.Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*probe.Detection, 0, len(fileDetections)+len(procDetections))
+	for _, d := range fileDetections {
+		evidence, err := probe.ToEvidenceMap(d.Evidence)
+		if err != nil {
+			continue
+		}
+		out = append(out, &probe.Detection{
+			EventID: d.EventID, Timestamp: d.Timestamp, HostID: d.HostID,
+			DetectionType: d.DetectionType, Score: d.Score, Evidence: evidence,
+		})
+	}
+	for _, d := range procDetections {
+		evidence, err := probe.ToEvidenceMap(d.Evidence)
+		if err != nil {
+			continue
+		}
+		out = append(out, &probe.Detection{
+			EventID: d.EventID, Timestamp: d.Timestamp, HostID: d.HostID,
+			DetectionType: d.DetectionType, Score: d.Score, Evidence: evidence,
+		})
+	}
+	return out, nil
+}
+
+func (p *containerScanProbe) Metrics() []prometheus.Collector { return nil }
@@ -0,0 +1,19 @@
+package probe
+
+import "encoding/json"
+
+// ToEvidenceMap round-trips a concrete Evidence struct (procscan.Evidence,
+// filescan.Evidence, ...) through JSON into the generic map Detection
+// carries, so adapter probes don't each hand-roll the same field-by-field
+// copy.
+func ToEvidenceMap(evidence interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(evidence)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
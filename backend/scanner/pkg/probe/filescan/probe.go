@@ -0,0 +1,111 @@
+// Package filescan adapts pkg/filescan.Scanner to the pkg/probe.Probe
+// interface, so cmd/scanner composes it through the registry instead of
+// calling into pkg/filescan directly.
+package filescan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/filescan"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/metrics"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/probe"
+)
+
+func init() {
+	probe.Register("filescan", func() probe.Probe { return &fileScanProbe{} })
+}
+
+type fileScanProbe struct {
+	scanner *filescan.Scanner
+}
+
+func (p *fileScanProbe) Name() string { return "filescan" }
+
+func (p *fileScanProbe) Init(cfg probe.Config, hostID string) error {
+	p.scanner = filescan.NewScanner(cfg.FilesystemRoots, cfg.Patterns, cfg.MaxFileSize, hostID)
+	if len(cfg.Detectors) > 0 {
+		p.scanner.EnableDetectors(cfg.Detectors)
+	}
+	if cfg.StateDBPath != "" {
+		store, err := filescan.NewBoltStateStore(cfg.StateDBPath)
+		if err != nil {
+			return fmt.Errorf("filescan: opening incremental state db: %w", err)
+		}
+		p.scanner.State = store
+	}
+	return nil
+}
+
+func (p *fileScanProbe) Scan(ctx context.Context) ([]*probe.Detection, error) {
+	detections, err := p.scanner.Scan(ctx)
+	p.recordIncrementalMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	return convertDetections(detections), nil
+}
+
+// Watch implements probe.Watcher, translating pkg/filescan's fsnotify
+// watcher into probe.Detection until ctx is canceled.
+func (p *fileScanProbe) Watch(ctx context.Context, out chan<- *probe.Detection) error {
+	detections := make(chan *filescan.Detection, 64)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for d := range detections {
+			if converted := convertDetection(d); converted != nil {
+				out <- converted
+			}
+		}
+	}()
+
+	err := p.scanner.Watch(ctx, detections)
+	close(detections)
+	<-done
+	p.recordIncrementalMetrics()
+	return err
+}
+
+// recordIncrementalMetrics feeds whatever incremental-scan counts the
+// scanner has accumulated since the last call into the shared Prometheus
+// counters (see pkg/metrics); both Scan and Watch call it since both can
+// skip/rehash files and overflow the watch queue.
+func (p *fileScanProbe) recordIncrementalMetrics() {
+	metrics.FilesSkippedTotal.Add(float64(p.scanner.SkippedCount()))
+	metrics.FilesRehashedTotal.Add(float64(p.scanner.RehashedCount()))
+	metrics.WatcherQueueOverflowTotal.Add(float64(p.scanner.WatchQueueOverflowCount()))
+}
+
+func (p *fileScanProbe) Metrics() []prometheus.Collector { return nil }
+
+func convertDetections(detections []*filescan.Detection) []*probe.Detection {
+	out := make([]*probe.Detection, 0, len(detections))
+	for _, d := range detections {
+		if converted := convertDetection(d); converted != nil {
+			out = append(out, converted)
+		}
+	}
+	return out
+}
+
+func convertDetection(d *filescan.Detection) *probe.Detection {
+	evidence, err := probe.ToEvidenceMap(d.Evidence)
+	if err != nil {
+		return nil
+	}
+	if count, ok := d.Evidence.Metadata["redaction_count"].(int); ok {
+		metrics.RedactionsPerDetection.Observe(float64(count))
+	}
+	return &probe.Detection{
+		EventID:       d.EventID,
+		Timestamp:     d.Timestamp,
+		HostID:        d.HostID,
+		DetectionType: d.DetectionType,
+		Score:         d.Score,
+		Evidence:      evidence,
+	}
+}
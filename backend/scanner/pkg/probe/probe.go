@@ -0,0 +1,133 @@
+// Package probe defines the pluggable detection-probe interface every scan
+// source (procscan, filescan, containerscan, and any future site-specific
+// source) implements, plus the process-wide registry that composes them.
+// Modeled on kubeskoop's exporter registration: each probe package
+// self-registers a factory from its own init(), so cmd/scanner composes the
+// active probe set with blank imports (see pkg/probe/procscan,
+// pkg/probe/filescan, pkg/probe/containerscan) instead of calling into every
+// scanner package by name. Third parties can add a site-specific probe
+// (e.g. a container-runtime inspector) as a separate module without
+// touching this package or cmd/scanner's core logic.
+//
+// pkg/proctrace is deliberately not a Probe: it streams continuously rather
+// than running one scan per interval, so it keeps its own lifecycle in
+// cmd/scanner (see runProcessTracer) instead of fitting this poll-based
+// interface.
+package probe
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/logging"
+)
+
+// Detection is the wire shape every probe publishes. It matches
+// procscan/filescan/containerscan's existing Detection/Evidence JSON today;
+// Evidence is a generic map rather than a shared struct so each probe can
+// carry whatever fields are specific to its detection source.
+type Detection struct {
+	EventID       string                 `json:"event_id"`
+	Timestamp     time.Time              `json:"timestamp"`
+	HostID        string                 `json:"host_id"`
+	DetectionType string                 `json:"detection_type"`
+	Score         int                    `json:"score"`
+	Evidence      map[string]interface{} `json:"evidence"`
+}
+
+// Config holds one probe's settings. Enabled/Interval/Patterns come from
+// scanner.yaml's `probes` map (see pkg/config); FilesystemRoots/MaxFileSize
+// are scanner-wide settings cmd/scanner merges in from FlatConfig for the
+// probes that need them (filescan, containerscan). Probes interpret these
+// however fits their source; not every field applies to every probe.
+type Config struct {
+	Enabled         bool
+	Interval        time.Duration
+	Patterns        []string
+	FilesystemRoots []string
+	MaxFileSize     int64
+	MaxProcesses    int
+	PortPatterns    []string
+	// Detectors narrows which of a probe's pluggable sub-detectors run
+	// (currently only filescan's ManifestDetectors; see
+	// pkg/filescan.Scanner.EnableDetectors). Empty leaves the probe's
+	// default set enabled.
+	Detectors []string
+	// StateDBPath is the BoltDB file a probe with persistent incremental
+	// state (currently only filescan; see pkg/filescan.Scanner.State)
+	// uses to skip files unchanged since the last scan. Empty disables
+	// incremental mode.
+	StateDBPath string
+	// Watch enables a probe's continuous, event-driven mode (see
+	// Watcher) alongside its periodic Scan. Ignored by probes that don't
+	// implement Watcher.
+	Watch  bool
+	Logger logging.Logger
+}
+
+// Probe is one pluggable detection source.
+type Probe interface {
+	// Name identifies the probe in the `probes` config map, logs, and
+	// metrics (e.g. "procscan", "filescan").
+	Name() string
+	// Init configures the probe before its first Scan. Called once, after
+	// config load, with this probe's entry from the `probes` map and the
+	// resolved host ID.
+	Init(cfg Config, hostID string) error
+	// Scan runs one detection pass.
+	Scan(ctx context.Context) ([]*Detection, error)
+	// Metrics returns any Prometheus collectors the probe wants registered
+	// alongside the core scanner metrics. A probe with nothing
+	// probe-specific to export can return nil.
+	Metrics() []prometheus.Collector
+}
+
+// Watcher is an optional capability a Probe implements when it supports a
+// continuous, event-driven detection mode alongside its periodic Scan
+// (currently only filescan's inotify/FSEvents watcher). cmd/scanner
+// starts Watch in its own goroutine for any configured probe whose
+// Config.Watch is true and that implements this interface; Watch should
+// block, sending a Detection to out as each is found, until ctx is
+// canceled.
+type Watcher interface {
+	Watch(ctx context.Context, out chan<- *Detection) error
+}
+
+// Factory constructs a fresh, unconfigured Probe instance.
+type Factory func() Probe
+
+var (
+	mu        sync.Mutex
+	factories = map[string]Factory{}
+	order     []string
+)
+
+// Register adds a probe factory under name. Probe packages call this from
+// their own init(), so the registry never needs to import their concrete
+// types — only that the probe package is blank-imported by cmd/scanner.
+// Register panics on a duplicate name: that's a build-time wiring mistake,
+// not a runtime condition to recover from.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, exists := factories[name]; exists {
+		panic("probe: duplicate registration for " + name)
+	}
+	factories[name] = factory
+	order = append(order, name)
+}
+
+// Registered returns one freshly constructed instance of every registered
+// probe, in registration order, for deterministic startup logs.
+func Registered() []Probe {
+	mu.Lock()
+	defer mu.Unlock()
+	probes := make([]Probe, 0, len(order))
+	for _, name := range order {
+		probes = append(probes, factories[name]())
+	}
+	return probes
+}
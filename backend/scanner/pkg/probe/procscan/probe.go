@@ -0,0 +1,72 @@
+// Package procscan adapts pkg/procscan.Scanner to the pkg/probe.Probe
+// interface, so cmd/scanner composes it through the registry instead of
+// calling into pkg/procscan directly.
+package procscan
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/metrics"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/probe"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/procscan"
+)
+
+func init() {
+	probe.Register("procscan", func() probe.Probe { return &procScanProbe{} })
+}
+
+// procScanProbe wraps a procscan.Scanner, built once Init supplies the
+// patterns and host ID that FlatConfig used to pass to
+// procscan.NewScanner directly.
+type procScanProbe struct {
+	scanner *procscan.Scanner
+	hostID  string
+}
+
+func (p *procScanProbe) Name() string { return "procscan" }
+
+func (p *procScanProbe) Init(cfg probe.Config, hostID string) error {
+	p.scanner = procscan.NewScanner(cfg.Patterns, cfg.PortPatterns, cfg.MaxProcesses, hostID)
+	p.hostID = hostID
+	return nil
+}
+
+func (p *procScanProbe) Scan(ctx context.Context) ([]*probe.Detection, error) {
+	detections, err := p.scanner.Scan()
+	if err != nil {
+		return nil, err
+	}
+
+	// Feed this pass's PIDs to metrics.MCPProcesses so its per-process
+	// resource gauges (see pkg/metrics/process_collector.go) track exactly
+	// the MCP processes this probe last found.
+	tracked := make(map[int]metrics.MCPProcessInfo, len(detections))
+
+	out := make([]*probe.Detection, 0, len(detections))
+	for _, d := range detections {
+		tracked[int(d.Evidence.ProcessID)] = metrics.MCPProcessInfo{
+			ProcessHash: d.Evidence.ProcessHash,
+			Port:        d.Evidence.Port,
+		}
+
+		evidence, err := probe.ToEvidenceMap(d.Evidence)
+		if err != nil {
+			continue
+		}
+		out = append(out, &probe.Detection{
+			EventID:       d.EventID,
+			Timestamp:     d.Timestamp,
+			HostID:        d.HostID,
+			DetectionType: d.DetectionType,
+			Score:         d.Score,
+			Evidence:      evidence,
+		})
+	}
+	metrics.MCPProcesses.UpdateMCPProcesses(p.hostID, tracked)
+
+	return out, nil
+}
+
+func (p *procScanProbe) Metrics() []prometheus.Collector { return nil }
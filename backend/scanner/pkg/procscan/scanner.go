@@ -14,39 +14,40 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/buildinfo"
 	"github.com/shirou/gopsutil/v3/process"
 )
 
 // Detection represents a detected MCP server process
 type Detection struct {
-	EventID      string    `json:"event_id"`
-	Timestamp    time.Time `json:"timestamp"`
-	HostID       string    `json:"host_id"`
-	DetectionType string   `json:"detection_type"`
-	Score        int       `json:"score"`
-	Evidence     Evidence  `json:"evidence"`
+	EventID       string    `json:"event_id"`
+	Timestamp     time.Time `json:"timestamp"`
+	HostID        string    `json:"host_id"`
+	DetectionType string    `json:"detection_type"`
+	Score         int       `json:"score"`
+	Evidence      Evidence  `json:"evidence"`
 }
 
 // Evidence contains detailed information about the detection
 type Evidence struct {
-	Source    string            `json:"source"`
-	ProcessID int32             `json:"process_id"`
-	ProcessName string          `json:"process_name"`
-	CommandLine string          `json:"command_line"`
-	BinaryPath  string          `json:"binary_path"`
-	ProcessHash string          `json:"process_hash"`
-	Port        int               `json:"port,omitempty"`
-	Snippet     string            `json:"snippet"`
+	Source      string                 `json:"source"`
+	ProcessID   int32                  `json:"process_id"`
+	ProcessName string                 `json:"process_name"`
+	CommandLine string                 `json:"command_line"`
+	BinaryPath  string                 `json:"binary_path"`
+	ProcessHash string                 `json:"process_hash"`
+	Port        int                    `json:"port,omitempty"`
+	Snippet     string                 `json:"snippet"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // Scanner scans running processes for MCP servers
 type Scanner struct {
-	MCPPatterns     []string
-	PortPatterns    []string
-	MaxProcesses    int
-	HostID          string
-	ScannerVersion  string
+	MCPPatterns    []string
+	PortPatterns   []string
+	MaxProcesses   int
+	HostID         string
+	ScannerVersion string
 }
 
 // NewScanner creates a new process scanner
@@ -56,7 +57,7 @@ func NewScanner(patterns []string, portPatterns []string, maxProcs int, hostID s
 		PortPatterns:   portPatterns,
 		MaxProcesses:   maxProcs,
 		HostID:         hostID,
-		ScannerVersion: "scanner-v1.0.0",
+		ScannerVersion: "scanner-" + buildinfo.Version,
 	}
 }
 
@@ -138,11 +139,11 @@ func (s *Scanner) analyzeProcess(proc *process.Process) (*Detection, error) {
 
 	// Create detection
 	detection := &Detection{
-		EventID:      uuid.New().String(),
-		Timestamp:    time.Now().UTC(),
-		HostID:       s.HostID,
+		EventID:       uuid.New().String(),
+		Timestamp:     time.Now().UTC(),
+		HostID:        s.HostID,
 		DetectionType: "process",
-		Score:        score,
+		Score:         score,
 		Evidence: Evidence{
 			Source:      s.ScannerVersion,
 			ProcessID:   pid,
@@ -164,12 +165,21 @@ func (s *Scanner) analyzeProcess(proc *process.Process) (*Detection, error) {
 
 // looksLikeMCPServer checks if process looks like an MCP server
 func (s *Scanner) looksLikeMCPServer(name string, cmdline string) bool {
+	return LooksLikeMCPServer(s.MCPPatterns, name, cmdline)
+}
+
+// LooksLikeMCPServer reports whether a process name/cmdline pair looks like
+// an MCP server, matching it against patterns plus this package's built-in
+// indicators. Exported so other detection sources that never go through
+// Scanner (e.g. pkg/proctrace's eBPF exec events, which observe comm/argv
+// directly) can reuse the same classification instead of duplicating it.
+func LooksLikeMCPServer(patterns []string, name string, cmdline string) bool {
 	// Convert to lowercase for case-insensitive matching
 	nameLower := strings.ToLower(name)
 	cmdlineLower := strings.ToLower(cmdline)
 
 	// Check against MCP patterns
-	for _, pattern := range s.MCPPatterns {
+	for _, pattern := range patterns {
 		matched, _ := regexp.MatchString(pattern, nameLower)
 		if matched {
 			return true
@@ -200,8 +210,8 @@ func (s *Scanner) looksLikeMCPServer(name string, cmdline string) bool {
 	}
 
 	// Check for common MCP server languages with MCP-related args
-	if (strings.Contains(nameLower, "node") || strings.Contains(nameLower, "python") ||
-	    strings.Contains(nameLower, "go") || strings.Contains(nameLower, "java")) {
+	if strings.Contains(nameLower, "node") || strings.Contains(nameLower, "python") ||
+		strings.Contains(nameLower, "go") || strings.Contains(nameLower, "java") {
 		mcpKeywords := []string{"mcp", "model-context-protocol", "anthropic"}
 		for _, keyword := range mcpKeywords {
 			if strings.Contains(cmdlineLower, keyword) {
@@ -262,8 +272,8 @@ func (s *Scanner) generateProcessHash(binaryPath string, cmdline string) string
 // createSnippet creates a JSON snippet of process information
 func (s *Scanner) createSnippet(name string, cmdline string) string {
 	snippet := map[string]interface{}{
-		"process_name": name,
-		"command_line": cmdline,
+		"process_name":     name,
+		"command_line":     cmdline,
 		"detection_method": "process_scan",
 	}
 
@@ -295,8 +305,8 @@ func (s *Scanner) calculateScore(name string, cmdline string, port int) int {
 
 	// Protocol-specific transport modes
 	if strings.Contains(cmdlineLower, "stdio") ||
-	   strings.Contains(cmdlineLower, "sse") ||
-	   strings.Contains(cmdlineLower, "websocket") {
+		strings.Contains(cmdlineLower, "sse") ||
+		strings.Contains(cmdlineLower, "websocket") {
 		baseScore += 1
 	}
 
@@ -0,0 +1,10 @@
+//go:build linux
+
+// Package bpf holds the compiled eBPF objects for pkg/proctrace, generated
+// from tracepoints.c by bpf2go. Run `go generate ./...` (with clang and
+// libbpf headers available) to regenerate execprobe_bpf*.go/.o and
+// netbindprobe_bpf*.go/.o after editing tracepoints.c.
+package bpf
+
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -target amd64,arm64 -cc clang execProbe tracepoints.c -- -I/usr/include/bpf
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -target amd64,arm64 -cc clang netbindProbe tracepoints.c -- -I/usr/include/bpf
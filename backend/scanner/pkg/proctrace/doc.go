@@ -0,0 +1,25 @@
+//go:build linux
+
+// Package proctrace streams real-time process exec and network-bind events
+// from the kernel via eBPF, as a low-latency complement to
+// pkg/procscan's 12-hour polling cycle. A short-lived MCP server that
+// starts and exits between two poll cycles is invisible to procscan;
+// proctrace catches it the moment it execs.
+//
+// Each probe (one per tracepoint, see probe_exec.go and probe_netbind.go)
+// self-registers into a package-level registry at init time, modeled on
+// the kubeskoop tracer convention, so adding a new probe is a matter of
+// dropping in a file rather than touching Tracer. Tracer attaches every
+// registered probe, merges their event streams, and turns matching exec
+// events into the same procscan.Detection/Evidence types procscan already
+// publishes — the correlator consumer needs no changes (see
+// pkg/publisher, which doesn't care which scanner subsystem produced a
+// Detection).
+//
+// Each probe tries its tracepoint first (sched_process_exec for exec,
+// sys_enter_bind for bind) and falls back to an equivalent kprobe
+// (__x64_sys_execve, __x64_sys_bind) when the running kernel doesn't
+// expose the tracepoint. Config.Enabled also lets operators disable eBPF entirely
+// and rely on procscan's polling alone, e.g. on kernels too old for any
+// of this or in containers without CAP_BPF.
+package proctrace
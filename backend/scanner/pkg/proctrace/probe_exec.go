@@ -0,0 +1,115 @@
+//go:build linux
+
+package proctrace
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/ringbuf"
+	"github.com/cilium/ebpf/rlimit"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/proctrace/bpf"
+)
+
+func init() {
+	register(&execProbe{})
+}
+
+// execProbe attaches to sched_process_exec (falling back to a kprobe on
+// __x64_sys_execve) and reports every process exec on the host.
+type execProbe struct{}
+
+func (p *execProbe) Name() string { return "exec" }
+
+func (p *execProbe) Attach() (<-chan RawEvent, func() error, error) {
+	if err := rlimit.RemoveMemlock(); err != nil {
+		return nil, nil, fmt.Errorf("remove memlock rlimit: %w", err)
+	}
+
+	objs := bpf.ExecProbeObjects{}
+	if err := bpf.LoadExecProbeObjects(&objs, nil); err != nil {
+		return nil, nil, fmt.Errorf("load exec probe objects: %w", err)
+	}
+
+	tp, err := link.Tracepoint("sched", "sched_process_exec", objs.TraceSchedProcessExec, nil)
+	fallback := false
+	if err != nil {
+		tp, err = link.Kprobe("__x64_sys_execve", objs.KprobeExecve, nil)
+		fallback = true
+		if err != nil {
+			objs.Close()
+			return nil, nil, fmt.Errorf("attach exec tracepoint and kprobe fallback: %w", err)
+		}
+	}
+	_ = fallback // not reported: Tracer logs only whether each probe attached
+
+	reader, err := ringbuf.NewReader(objs.ExecEvents)
+	if err != nil {
+		tp.Close()
+		objs.Close()
+		return nil, nil, fmt.Errorf("open exec ringbuf: %w", err)
+	}
+
+	events := make(chan RawEvent, 256)
+	done := make(chan struct{})
+	go func() {
+		defer close(events)
+		for {
+			record, err := reader.Read()
+			if err != nil {
+				select {
+				case <-done:
+					return
+				default:
+					continue
+				}
+			}
+			ev, ok := decodeExecEvent(record.RawSample)
+			if !ok {
+				continue
+			}
+			events <- ev
+		}
+	}()
+
+	closer := func() error {
+		close(done)
+		reader.Close()
+		tp.Close()
+		objs.Close()
+		return nil
+	}
+	return events, closer, nil
+}
+
+// execEvent mirrors bpf/tracepoints.c's struct exec_event.
+type execEvent struct {
+	PID  uint32
+	Comm [16]byte
+	Argv [256]byte
+}
+
+func decodeExecEvent(raw []byte) (RawEvent, bool) {
+	var e execEvent
+	if err := binary.Read(bytes.NewReader(raw), binary.LittleEndian, &e); err != nil {
+		return RawEvent{}, false
+	}
+	comm := cString(e.Comm[:])
+	argv := cString(e.Argv[:])
+	return RawEvent{
+		Kind: "exec",
+		PID:  int32(e.PID),
+		Comm: comm,
+		Argv: []string{argv},
+	}, true
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
@@ -0,0 +1,108 @@
+//go:build linux
+
+package proctrace
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/ringbuf"
+	"github.com/cilium/ebpf/rlimit"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/proctrace/bpf"
+)
+
+func init() {
+	register(&netbindProbe{})
+}
+
+// netbindProbe attaches to sys_enter_bind (falling back to a kprobe on
+// __x64_sys_bind) and reports the port every process binds to. Tracer
+// records it per PID and attaches it to a later exec detection from the
+// same PID; a bind that follows the exec is not added to that detection.
+type netbindProbe struct{}
+
+func (p *netbindProbe) Name() string { return "netbind" }
+
+func (p *netbindProbe) Attach() (<-chan RawEvent, func() error, error) {
+	if err := rlimit.RemoveMemlock(); err != nil {
+		return nil, nil, fmt.Errorf("remove memlock rlimit: %w", err)
+	}
+
+	objs := bpf.NetbindProbeObjects{}
+	if err := bpf.LoadNetbindProbeObjects(&objs, nil); err != nil {
+		return nil, nil, fmt.Errorf("load netbind probe objects: %w", err)
+	}
+
+	tp, err := link.Tracepoint("syscalls", "sys_enter_bind", objs.TraceSysEnterBind, nil)
+	fallback := false
+	if err != nil {
+		tp, err = link.Kprobe("__x64_sys_bind", objs.KprobeBind, nil)
+		fallback = true
+		if err != nil {
+			objs.Close()
+			return nil, nil, fmt.Errorf("attach bind tracepoint and kprobe fallback: %w", err)
+		}
+	}
+	_ = fallback // not reported: Tracer logs only whether each probe attached
+
+	reader, err := ringbuf.NewReader(objs.BindEvents)
+	if err != nil {
+		tp.Close()
+		objs.Close()
+		return nil, nil, fmt.Errorf("open bind ringbuf: %w", err)
+	}
+
+	events := make(chan RawEvent, 256)
+	done := make(chan struct{})
+	go func() {
+		defer close(events)
+		for {
+			record, err := reader.Read()
+			if err != nil {
+				select {
+				case <-done:
+					return
+				default:
+					continue
+				}
+			}
+			ev, ok := decodeBindEvent(record.RawSample)
+			if !ok {
+				continue
+			}
+			events <- ev
+		}
+	}()
+
+	closer := func() error {
+		close(done)
+		reader.Close()
+		tp.Close()
+		objs.Close()
+		return nil
+	}
+	return events, closer, nil
+}
+
+// bindEvent mirrors bpf/tracepoints.c's struct bind_event.
+type bindEvent struct {
+	PID  uint32
+	Comm [16]byte
+	Port uint16
+}
+
+func decodeBindEvent(raw []byte) (RawEvent, bool) {
+	var e bindEvent
+	if err := binary.Read(bytes.NewReader(raw), binary.LittleEndian, &e); err != nil {
+		return RawEvent{}, false
+	}
+	return RawEvent{
+		Kind: "bind",
+		PID:  int32(e.PID),
+		Comm: cString(e.Comm[:]),
+		Port: int(e.Port),
+	}, true
+}
@@ -0,0 +1,56 @@
+//go:build linux
+
+package proctrace
+
+import "sync"
+
+// RawEvent is a single kernel event surfaced by a Probe, already decoded
+// from its eBPF ring buffer record.
+type RawEvent struct {
+	// Kind is "exec" or "bind"; Tracer dispatches on it.
+	Kind string
+	PID  int32
+	// Comm is the kernel's truncated (16-byte) process name.
+	Comm string
+	// Argv is only populated for "exec" events.
+	Argv []string
+	// Port is only populated for "bind" events.
+	Port int
+}
+
+// Probe attaches one eBPF program (tracepoint, falling back to a kprobe)
+// and streams the events it captures.
+type Probe interface {
+	// Name identifies the probe in logs and metrics, e.g. "exec",
+	// "netbind".
+	Name() string
+	// Attach loads and attaches the probe's eBPF program(s), returning a
+	// channel of decoded events and a closer to detach and release kernel
+	// resources. Attach should try its tracepoint first and fall back to
+	// an equivalent kprobe if the tracepoint isn't available on this
+	// kernel.
+	Attach() (events <-chan RawEvent, closer func() error, err error)
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []Probe
+)
+
+// register adds p to the package-level probe registry. Probes call this
+// from their own init(), so Tracer never needs to know the concrete probe
+// types — only that the registry package was imported.
+func register(p Probe) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, p)
+}
+
+// registeredProbes returns a snapshot of every self-registered probe.
+func registeredProbes() []Probe {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	probes := make([]Probe, len(registry))
+	copy(probes, registry)
+	return probes
+}
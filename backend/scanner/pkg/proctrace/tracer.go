@@ -0,0 +1,183 @@
+//go:build linux
+
+package proctrace
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/buildinfo"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/logging"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/metrics"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/procscan"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/publisher"
+)
+
+// Config controls the real-time eBPF tracer. See Tracer.
+type Config struct {
+	// Enabled turns on eBPF tracing. Disabled by default: it requires
+	// CAP_BPF/CAP_PERFMON (or root) and a 4.18+ kernel, neither of which
+	// every deployment target has.
+	Enabled bool
+
+	// MCPPatterns are matched against each exec event's comm/argv using
+	// the same rules as pkg/procscan (see procscan.LooksLikeMCPServer).
+	MCPPatterns []string
+}
+
+// Tracer attaches every self-registered Probe, merges their event streams,
+// and turns matching exec events into procscan.Detection/Evidence so the
+// correlator needs no changes to consume them. Unlike pkg/procscan, which
+// polls /proc once per ScanInterval, Tracer runs continuously for the
+// lifetime of the scanner process.
+type Tracer struct {
+	cfg    Config
+	hostID string
+	logger logging.Logger
+}
+
+// NewTracer creates a Tracer. A nil logger falls back to a discarding
+// logger.
+func NewTracer(cfg Config, hostID string, logger logging.Logger) *Tracer {
+	if logger == nil {
+		logger = logging.Noop()
+	}
+	return &Tracer{cfg: cfg, hostID: hostID, logger: logger}
+}
+
+// Run attaches every registered probe and publishes matching exec events as
+// detections until ctx is cancelled. It returns nil on a clean shutdown; a
+// probe that fails to attach is logged and skipped rather than treated as
+// fatal, so e.g. a kernel without netbind tracepoint support still gets
+// exec coverage.
+func (t *Tracer) Run(ctx context.Context, pub *publisher.Publisher) error {
+	if !t.cfg.Enabled {
+		t.logger.Info("eBPF tracing disabled, relying on procscan polling only")
+		return nil
+	}
+
+	probes := registeredProbes()
+	merged := make(chan RawEvent, 256)
+	var closers []func() error
+	attached := 0
+
+	for _, p := range probes {
+		events, closer, err := p.Attach()
+		if err != nil {
+			t.logger.Warn("failed to attach probe, skipping", "probe", p.Name(), "error", err)
+			metrics.ProctraceProbeAttachTotal.WithLabelValues(p.Name(), "error").Inc()
+			continue
+		}
+		attached++
+		closers = append(closers, closer)
+		metrics.ProctraceProbeAttachTotal.WithLabelValues(p.Name(), "ok").Inc()
+		go forwardEvents(ctx, events, merged)
+	}
+
+	if attached == 0 {
+		t.logger.Warn("no eBPF probes attached, relying on procscan polling only")
+		return nil
+	}
+	t.logger.Info("eBPF tracer attached", "probes", attached)
+
+	defer func() {
+		for _, closer := range closers {
+			if err := closer(); err != nil {
+				t.logger.Warn("failed to detach probe", "error", err)
+			}
+		}
+	}()
+
+	// pendingPorts remembers the most recent bind port per PID so an exec
+	// event that arrives after its process has already bound a listener
+	// carries the port. A bind that arrives after the exec event only
+	// updates this map; the detection already published keeps port 0.
+	pendingPorts := map[int32]int{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-merged:
+			if !ok {
+				return nil
+			}
+			t.handleEvent(ctx, ev, pub, pendingPorts)
+		}
+	}
+}
+
+func (t *Tracer) handleEvent(ctx context.Context, ev RawEvent, pub *publisher.Publisher, pendingPorts map[int32]int) {
+	metrics.ProctraceEventsTotal.WithLabelValues(ev.Kind).Inc()
+
+	switch ev.Kind {
+	case "bind":
+		pendingPorts[ev.PID] = ev.Port
+		return
+	case "exec":
+		cmdline := strings.Join(ev.Argv, " ")
+		if !procscan.LooksLikeMCPServer(t.cfg.MCPPatterns, ev.Comm, cmdline) {
+			return
+		}
+		detection := t.buildDetection(ev, cmdline, pendingPorts[ev.PID])
+		if err := pub.PublishDetection(ctx, detection); err != nil {
+			t.logger.Error("failed to publish realtime detection", "error", err)
+			metrics.ScanErrorsTotal.WithLabelValues("publish").Inc()
+			return
+		}
+		metrics.EventPublishedTotal.WithLabelValues("process_realtime").Inc()
+		metrics.DetectionsFoundTotal.WithLabelValues("process_realtime").Inc()
+	}
+}
+
+// buildDetection mirrors procscan.Scanner.analyzeProcess's Detection shape,
+// so the correlator's handling doesn't change regardless of which scanner
+// subsystem produced the event.
+func (t *Tracer) buildDetection(ev RawEvent, cmdline string, port int) *procscan.Detection {
+	snippet := cmdline
+	if len(snippet) > 1024 {
+		snippet = snippet[:1024]
+	}
+
+	return &procscan.Detection{
+		EventID:       uuid.New().String(),
+		Timestamp:     time.Now().UTC(),
+		HostID:        t.hostID,
+		DetectionType: "process",
+		Score:         70, // exec-time match only; no port/file corroboration yet
+		Evidence: procscan.Evidence{
+			Source:      "proctrace-ebpf-" + buildinfo.Version,
+			ProcessID:   ev.PID,
+			ProcessName: ev.Comm,
+			CommandLine: cmdline,
+			Port:        port,
+			Snippet:     snippet,
+			Metadata: map[string]interface{}{
+				"realtime": true,
+			},
+		},
+	}
+}
+
+// forwardEvents copies events from a single probe's channel onto the
+// merged channel until ctx is cancelled or the probe channel closes.
+func forwardEvents(ctx context.Context, events <-chan RawEvent, merged chan<- RawEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			select {
+			case merged <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+//go:build !linux
+
+// Package proctrace's eBPF probes are Linux-only (tracepoints/kprobes and
+// the cilium/ebpf loader have no equivalent on other platforms). This file
+// provides the same Config/Tracer surface on other GOOS values so
+// cmd/scanner can depend on the package unconditionally; Run always logs
+// and returns nil, leaving procscan's polling as the only detection path.
+package proctrace
+
+import (
+	"context"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/logging"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/publisher"
+)
+
+// Config mirrors the Linux build's Config so callers don't need a build tag
+// of their own.
+type Config struct {
+	Enabled     bool
+	MCPPatterns []string
+}
+
+// Tracer is a no-op on non-Linux platforms.
+type Tracer struct {
+	logger logging.Logger
+}
+
+// NewTracer creates a no-op Tracer. A nil logger falls back to a discarding
+// logger.
+func NewTracer(cfg Config, hostID string, logger logging.Logger) *Tracer {
+	if logger == nil {
+		logger = logging.Noop()
+	}
+	return &Tracer{logger: logger}
+}
+
+// Run logs that eBPF tracing isn't available on this platform and returns
+// immediately.
+func (t *Tracer) Run(ctx context.Context, pub *publisher.Publisher) error {
+	t.logger.Info("eBPF tracing not supported on this platform, relying on procscan polling only")
+	return nil
+}
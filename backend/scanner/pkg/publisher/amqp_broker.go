@@ -0,0 +1,209 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// AMQPBroker implements MessageBroker over RabbitMQ using publisher
+// confirms, so Publish/PublishAsync give the same ack-or-error semantics as
+// NATSBroker's JetStream acks.
+type AMQPBroker struct {
+	conn         *amqp.Connection
+	ch           *amqp.Channel
+	exchange     string
+	routingKey   string
+	deliveryMode uint8
+
+	mu      sync.Mutex
+	pending map[uint64]*amqpAckHandle
+}
+
+// newAMQPBroker dials RabbitMQ, enables publisher confirms, and declares
+// config.AMQPExchange if one was given.
+func newAMQPBroker(config *Config) (*AMQPBroker, error) {
+	var conn *amqp.Connection
+	var err error
+	if config.TLSEnabled {
+		tlsConfig, tlsErr := loadTLSConfig(config.TLSCertFile, config.TLSKeyFile, config.TLSCAFile)
+		if tlsErr != nil {
+			return nil, fmt.Errorf("mTLS enabled but failed to load certificate bundle: %w", tlsErr)
+		}
+		conn, err = amqp.DialTLS(config.AMQPUrl, tlsConfig)
+	} else {
+		conn, err = amqp.Dial(config.AMQPUrl)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to AMQP broker: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open AMQP channel: %w", err)
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("failed to enable AMQP publisher confirms: %w", err)
+	}
+
+	if config.AMQPExchange != "" {
+		if err := ch.ExchangeDeclare(config.AMQPExchange, "topic", true, false, false, false, nil); err != nil {
+			ch.Close()
+			conn.Close()
+			return nil, fmt.Errorf("failed to declare AMQP exchange: %w", err)
+		}
+	}
+
+	deliveryMode := amqp.Transient
+	if config.AMQPPersistent {
+		deliveryMode = amqp.Persistent
+	}
+
+	b := &AMQPBroker{
+		conn:         conn,
+		ch:           ch,
+		exchange:     config.AMQPExchange,
+		routingKey:   config.AMQPRoutingKey,
+		deliveryMode: uint8(deliveryMode),
+		pending:      make(map[uint64]*amqpAckHandle),
+	}
+
+	go b.watchConfirms(ch.NotifyPublish(make(chan amqp.Confirmation, 64)))
+
+	return b, nil
+}
+
+// amqpAckHandle adapts RabbitMQ publisher confirms to the broker-agnostic
+// AckHandle.
+type amqpAckHandle struct {
+	ok  chan struct{}
+	err chan error
+}
+
+func (h *amqpAckHandle) Ok() <-chan struct{} {
+	return h.ok
+}
+
+func (h *amqpAckHandle) Err() <-chan error {
+	return h.err
+}
+
+// watchConfirms resolves pending ack handles as RabbitMQ confirms deliveries.
+func (b *AMQPBroker) watchConfirms(confirms <-chan amqp.Confirmation) {
+	for confirm := range confirms {
+		b.mu.Lock()
+		handle, ok := b.pending[confirm.DeliveryTag]
+		delete(b.pending, confirm.DeliveryTag)
+		b.mu.Unlock()
+		if !ok {
+			continue
+		}
+		if confirm.Ack {
+			close(handle.ok)
+		} else {
+			handle.err <- fmt.Errorf("broker nacked delivery tag %d", confirm.DeliveryTag)
+		}
+	}
+}
+
+// contentTypeFor maps an event-codec header value to an AMQP content type,
+// defaulting to JSON for an empty/unknown codec.
+func contentTypeFor(codecName string) string {
+	switch codecName {
+	case "protobuf":
+		return "application/x-protobuf"
+	case "cbor":
+		return "application/cbor"
+	default:
+		return "application/json"
+	}
+}
+
+// routingKeyFor falls back to the caller-supplied subject when no fixed
+// routing key is configured, mirroring how NATS subjects map 1:1 to subjects.
+func (b *AMQPBroker) routingKeyFor(subject string) string {
+	if b.routingKey != "" {
+		return b.routingKey
+	}
+	return subject
+}
+
+// PublishAsync publishes without blocking for the broker confirm.
+func (b *AMQPBroker) PublishAsync(ctx context.Context, subject string, payload []byte, headers map[string]string) (AckHandle, error) {
+	tag := b.ch.GetNextPublishSeqNo()
+	handle := &amqpAckHandle{ok: make(chan struct{}), err: make(chan error, 1)}
+
+	b.mu.Lock()
+	b.pending[tag] = handle
+	b.mu.Unlock()
+
+	amqpHeaders := make(amqp.Table, len(headers))
+	for k, v := range headers {
+		amqpHeaders[k] = v
+	}
+
+	err := b.ch.PublishWithContext(ctx, b.exchange, b.routingKeyFor(subject), false, false, amqp.Publishing{
+		ContentType:  contentTypeFor(headers["event-codec"]),
+		Headers:      amqpHeaders,
+		Body:         payload,
+		DeliveryMode: b.deliveryMode,
+		Timestamp:    time.Now(),
+	})
+	if err != nil {
+		b.mu.Lock()
+		delete(b.pending, tag)
+		b.mu.Unlock()
+		return nil, fmt.Errorf("failed to publish to AMQP: %w", err)
+	}
+
+	return handle, nil
+}
+
+// Publish publishes and blocks until the broker confirms or rejects delivery.
+func (b *AMQPBroker) Publish(ctx context.Context, subject string, payload []byte, headers map[string]string) error {
+	handle, err := b.PublishAsync(ctx, subject, payload, headers)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-handle.Ok():
+		return nil
+	case err := <-handle.Err():
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StreamInfo reports backlog depth for the named queue.
+func (b *AMQPBroker) StreamInfo(name string) (*BrokerStreamInfo, error) {
+	q, err := b.ch.QueueInspect(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect AMQP queue: %w", err)
+	}
+	return &BrokerStreamInfo{Name: q.Name, Messages: uint64(q.Messages)}, nil
+}
+
+// Close closes the AMQP channel and connection.
+func (b *AMQPBroker) Close() error {
+	if b.ch != nil {
+		b.ch.Close()
+	}
+	if b.conn != nil {
+		return b.conn.Close()
+	}
+	return nil
+}
+
+// Connected reports whether the AMQP connection is currently open.
+func (b *AMQPBroker) Connected() bool {
+	return b.conn != nil && !b.conn.IsClosed()
+}
@@ -0,0 +1,63 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+)
+
+// BrokerStreamInfo is a broker-agnostic summary of a stream/queue, enough for
+// the health endpoint to report backlog depth regardless of backend.
+type BrokerStreamInfo struct {
+	Name     string
+	Messages uint64
+}
+
+// AckHandle represents an in-flight async publish, resolved once the broker
+// confirms or rejects delivery.
+type AckHandle interface {
+	Ok() <-chan struct{}
+	Err() <-chan error
+}
+
+// MessageBroker abstracts the transport Publisher sends detection events
+// over, so a deployment can swap NATS JetStream for RabbitMQ (or anything
+// else) via Config.BrokerType without touching call sites.
+type MessageBroker interface {
+	// Publish sends payload to subject and waits for broker acknowledgment.
+	// headers carries transport-agnostic metadata (e.g. event-codec,
+	// event-type) alongside the payload; it may be nil.
+	Publish(ctx context.Context, subject string, payload []byte, headers map[string]string) error
+
+	// PublishAsync sends payload without blocking for acknowledgment,
+	// returning a handle the caller can wait on. headers is as in Publish.
+	PublishAsync(ctx context.Context, subject string, payload []byte, headers map[string]string) (AckHandle, error)
+
+	// StreamInfo reports backlog stats for the named stream/queue.
+	StreamInfo(name string) (*BrokerStreamInfo, error)
+
+	// Connected reports whether the broker currently has a live
+	// connection, for the health server's readiness probe.
+	Connected() bool
+
+	// Close releases the underlying connection.
+	Close() error
+}
+
+// newBroker constructs the MessageBroker selected by config.BrokerType,
+// defaulting to NATS for backward compatibility with existing deployments.
+func newBroker(config *Config) (MessageBroker, error) {
+	switch config.BrokerType {
+	case "", BrokerTypeNATS:
+		return newNATSBroker(config)
+	case BrokerTypeAMQP:
+		return newAMQPBroker(config)
+	default:
+		return nil, fmt.Errorf("unsupported broker type: %q", config.BrokerType)
+	}
+}
+
+// Broker type identifiers accepted by Config.BrokerType.
+const (
+	BrokerTypeNATS = "nats"
+	BrokerTypeAMQP = "amqp"
+)
@@ -0,0 +1,180 @@
+package publisher
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSBroker implements MessageBroker over NATS JetStream.
+type NATSBroker struct {
+	nc *nats.Conn
+	js nats.JetStreamContext
+}
+
+// newNATSBroker connects to NATS JetStream using config's URL, optional
+// mTLS bundle, and optional NKey/credentials/token authentication.
+func newNATSBroker(config *Config) (*NATSBroker, error) {
+	opts := []nats.Option{
+		nats.Timeout(config.ConnectTimeout),
+		nats.MaxReconnects(-1), // Unlimited reconnects
+		nats.ReconnectWait(2 * time.Second),
+		nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
+			if err != nil {
+				fmt.Printf("NATS disconnected: %v\n", err)
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			fmt.Printf("NATS reconnected to %s\n", nc.ConnectedUrl())
+		}),
+	}
+
+	if config.TLSEnabled {
+		tlsConfig, err := loadTLSConfig(config.TLSCertFile, config.TLSKeyFile, config.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("mTLS enabled but failed to load certificate bundle: %w", err)
+		}
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+
+	switch {
+	case config.CredsFile != "":
+		opts = append(opts, nats.UserCredentials(config.CredsFile))
+	case config.NKeySeedFile != "":
+		nkeyOpt, err := nats.NkeyOptionFromSeed(config.NKeySeedFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load NKey seed file: %w", err)
+		}
+		opts = append(opts, nkeyOpt)
+	case config.Token != "":
+		opts = append(opts, nats.Token(config.Token))
+	}
+
+	nc, err := nats.Connect(config.NATSUrl, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	return &NATSBroker{nc: nc, js: js}, nil
+}
+
+// Publish sends payload to subject and waits for the JetStream ack.
+func (b *NATSBroker) Publish(ctx context.Context, subject string, payload []byte, headers map[string]string) error {
+	_, err := b.js.PublishMsg(natsMsg(subject, payload, headers), nats.Context(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+	return nil
+}
+
+// natsMsg builds a *nats.Msg carrying headers, or a bare subject/payload
+// message when headers is empty (NATS headers require core server support
+// we don't want to assume unconditionally on the hot path).
+func natsMsg(subject string, payload []byte, headers map[string]string) *nats.Msg {
+	msg := &nats.Msg{Subject: subject, Data: payload}
+	if len(headers) == 0 {
+		return msg
+	}
+	msg.Header = nats.Header{}
+	for k, v := range headers {
+		msg.Header.Set(k, v)
+	}
+	return msg
+}
+
+// natsAckHandle adapts nats.PubAckFuture (whose Ok()/Err() are
+// <-chan *nats.PubAck / <-chan error) to the broker-agnostic AckHandle
+// (<-chan struct{} / <-chan error), draining the future in a background
+// goroutine and closing/forwarding onto its own channels.
+type natsAckHandle struct {
+	ok  chan struct{}
+	err chan error
+}
+
+func (h *natsAckHandle) Ok() <-chan struct{} {
+	return h.ok
+}
+
+func (h *natsAckHandle) Err() <-chan error {
+	return h.err
+}
+
+// watchNATSFuture waits for future to resolve and signals handle accordingly.
+func watchNATSFuture(future nats.PubAckFuture, handle *natsAckHandle) {
+	select {
+	case <-future.Ok():
+		close(handle.ok)
+	case err := <-future.Err():
+		handle.err <- err
+	}
+}
+
+// PublishAsync sends payload without blocking for acknowledgment.
+func (b *NATSBroker) PublishAsync(ctx context.Context, subject string, payload []byte, headers map[string]string) (AckHandle, error) {
+	future, err := b.js.PublishMsgAsync(natsMsg(subject, payload, headers), nats.Context(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+	handle := &natsAckHandle{ok: make(chan struct{}), err: make(chan error, 1)}
+	go watchNATSFuture(future, handle)
+	return handle, nil
+}
+
+// StreamInfo reports backlog stats for the named JetStream stream.
+func (b *NATSBroker) StreamInfo(name string) (*BrokerStreamInfo, error) {
+	info, err := b.js.StreamInfo(name)
+	if err != nil {
+		return nil, err
+	}
+	return &BrokerStreamInfo{Name: info.Config.Name, Messages: info.State.Msgs}, nil
+}
+
+// Close closes the NATS connection.
+func (b *NATSBroker) Close() error {
+	if b.nc != nil {
+		b.nc.Close()
+	}
+	return nil
+}
+
+// Connected reports whether the NATS connection is currently established.
+func (b *NATSBroker) Connected() bool {
+	return b.nc != nil && b.nc.IsConnected()
+}
+
+// loadTLSConfig builds a client TLS config for mTLS-secured broker
+// connections. All three files must load successfully; a partial or missing
+// bundle is an error rather than a silent fall-back to an insecure connection.
+func loadTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	caCert, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("failed to parse CA certificate")
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      caPool,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
@@ -0,0 +1,258 @@
+// Package publisher publishes detection events over a pluggable MessageBroker
+// (NATS JetStream by default, RabbitMQ via AMQPBroker) with JSON Schema
+// validation.
+// Reference: FR-001 (NATS messaging), FR-016 (JSON Schema validation), US1
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/codec"
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/tracing"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Publisher publishes detection events to the configured MessageBroker
+type Publisher struct {
+	broker           MessageBroker
+	codec            codec.Codec
+	endpointSchema   *gojsonschema.Schema
+	enableValidation bool
+	subject          string
+}
+
+// Config holds publisher configuration
+type Config struct {
+	// BrokerType selects the MessageBroker implementation: "nats" (default)
+	// or "amqp".
+	BrokerType string
+
+	// Codec selects the wire format for detection events: "json" (default),
+	// "protobuf", or "cbor". See pkg/codec.
+	Codec string
+
+	NATSUrl          string
+	Subject          string
+	SchemaPath       string
+	EnableValidation bool
+	ConnectTimeout   time.Duration
+	PublishTimeout   time.Duration
+
+	// TLSEnabled turns on mTLS for the broker connection. When true,
+	// TLSCertFile/TLSKeyFile/TLSCAFile must all be set and loadable, or
+	// NewPublisher fails rather than connecting insecurely.
+	TLSEnabled  bool
+	TLSCertFile string
+	TLSKeyFile  string
+	TLSCAFile   string
+
+	// NKeySeedFile, CredsFile, and Token are mutually exclusive NATS
+	// authentication methods; at most one should be set. Ignored for AMQP.
+	NKeySeedFile string // NKey seed file, used via nats.NkeyOptionFromSeed
+	CredsFile    string // NATS JWT + NKey credentials file
+	Token        string // Plain auth token
+
+	// AMQP-specific settings, used only when BrokerType is "amqp".
+	AMQPUrl        string
+	AMQPExchange   string
+	AMQPRoutingKey string // Falls back to Subject when empty
+	AMQPPersistent bool   // Sets delivery-mode 2 (persistent) instead of 1 (transient)
+}
+
+// NewPublisher creates a new Publisher backed by the broker selected in config.
+func NewPublisher(config *Config) (*Publisher, error) {
+	broker, err := newBroker(config)
+	if err != nil {
+		return nil, err
+	}
+
+	eventCodec, err := codec.Get(config.Codec)
+	if err != nil {
+		broker.Close()
+		return nil, fmt.Errorf("failed to select codec: %w", err)
+	}
+
+	publisher := &Publisher{
+		broker:           broker,
+		codec:            eventCodec,
+		enableValidation: config.EnableValidation,
+		subject:          config.Subject,
+	}
+
+	// Load and compile JSON Schema if validation is enabled. Schema
+	// validation only applies to the "json" codec; other wire formats skip
+	// it since JSON Schema cannot describe their encoding.
+	if config.EnableValidation && config.SchemaPath != "" && codec.SupportsSchemaValidation(eventCodec) {
+		if err := publisher.loadSchema(config.SchemaPath); err != nil {
+			broker.Close()
+			return nil, fmt.Errorf("failed to load schema: %w", err)
+		}
+	}
+
+	return publisher, nil
+}
+
+// loadSchema loads and compiles the JSON Schema
+func (p *Publisher) loadSchema(schemaPath string) error {
+	// Read schema file
+	schemaBytes, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	// Compile schema
+	schemaLoader := gojsonschema.NewStringLoader(string(schemaBytes))
+	schema, err := gojsonschema.NewSchema(schemaLoader)
+	if err != nil {
+		return fmt.Errorf("failed to compile schema: %w", err)
+	}
+
+	p.endpointSchema = schema
+	return nil
+}
+
+// PublishDetection publishes a detection event to the broker
+func (p *Publisher) PublishDetection(ctx context.Context, detection interface{}) error {
+	ctx, span := tracing.Tracer.Start(ctx, "Publisher.PublishDetection")
+	defer span.End()
+
+	if err := p.validateAgainstSchema(detection); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	payload, err := p.codec.Encode(detection)
+	if err != nil {
+		err = fmt.Errorf("failed to encode detection: %w", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	if err := p.broker.Publish(ctx, p.subject, payload, p.headers(ctx, detection)); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// headers builds the transport metadata sent alongside an encoded detection
+// so the correlator can pick the right decoder (event-codec), the Go type
+// that produced it (event-type), and continue ctx's trace: the W3C
+// traceparent/baggage propagator injects directly into this map, which the
+// broker sends as message headers.
+func (p *Publisher) headers(ctx context.Context, detection interface{}) map[string]string {
+	headers := map[string]string{
+		"event-codec": p.codec.Name(),
+		"event-type":  fmt.Sprintf("%T", detection),
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(headers))
+	return headers
+}
+
+// validateAgainstSchema checks detection against the loaded JSON Schema, if
+// validation is enabled and the selected codec supports it. Non-JSON codecs
+// (protobuf, cbor) skip validation since JSON Schema does not describe them.
+func (p *Publisher) validateAgainstSchema(detection interface{}) error {
+	if !p.enableValidation || p.endpointSchema == nil || !codec.SupportsSchemaValidation(p.codec) {
+		return nil
+	}
+
+	detectionJSON, err := (codec.JSONCodec{}).Encode(detection)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection: %w", err)
+	}
+
+	if err := p.validateEvent(detectionJSON); err != nil {
+		return fmt.Errorf("schema validation failed: %w", err)
+	}
+
+	return nil
+}
+
+// validateEvent validates event against JSON Schema
+func (p *Publisher) validateEvent(eventJSON []byte) error {
+	documentLoader := gojsonschema.NewStringLoader(string(eventJSON))
+	result, err := p.endpointSchema.Validate(documentLoader)
+	if err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	if !result.Valid() {
+		// Collect all validation errors
+		var errMessages []string
+		for _, desc := range result.Errors() {
+			errMessages = append(errMessages, desc.String())
+		}
+		return fmt.Errorf("validation failed: %v", errMessages)
+	}
+
+	return nil
+}
+
+// PublishBatch publishes multiple detections in a batch
+func (p *Publisher) PublishBatch(ctx context.Context, detections []interface{}) error {
+	for i, detection := range detections {
+		if err := p.PublishDetection(ctx, detection); err != nil {
+			return fmt.Errorf("failed to publish detection %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying broker connection
+func (p *Publisher) Close() error {
+	return p.broker.Close()
+}
+
+// Connected reports whether the underlying broker currently has a live
+// connection, for the health server's readiness probe.
+func (p *Publisher) Connected() bool {
+	return p.broker.Connected()
+}
+
+// StreamInfo returns backlog information about the named stream/queue
+func (p *Publisher) StreamInfo(streamName string) (*BrokerStreamInfo, error) {
+	return p.broker.StreamInfo(streamName)
+}
+
+// PublishDetectionWithAck publishes a detection and waits for the broker's
+// acknowledgment, failing if it does not arrive within timeout.
+func (p *Publisher) PublishDetectionWithAck(ctx context.Context, detection interface{}, timeout time.Duration) error {
+	if err := p.validateAgainstSchema(detection); err != nil {
+		return err
+	}
+
+	payload, err := p.codec.Encode(detection)
+	if err != nil {
+		return fmt.Errorf("failed to encode detection: %w", err)
+	}
+
+	// Publish with acknowledgment
+	ackHandle, err := p.broker.PublishAsync(ctx, p.subject, payload, p.headers(ctx, detection))
+	if err != nil {
+		return err
+	}
+
+	// Wait for ack with timeout
+	select {
+	case <-ackHandle.Ok():
+		return nil
+	case err := <-ackHandle.Err():
+		return fmt.Errorf("publish ack failed: %w", err)
+	case <-time.After(timeout):
+		return fmt.Errorf("publish ack timeout after %v", timeout)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
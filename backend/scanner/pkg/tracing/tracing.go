@@ -0,0 +1,129 @@
+// Package tracing initializes OpenTelemetry distributed tracing for the
+// scanner, so a scan cycle can be followed end to end: scan (this package's
+// root span) -> publish (pkg/publisher injects the span into NATS/AMQP
+// headers) -> correlate (backend/correlator continues the trace, see its
+// pkg/interceptor.Tracing and pkg/engine).
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+
+	"github.com/ozlabs/mcpeeker/backend/scanner/pkg/logging"
+)
+
+// TracerName is the OpenTelemetry instrumentation name scanner spans are
+// reported under.
+const TracerName = "github.com/ozlabs/mcpeeker/backend/scanner"
+
+// Tracer is the scanner's tracer, shared by cmd/scanner and pkg/publisher.
+// Before Init runs (or when tracing is disabled), otel's default no-op
+// implementation makes every Start call free.
+var Tracer = otel.Tracer(TracerName)
+
+// Config controls whether the scanner exports spans to an OTLP collector,
+// and how.
+type Config struct {
+	// Enabled turns on the OTLP exporter. When false, Init leaves the
+	// global no-op TracerProvider in place.
+	Enabled bool `yaml:"enabled"`
+
+	// Endpoint is the OTLP collector address, e.g. "otel-collector:4317"
+	// for grpc or "otel-collector:4318" for http.
+	Endpoint string `yaml:"endpoint"`
+
+	// Protocol selects the OTLP transport: "grpc" (default) or "http".
+	Protocol string `yaml:"protocol"`
+
+	// Insecure skips TLS for the OTLP connection, for local collectors.
+	Insecure bool `yaml:"insecure"`
+
+	// Headers are sent with every OTLP export request, e.g. for
+	// collector authentication.
+	Headers map[string]string `yaml:"headers"`
+
+	// SamplerRatio is the fraction of root spans sampled, 0.0-1.0.
+	// Defaults to 1.0 (always sample) when zero.
+	SamplerRatio float64 `yaml:"sampler_ratio"`
+}
+
+// Init configures the global TracerProvider and text-map propagator per
+// cfg. It returns a shutdown function that flushes and closes the
+// exporter; callers should defer it. When cfg.Enabled is false, Init only
+// sets the propagator, leaves the global no-op TracerProvider in place, and
+// returns a shutdown function that does nothing, so callers don't
+// need to branch on whether tracing is on.
+func Init(ctx context.Context, cfg Config, serviceName string, logger logging.Logger) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(
+		semconv.ServiceNameKey.String(serviceName),
+		semconv.ServiceVersionKey.String(""),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to build resource: %w", err)
+	}
+
+	ratio := cfg.SamplerRatio
+	if ratio <= 0 {
+		ratio = 1.0
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	Tracer = tp.Tracer(TracerName)
+
+	logger.Info("tracing initialized", "endpoint", cfg.Endpoint, "protocol", cfg.Protocol, "sampler_ratio", ratio)
+	return tp.Shutdown, nil
+}
+
+// newExporter builds the OTLP trace exporter for cfg.Protocol.
+func newExporter(ctx context.Context, cfg Config) (*otlptrace.Exporter, error) {
+	switch cfg.Protocol {
+	case "", "grpc":
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case "http":
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("tracing: unknown protocol %q, want \"grpc\" or \"http\"", cfg.Protocol)
+	}
+}